@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// SweepServiceClient is the client API for SweepService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SweepServiceClient interface {
+	// Creates a Sweep. It starts running the workflow template with the parameter values of its search space.
+	CreateSweep(ctx context.Context, in *CreateSweepRequest, opts ...grpc.CallOption) (*Sweep, error)
+	// Gets a Sweep, with its runs and best run
+	GetSweep(ctx context.Context, in *GetSweepRequest, opts ...grpc.CallOption) (*Sweep, error)
+	ListSweeps(ctx context.Context, in *ListSweepsRequest, opts ...grpc.CallOption) (*ListSweepsResponse, error)
+	// Terminates a Sweep and its running workflow executions
+	TerminateSweep(ctx context.Context, in *TerminateSweepRequest, opts ...grpc.CallOption) (*Sweep, error)
+}
+
+type sweepServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSweepServiceClient(cc grpc.ClientConnInterface) SweepServiceClient {
+	return &sweepServiceClient{cc}
+}
+
+func (c *sweepServiceClient) CreateSweep(ctx context.Context, in *CreateSweepRequest, opts ...grpc.CallOption) (*Sweep, error) {
+	out := new(Sweep)
+	err := c.cc.Invoke(ctx, "/api.SweepService/CreateSweep", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepServiceClient) GetSweep(ctx context.Context, in *GetSweepRequest, opts ...grpc.CallOption) (*Sweep, error) {
+	out := new(Sweep)
+	err := c.cc.Invoke(ctx, "/api.SweepService/GetSweep", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepServiceClient) ListSweeps(ctx context.Context, in *ListSweepsRequest, opts ...grpc.CallOption) (*ListSweepsResponse, error) {
+	out := new(ListSweepsResponse)
+	err := c.cc.Invoke(ctx, "/api.SweepService/ListSweeps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepServiceClient) TerminateSweep(ctx context.Context, in *TerminateSweepRequest, opts ...grpc.CallOption) (*Sweep, error) {
+	out := new(Sweep)
+	err := c.cc.Invoke(ctx, "/api.SweepService/TerminateSweep", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SweepServiceServer is the server API for SweepService service.
+// All implementations must embed UnimplementedSweepServiceServer
+// for forward compatibility
+type SweepServiceServer interface {
+	// Creates a Sweep. It starts running the workflow template with the parameter values of its search space.
+	CreateSweep(context.Context, *CreateSweepRequest) (*Sweep, error)
+	// Gets a Sweep, with its runs and best run
+	GetSweep(context.Context, *GetSweepRequest) (*Sweep, error)
+	ListSweeps(context.Context, *ListSweepsRequest) (*ListSweepsResponse, error)
+	// Terminates a Sweep and its running workflow executions
+	TerminateSweep(context.Context, *TerminateSweepRequest) (*Sweep, error)
+	mustEmbedUnimplementedSweepServiceServer()
+}
+
+// UnimplementedSweepServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSweepServiceServer struct {
+}
+
+func (UnimplementedSweepServiceServer) CreateSweep(context.Context, *CreateSweepRequest) (*Sweep, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSweep not implemented")
+}
+func (UnimplementedSweepServiceServer) GetSweep(context.Context, *GetSweepRequest) (*Sweep, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSweep not implemented")
+}
+func (UnimplementedSweepServiceServer) ListSweeps(context.Context, *ListSweepsRequest) (*ListSweepsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSweeps not implemented")
+}
+func (UnimplementedSweepServiceServer) TerminateSweep(context.Context, *TerminateSweepRequest) (*Sweep, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TerminateSweep not implemented")
+}
+func (UnimplementedSweepServiceServer) mustEmbedUnimplementedSweepServiceServer() {}
+
+// UnsafeSweepServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SweepServiceServer will
+// result in compilation errors.
+type UnsafeSweepServiceServer interface {
+	mustEmbedUnimplementedSweepServiceServer()
+}
+
+func RegisterSweepServiceServer(s grpc.ServiceRegistrar, srv SweepServiceServer) {
+	s.RegisterService(&_SweepService_serviceDesc, srv)
+}
+
+func _SweepService_CreateSweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepServiceServer).CreateSweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SweepService/CreateSweep",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepServiceServer).CreateSweep(ctx, req.(*CreateSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepService_GetSweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepServiceServer).GetSweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SweepService/GetSweep",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepServiceServer).GetSweep(ctx, req.(*GetSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepService_ListSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSweepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepServiceServer).ListSweeps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SweepService/ListSweeps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepServiceServer).ListSweeps(ctx, req.(*ListSweepsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepService_TerminateSweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TerminateSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepServiceServer).TerminateSweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SweepService/TerminateSweep",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepServiceServer).TerminateSweep(ctx, req.(*TerminateSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SweepService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.SweepService",
+	HandlerType: (*SweepServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSweep",
+			Handler:    _SweepService_CreateSweep_Handler,
+		},
+		{
+			MethodName: "GetSweep",
+			Handler:    _SweepService_GetSweep_Handler,
+		},
+		{
+			MethodName: "ListSweeps",
+			Handler:    _SweepService_ListSweeps_Handler,
+		},
+		{
+			MethodName: "TerminateSweep",
+			Handler:    _SweepService_TerminateSweep_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sweep.proto",
+}
@@ -25,18 +25,62 @@ type WorkflowServiceClient interface {
 	GetWorkflowExecutionStatisticsForNamespace(ctx context.Context, in *GetWorkflowExecutionStatisticsForNamespaceRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionStatisticsForNamespaceResponse, error)
 	GetWorkflowExecution(ctx context.Context, in *GetWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error)
 	ListWorkflowExecutions(ctx context.Context, in *ListWorkflowExecutionsRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionsResponse, error)
+	// Compares workflow executions side-by-side, with their parameters, metrics and labels aligned by name
+	CompareWorkflowExecutions(ctx context.Context, in *CompareWorkflowExecutionsRequest, opts ...grpc.CallOption) (*CompareWorkflowExecutionsResponse, error)
 	WatchWorkflowExecution(ctx context.Context, in *WatchWorkflowExecutionRequest, opts ...grpc.CallOption) (WorkflowService_WatchWorkflowExecutionClient, error)
+	// Watches the workflow executions in a namespace for creation, phase changes and deletion. The stream fails with OUT_OF_RANGE if resourceVersion is too old to resume from
+	WatchWorkflowExecutions(ctx context.Context, in *WatchWorkflowExecutionsRequest, opts ...grpc.CallOption) (WorkflowService_WatchWorkflowExecutionsClient, error)
 	GetWorkflowExecutionLogs(ctx context.Context, in *GetWorkflowExecutionLogsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowExecutionLogsClient, error)
+	GetMergedWorkflowExecutionLogs(ctx context.Context, in *GetMergedWorkflowExecutionLogsRequest, opts ...grpc.CallOption) (WorkflowService_GetMergedWorkflowExecutionLogsClient, error)
 	GetWorkflowExecutionMetrics(ctx context.Context, in *GetWorkflowExecutionMetricsRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionMetricsResponse, error)
+	// Lists the nodes (steps) of a workflow execution and the edges between them
+	ListWorkflowExecutionNodes(ctx context.Context, in *ListWorkflowExecutionNodesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionNodesResponse, error)
+	// Retries a failed workflow execution as a new workflow execution, optionally only for the failed nodes matching a selector
+	RetryWorkflowExecution(ctx context.Context, in *RetryWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error)
 	ResubmitWorkflowExecution(ctx context.Context, in *ResubmitWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error)
+	// Suspends a running workflow execution
+	SuspendWorkflowExecution(ctx context.Context, in *SuspendWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Resumes a suspended workflow execution, optionally only the suspend nodes matching a selector
+	ResumeWorkflowExecution(ctx context.Context, in *ResumeWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error)
+	// Lists the suspend nodes of a workflow execution that are waiting to be resumed
+	ListWorkflowExecutionSuspendNodes(ctx context.Context, in *ListWorkflowExecutionSuspendNodesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionSuspendNodesResponse, error)
 	TerminateWorkflowExecution(ctx context.Context, in *TerminateWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Lists the workflow executions of a namespace that wait for its concurrency limits, in the order they start
+	ListQueuedWorkflowExecutions(ctx context.Context, in *ListQueuedWorkflowExecutionsRequest, opts ...grpc.CallOption) (*ListQueuedWorkflowExecutionsResponse, error)
+	// Moves a queued workflow execution to a position in the queue of its namespace. Positions start at 1.
+	MoveQueuedWorkflowExecution(ctx context.Context, in *MoveQueuedWorkflowExecutionRequest, opts ...grpc.CallOption) (*ListQueuedWorkflowExecutionsResponse, error)
+	// Removes a workflow execution from the queue before it starts
+	CancelQueuedWorkflowExecution(ctx context.Context, in *CancelQueuedWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Lists the finished workflow executions that the retention policies of the namespace would delete now,
+	// or whose artifacts they would delete. Nothing is deleted.
+	GetWorkflowExecutionRetentionReport(ctx context.Context, in *GetWorkflowExecutionRetentionReportRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionRetentionReportResponse, error)
+	// Terminates, archives, resubmits or labels the workflow executions selected by uids, or by workflow template, labels, phase and filter.
+	// Each workflow execution has its own result, so failures don't stop the others.
+	BulkWorkflowExecutions(ctx context.Context, in *BulkWorkflowExecutionsRequest, opts ...grpc.CallOption) (*BulkWorkflowExecutionsResponse, error)
+	// Returns the artifacts a workflow execution read and wrote, and the workflow executions connected to it by artifacts:
+	// upstream the ones that wrote what it read, downstream the ones that read what it wrote
+	GetWorkflowExecutionLineage(ctx context.Context, in *GetWorkflowExecutionLineageRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionLineageResponse, error)
+	// Lists the workflow executions that wrote or read an artifact key, or the files inside it
+	ListArtifactLineage(ctx context.Context, in *ListArtifactLineageRequest, opts ...grpc.CallOption) (*ListArtifactLineageResponse, error)
 	GetArtifact(ctx context.Context, in *GetArtifactRequest, opts ...grpc.CallOption) (*ArtifactResponse, error)
+	// Gets a time-limited pre-signed URL that can be used to download an artifact directly from the artifact repository
+	GetArtifactURL(ctx context.Context, in *GetArtifactURLRequest, opts ...grpc.CallOption) (*GetArtifactURLResponse, error)
+	// Streams an artifact in chunks, starting at offset and reading length bytes.
+	// Over HTTP, this is served by the artifact download handler, which supports Range requests.
+	DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (WorkflowService_DownloadArtifactClient, error)
+	// Uploads a file into the artifact repository of the namespace. The first message has the information of the file,
+	// the data is sent in chunks. Over HTTP, this is served by the artifact upload handler as a multipart form upload.
+	UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_UploadArtifactClient, error)
 	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
 	AddWorkflowExecutionStatistics(ctx context.Context, in *AddWorkflowExecutionStatisticRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	CronStartWorkflowExecutionStatistic(ctx context.Context, in *CronStartWorkflowExecutionStatisticRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UpdateWorkflowExecutionStatus(ctx context.Context, in *UpdateWorkflowExecutionStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	AddWorkflowExecutionMetrics(ctx context.Context, in *AddWorkflowExecutionsMetricsRequest, opts ...grpc.CallOption) (*WorkflowExecutionsMetricsResponse, error)
 	UpdateWorkflowExecutionMetrics(ctx context.Context, in *UpdateWorkflowExecutionsMetricsRequest, opts ...grpc.CallOption) (*WorkflowExecutionsMetricsResponse, error)
+	// Adds a batch of metric points, like the loss at each step of a training, to a workflow execution
+	AddWorkflowExecutionMetricPoints(ctx context.Context, in *AddWorkflowExecutionMetricPointsRequest, opts ...grpc.CallOption) (*WorkflowExecutionsMetricsResponse, error)
+	// Lists the series of metric points of a workflow execution, downsampled to at most maxPoints points each
+	ListWorkflowExecutionMetricSeries(ctx context.Context, in *ListWorkflowExecutionMetricSeriesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionMetricSeriesResponse, error)
 }
 
 type workflowServiceClient struct {
@@ -92,6 +136,15 @@ func (c *workflowServiceClient) ListWorkflowExecutions(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *workflowServiceClient) CompareWorkflowExecutions(ctx context.Context, in *CompareWorkflowExecutionsRequest, opts ...grpc.CallOption) (*CompareWorkflowExecutionsResponse, error) {
+	out := new(CompareWorkflowExecutionsResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/CompareWorkflowExecutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) WatchWorkflowExecution(ctx context.Context, in *WatchWorkflowExecutionRequest, opts ...grpc.CallOption) (WorkflowService_WatchWorkflowExecutionClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[0], "/api.WorkflowService/WatchWorkflowExecution", opts...)
 	if err != nil {
@@ -124,8 +177,40 @@ func (x *workflowServiceWatchWorkflowExecutionClient) Recv() (*WorkflowExecution
 	return m, nil
 }
 
+func (c *workflowServiceClient) WatchWorkflowExecutions(ctx context.Context, in *WatchWorkflowExecutionsRequest, opts ...grpc.CallOption) (WorkflowService_WatchWorkflowExecutionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[1], "/api.WorkflowService/WatchWorkflowExecutions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceWatchWorkflowExecutionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkflowService_WatchWorkflowExecutionsClient interface {
+	Recv() (*WorkflowExecutionEvent, error)
+	grpc.ClientStream
+}
+
+type workflowServiceWatchWorkflowExecutionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceWatchWorkflowExecutionsClient) Recv() (*WorkflowExecutionEvent, error) {
+	m := new(WorkflowExecutionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *workflowServiceClient) GetWorkflowExecutionLogs(ctx context.Context, in *GetWorkflowExecutionLogsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowExecutionLogsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[1], "/api.WorkflowService/GetWorkflowExecutionLogs", opts...)
+	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[2], "/api.WorkflowService/GetWorkflowExecutionLogs", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +241,38 @@ func (x *workflowServiceGetWorkflowExecutionLogsClient) Recv() (*LogStreamRespon
 	return m, nil
 }
 
+func (c *workflowServiceClient) GetMergedWorkflowExecutionLogs(ctx context.Context, in *GetMergedWorkflowExecutionLogsRequest, opts ...grpc.CallOption) (WorkflowService_GetMergedWorkflowExecutionLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[3], "/api.WorkflowService/GetMergedWorkflowExecutionLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceGetMergedWorkflowExecutionLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkflowService_GetMergedWorkflowExecutionLogsClient interface {
+	Recv() (*LogStreamResponse, error)
+	grpc.ClientStream
+}
+
+type workflowServiceGetMergedWorkflowExecutionLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceGetMergedWorkflowExecutionLogsClient) Recv() (*LogStreamResponse, error) {
+	m := new(LogStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *workflowServiceClient) GetWorkflowExecutionMetrics(ctx context.Context, in *GetWorkflowExecutionMetricsRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionMetricsResponse, error) {
 	out := new(GetWorkflowExecutionMetricsResponse)
 	err := c.cc.Invoke(ctx, "/api.WorkflowService/GetWorkflowExecutionMetrics", in, out, opts...)
@@ -165,6 +282,24 @@ func (c *workflowServiceClient) GetWorkflowExecutionMetrics(ctx context.Context,
 	return out, nil
 }
 
+func (c *workflowServiceClient) ListWorkflowExecutionNodes(ctx context.Context, in *ListWorkflowExecutionNodesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionNodesResponse, error) {
+	out := new(ListWorkflowExecutionNodesResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListWorkflowExecutionNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) RetryWorkflowExecution(ctx context.Context, in *RetryWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error) {
+	out := new(WorkflowExecution)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/RetryWorkflowExecution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) ResubmitWorkflowExecution(ctx context.Context, in *ResubmitWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error) {
 	out := new(WorkflowExecution)
 	err := c.cc.Invoke(ctx, "/api.WorkflowService/ResubmitWorkflowExecution", in, out, opts...)
@@ -174,6 +309,33 @@ func (c *workflowServiceClient) ResubmitWorkflowExecution(ctx context.Context, i
 	return out, nil
 }
 
+func (c *workflowServiceClient) SuspendWorkflowExecution(ctx context.Context, in *SuspendWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/SuspendWorkflowExecution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ResumeWorkflowExecution(ctx context.Context, in *ResumeWorkflowExecutionRequest, opts ...grpc.CallOption) (*WorkflowExecution, error) {
+	out := new(WorkflowExecution)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ResumeWorkflowExecution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListWorkflowExecutionSuspendNodes(ctx context.Context, in *ListWorkflowExecutionSuspendNodesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionSuspendNodesResponse, error) {
+	out := new(ListWorkflowExecutionSuspendNodesResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListWorkflowExecutionSuspendNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) TerminateWorkflowExecution(ctx context.Context, in *TerminateWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/api.WorkflowService/TerminateWorkflowExecution", in, out, opts...)
@@ -183,6 +345,69 @@ func (c *workflowServiceClient) TerminateWorkflowExecution(ctx context.Context,
 	return out, nil
 }
 
+func (c *workflowServiceClient) ListQueuedWorkflowExecutions(ctx context.Context, in *ListQueuedWorkflowExecutionsRequest, opts ...grpc.CallOption) (*ListQueuedWorkflowExecutionsResponse, error) {
+	out := new(ListQueuedWorkflowExecutionsResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListQueuedWorkflowExecutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) MoveQueuedWorkflowExecution(ctx context.Context, in *MoveQueuedWorkflowExecutionRequest, opts ...grpc.CallOption) (*ListQueuedWorkflowExecutionsResponse, error) {
+	out := new(ListQueuedWorkflowExecutionsResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/MoveQueuedWorkflowExecution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) CancelQueuedWorkflowExecution(ctx context.Context, in *CancelQueuedWorkflowExecutionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/CancelQueuedWorkflowExecution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) GetWorkflowExecutionRetentionReport(ctx context.Context, in *GetWorkflowExecutionRetentionReportRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionRetentionReportResponse, error) {
+	out := new(GetWorkflowExecutionRetentionReportResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/GetWorkflowExecutionRetentionReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) BulkWorkflowExecutions(ctx context.Context, in *BulkWorkflowExecutionsRequest, opts ...grpc.CallOption) (*BulkWorkflowExecutionsResponse, error) {
+	out := new(BulkWorkflowExecutionsResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/BulkWorkflowExecutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) GetWorkflowExecutionLineage(ctx context.Context, in *GetWorkflowExecutionLineageRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionLineageResponse, error) {
+	out := new(GetWorkflowExecutionLineageResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/GetWorkflowExecutionLineage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListArtifactLineage(ctx context.Context, in *ListArtifactLineageRequest, opts ...grpc.CallOption) (*ListArtifactLineageResponse, error) {
+	out := new(ListArtifactLineageResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListArtifactLineage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) GetArtifact(ctx context.Context, in *GetArtifactRequest, opts ...grpc.CallOption) (*ArtifactResponse, error) {
 	out := new(ArtifactResponse)
 	err := c.cc.Invoke(ctx, "/api.WorkflowService/GetArtifact", in, out, opts...)
@@ -192,6 +417,81 @@ func (c *workflowServiceClient) GetArtifact(ctx context.Context, in *GetArtifact
 	return out, nil
 }
 
+func (c *workflowServiceClient) GetArtifactURL(ctx context.Context, in *GetArtifactURLRequest, opts ...grpc.CallOption) (*GetArtifactURLResponse, error) {
+	out := new(GetArtifactURLResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/GetArtifactURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (WorkflowService_DownloadArtifactClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[4], "/api.WorkflowService/DownloadArtifact", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceDownloadArtifactClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkflowService_DownloadArtifactClient interface {
+	Recv() (*ArtifactChunk, error)
+	grpc.ClientStream
+}
+
+type workflowServiceDownloadArtifactClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceDownloadArtifactClient) Recv() (*ArtifactChunk, error) {
+	m := new(ArtifactChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowServiceClient) UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_UploadArtifactClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkflowService_serviceDesc.Streams[5], "/api.WorkflowService/UploadArtifact", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceUploadArtifactClient{stream}
+	return x, nil
+}
+
+type WorkflowService_UploadArtifactClient interface {
+	Send(*UploadArtifactRequest) error
+	CloseAndRecv() (*UploadArtifactResponse, error)
+	grpc.ClientStream
+}
+
+type workflowServiceUploadArtifactClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceUploadArtifactClient) Send(m *UploadArtifactRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workflowServiceUploadArtifactClient) CloseAndRecv() (*UploadArtifactResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadArtifactResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *workflowServiceClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
 	out := new(ListFilesResponse)
 	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListFiles", in, out, opts...)
@@ -246,6 +546,24 @@ func (c *workflowServiceClient) UpdateWorkflowExecutionMetrics(ctx context.Conte
 	return out, nil
 }
 
+func (c *workflowServiceClient) AddWorkflowExecutionMetricPoints(ctx context.Context, in *AddWorkflowExecutionMetricPointsRequest, opts ...grpc.CallOption) (*WorkflowExecutionsMetricsResponse, error) {
+	out := new(WorkflowExecutionsMetricsResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/AddWorkflowExecutionMetricPoints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListWorkflowExecutionMetricSeries(ctx context.Context, in *ListWorkflowExecutionMetricSeriesRequest, opts ...grpc.CallOption) (*ListWorkflowExecutionMetricSeriesResponse, error) {
+	out := new(ListWorkflowExecutionMetricSeriesResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowService/ListWorkflowExecutionMetricSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations must embed UnimplementedWorkflowServiceServer
 // for forward compatibility
@@ -257,18 +575,62 @@ type WorkflowServiceServer interface {
 	GetWorkflowExecutionStatisticsForNamespace(context.Context, *GetWorkflowExecutionStatisticsForNamespaceRequest) (*GetWorkflowExecutionStatisticsForNamespaceResponse, error)
 	GetWorkflowExecution(context.Context, *GetWorkflowExecutionRequest) (*WorkflowExecution, error)
 	ListWorkflowExecutions(context.Context, *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error)
+	// Compares workflow executions side-by-side, with their parameters, metrics and labels aligned by name
+	CompareWorkflowExecutions(context.Context, *CompareWorkflowExecutionsRequest) (*CompareWorkflowExecutionsResponse, error)
 	WatchWorkflowExecution(*WatchWorkflowExecutionRequest, WorkflowService_WatchWorkflowExecutionServer) error
+	// Watches the workflow executions in a namespace for creation, phase changes and deletion. The stream fails with OUT_OF_RANGE if resourceVersion is too old to resume from
+	WatchWorkflowExecutions(*WatchWorkflowExecutionsRequest, WorkflowService_WatchWorkflowExecutionsServer) error
 	GetWorkflowExecutionLogs(*GetWorkflowExecutionLogsRequest, WorkflowService_GetWorkflowExecutionLogsServer) error
+	GetMergedWorkflowExecutionLogs(*GetMergedWorkflowExecutionLogsRequest, WorkflowService_GetMergedWorkflowExecutionLogsServer) error
 	GetWorkflowExecutionMetrics(context.Context, *GetWorkflowExecutionMetricsRequest) (*GetWorkflowExecutionMetricsResponse, error)
+	// Lists the nodes (steps) of a workflow execution and the edges between them
+	ListWorkflowExecutionNodes(context.Context, *ListWorkflowExecutionNodesRequest) (*ListWorkflowExecutionNodesResponse, error)
+	// Retries a failed workflow execution as a new workflow execution, optionally only for the failed nodes matching a selector
+	RetryWorkflowExecution(context.Context, *RetryWorkflowExecutionRequest) (*WorkflowExecution, error)
 	ResubmitWorkflowExecution(context.Context, *ResubmitWorkflowExecutionRequest) (*WorkflowExecution, error)
+	// Suspends a running workflow execution
+	SuspendWorkflowExecution(context.Context, *SuspendWorkflowExecutionRequest) (*emptypb.Empty, error)
+	// Resumes a suspended workflow execution, optionally only the suspend nodes matching a selector
+	ResumeWorkflowExecution(context.Context, *ResumeWorkflowExecutionRequest) (*WorkflowExecution, error)
+	// Lists the suspend nodes of a workflow execution that are waiting to be resumed
+	ListWorkflowExecutionSuspendNodes(context.Context, *ListWorkflowExecutionSuspendNodesRequest) (*ListWorkflowExecutionSuspendNodesResponse, error)
 	TerminateWorkflowExecution(context.Context, *TerminateWorkflowExecutionRequest) (*emptypb.Empty, error)
+	// Lists the workflow executions of a namespace that wait for its concurrency limits, in the order they start
+	ListQueuedWorkflowExecutions(context.Context, *ListQueuedWorkflowExecutionsRequest) (*ListQueuedWorkflowExecutionsResponse, error)
+	// Moves a queued workflow execution to a position in the queue of its namespace. Positions start at 1.
+	MoveQueuedWorkflowExecution(context.Context, *MoveQueuedWorkflowExecutionRequest) (*ListQueuedWorkflowExecutionsResponse, error)
+	// Removes a workflow execution from the queue before it starts
+	CancelQueuedWorkflowExecution(context.Context, *CancelQueuedWorkflowExecutionRequest) (*emptypb.Empty, error)
+	// Lists the finished workflow executions that the retention policies of the namespace would delete now,
+	// or whose artifacts they would delete. Nothing is deleted.
+	GetWorkflowExecutionRetentionReport(context.Context, *GetWorkflowExecutionRetentionReportRequest) (*GetWorkflowExecutionRetentionReportResponse, error)
+	// Terminates, archives, resubmits or labels the workflow executions selected by uids, or by workflow template, labels, phase and filter.
+	// Each workflow execution has its own result, so failures don't stop the others.
+	BulkWorkflowExecutions(context.Context, *BulkWorkflowExecutionsRequest) (*BulkWorkflowExecutionsResponse, error)
+	// Returns the artifacts a workflow execution read and wrote, and the workflow executions connected to it by artifacts:
+	// upstream the ones that wrote what it read, downstream the ones that read what it wrote
+	GetWorkflowExecutionLineage(context.Context, *GetWorkflowExecutionLineageRequest) (*GetWorkflowExecutionLineageResponse, error)
+	// Lists the workflow executions that wrote or read an artifact key, or the files inside it
+	ListArtifactLineage(context.Context, *ListArtifactLineageRequest) (*ListArtifactLineageResponse, error)
 	GetArtifact(context.Context, *GetArtifactRequest) (*ArtifactResponse, error)
+	// Gets a time-limited pre-signed URL that can be used to download an artifact directly from the artifact repository
+	GetArtifactURL(context.Context, *GetArtifactURLRequest) (*GetArtifactURLResponse, error)
+	// Streams an artifact in chunks, starting at offset and reading length bytes.
+	// Over HTTP, this is served by the artifact download handler, which supports Range requests.
+	DownloadArtifact(*DownloadArtifactRequest, WorkflowService_DownloadArtifactServer) error
+	// Uploads a file into the artifact repository of the namespace. The first message has the information of the file,
+	// the data is sent in chunks. Over HTTP, this is served by the artifact upload handler as a multipart form upload.
+	UploadArtifact(WorkflowService_UploadArtifactServer) error
 	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
 	AddWorkflowExecutionStatistics(context.Context, *AddWorkflowExecutionStatisticRequest) (*emptypb.Empty, error)
 	CronStartWorkflowExecutionStatistic(context.Context, *CronStartWorkflowExecutionStatisticRequest) (*emptypb.Empty, error)
 	UpdateWorkflowExecutionStatus(context.Context, *UpdateWorkflowExecutionStatusRequest) (*emptypb.Empty, error)
 	AddWorkflowExecutionMetrics(context.Context, *AddWorkflowExecutionsMetricsRequest) (*WorkflowExecutionsMetricsResponse, error)
 	UpdateWorkflowExecutionMetrics(context.Context, *UpdateWorkflowExecutionsMetricsRequest) (*WorkflowExecutionsMetricsResponse, error)
+	// Adds a batch of metric points, like the loss at each step of a training, to a workflow execution
+	AddWorkflowExecutionMetricPoints(context.Context, *AddWorkflowExecutionMetricPointsRequest) (*WorkflowExecutionsMetricsResponse, error)
+	// Lists the series of metric points of a workflow execution, downsampled to at most maxPoints points each
+	ListWorkflowExecutionMetricSeries(context.Context, *ListWorkflowExecutionMetricSeriesRequest) (*ListWorkflowExecutionMetricSeriesResponse, error)
 	mustEmbedUnimplementedWorkflowServiceServer()
 }
 
@@ -291,24 +653,78 @@ func (UnimplementedWorkflowServiceServer) GetWorkflowExecution(context.Context,
 func (UnimplementedWorkflowServiceServer) ListWorkflowExecutions(context.Context, *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowExecutions not implemented")
 }
+func (UnimplementedWorkflowServiceServer) CompareWorkflowExecutions(context.Context, *CompareWorkflowExecutionsRequest) (*CompareWorkflowExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareWorkflowExecutions not implemented")
+}
 func (UnimplementedWorkflowServiceServer) WatchWorkflowExecution(*WatchWorkflowExecutionRequest, WorkflowService_WatchWorkflowExecutionServer) error {
 	return status.Errorf(codes.Unimplemented, "method WatchWorkflowExecution not implemented")
 }
+func (UnimplementedWorkflowServiceServer) WatchWorkflowExecutions(*WatchWorkflowExecutionsRequest, WorkflowService_WatchWorkflowExecutionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchWorkflowExecutions not implemented")
+}
 func (UnimplementedWorkflowServiceServer) GetWorkflowExecutionLogs(*GetWorkflowExecutionLogsRequest, WorkflowService_GetWorkflowExecutionLogsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetWorkflowExecutionLogs not implemented")
 }
+func (UnimplementedWorkflowServiceServer) GetMergedWorkflowExecutionLogs(*GetMergedWorkflowExecutionLogsRequest, WorkflowService_GetMergedWorkflowExecutionLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMergedWorkflowExecutionLogs not implemented")
+}
 func (UnimplementedWorkflowServiceServer) GetWorkflowExecutionMetrics(context.Context, *GetWorkflowExecutionMetricsRequest) (*GetWorkflowExecutionMetricsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowExecutionMetrics not implemented")
 }
+func (UnimplementedWorkflowServiceServer) ListWorkflowExecutionNodes(context.Context, *ListWorkflowExecutionNodesRequest) (*ListWorkflowExecutionNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowExecutionNodes not implemented")
+}
+func (UnimplementedWorkflowServiceServer) RetryWorkflowExecution(context.Context, *RetryWorkflowExecutionRequest) (*WorkflowExecution, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryWorkflowExecution not implemented")
+}
 func (UnimplementedWorkflowServiceServer) ResubmitWorkflowExecution(context.Context, *ResubmitWorkflowExecutionRequest) (*WorkflowExecution, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResubmitWorkflowExecution not implemented")
 }
+func (UnimplementedWorkflowServiceServer) SuspendWorkflowExecution(context.Context, *SuspendWorkflowExecutionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendWorkflowExecution not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ResumeWorkflowExecution(context.Context, *ResumeWorkflowExecutionRequest) (*WorkflowExecution, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWorkflowExecution not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListWorkflowExecutionSuspendNodes(context.Context, *ListWorkflowExecutionSuspendNodesRequest) (*ListWorkflowExecutionSuspendNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowExecutionSuspendNodes not implemented")
+}
 func (UnimplementedWorkflowServiceServer) TerminateWorkflowExecution(context.Context, *TerminateWorkflowExecutionRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TerminateWorkflowExecution not implemented")
 }
+func (UnimplementedWorkflowServiceServer) ListQueuedWorkflowExecutions(context.Context, *ListQueuedWorkflowExecutionsRequest) (*ListQueuedWorkflowExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQueuedWorkflowExecutions not implemented")
+}
+func (UnimplementedWorkflowServiceServer) MoveQueuedWorkflowExecution(context.Context, *MoveQueuedWorkflowExecutionRequest) (*ListQueuedWorkflowExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveQueuedWorkflowExecution not implemented")
+}
+func (UnimplementedWorkflowServiceServer) CancelQueuedWorkflowExecution(context.Context, *CancelQueuedWorkflowExecutionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelQueuedWorkflowExecution not implemented")
+}
+func (UnimplementedWorkflowServiceServer) GetWorkflowExecutionRetentionReport(context.Context, *GetWorkflowExecutionRetentionReportRequest) (*GetWorkflowExecutionRetentionReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowExecutionRetentionReport not implemented")
+}
+func (UnimplementedWorkflowServiceServer) BulkWorkflowExecutions(context.Context, *BulkWorkflowExecutionsRequest) (*BulkWorkflowExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkWorkflowExecutions not implemented")
+}
+func (UnimplementedWorkflowServiceServer) GetWorkflowExecutionLineage(context.Context, *GetWorkflowExecutionLineageRequest) (*GetWorkflowExecutionLineageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowExecutionLineage not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListArtifactLineage(context.Context, *ListArtifactLineageRequest) (*ListArtifactLineageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtifactLineage not implemented")
+}
 func (UnimplementedWorkflowServiceServer) GetArtifact(context.Context, *GetArtifactRequest) (*ArtifactResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetArtifact not implemented")
 }
+func (UnimplementedWorkflowServiceServer) GetArtifactURL(context.Context, *GetArtifactURLRequest) (*GetArtifactURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifactURL not implemented")
+}
+func (UnimplementedWorkflowServiceServer) DownloadArtifact(*DownloadArtifactRequest, WorkflowService_DownloadArtifactServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadArtifact not implemented")
+}
+func (UnimplementedWorkflowServiceServer) UploadArtifact(WorkflowService_UploadArtifactServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadArtifact not implemented")
+}
 func (UnimplementedWorkflowServiceServer) ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListFiles not implemented")
 }
@@ -327,6 +743,12 @@ func (UnimplementedWorkflowServiceServer) AddWorkflowExecutionMetrics(context.Co
 func (UnimplementedWorkflowServiceServer) UpdateWorkflowExecutionMetrics(context.Context, *UpdateWorkflowExecutionsMetricsRequest) (*WorkflowExecutionsMetricsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateWorkflowExecutionMetrics not implemented")
 }
+func (UnimplementedWorkflowServiceServer) AddWorkflowExecutionMetricPoints(context.Context, *AddWorkflowExecutionMetricPointsRequest) (*WorkflowExecutionsMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWorkflowExecutionMetricPoints not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListWorkflowExecutionMetricSeries(context.Context, *ListWorkflowExecutionMetricSeriesRequest) (*ListWorkflowExecutionMetricSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowExecutionMetricSeries not implemented")
+}
 func (UnimplementedWorkflowServiceServer) mustEmbedUnimplementedWorkflowServiceServer() {}
 
 // UnsafeWorkflowServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -430,6 +852,24 @@ func _WorkflowService_ListWorkflowExecutions_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_CompareWorkflowExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareWorkflowExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).CompareWorkflowExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/CompareWorkflowExecutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).CompareWorkflowExecutions(ctx, req.(*CompareWorkflowExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_WatchWorkflowExecution_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchWorkflowExecutionRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -451,6 +891,27 @@ func (x *workflowServiceWatchWorkflowExecutionServer) Send(m *WorkflowExecution)
 	return x.ServerStream.SendMsg(m)
 }
 
+func _WorkflowService_WatchWorkflowExecutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchWorkflowExecutionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServiceServer).WatchWorkflowExecutions(m, &workflowServiceWatchWorkflowExecutionsServer{stream})
+}
+
+type WorkflowService_WatchWorkflowExecutionsServer interface {
+	Send(*WorkflowExecutionEvent) error
+	grpc.ServerStream
+}
+
+type workflowServiceWatchWorkflowExecutionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceWatchWorkflowExecutionsServer) Send(m *WorkflowExecutionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WorkflowService_GetWorkflowExecutionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(GetWorkflowExecutionLogsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -472,16 +933,37 @@ func (x *workflowServiceGetWorkflowExecutionLogsServer) Send(m *LogStreamRespons
 	return x.ServerStream.SendMsg(m)
 }
 
-func _WorkflowService_GetWorkflowExecutionMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetWorkflowExecutionMetricsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(WorkflowServiceServer).GetWorkflowExecutionMetrics(ctx, in)
+func _WorkflowService_GetMergedWorkflowExecutionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetMergedWorkflowExecutionLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
+	return srv.(WorkflowServiceServer).GetMergedWorkflowExecutionLogs(m, &workflowServiceGetMergedWorkflowExecutionLogsServer{stream})
+}
+
+type WorkflowService_GetMergedWorkflowExecutionLogsServer interface {
+	Send(*LogStreamResponse) error
+	grpc.ServerStream
+}
+
+type workflowServiceGetMergedWorkflowExecutionLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceGetMergedWorkflowExecutionLogsServer) Send(m *LogStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkflowService_GetWorkflowExecutionMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowExecutionMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetWorkflowExecutionMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
 		FullMethod: "/api.WorkflowService/GetWorkflowExecutionMetrics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
@@ -490,6 +972,42 @@ func _WorkflowService_GetWorkflowExecutionMetrics_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_ListWorkflowExecutionNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowExecutionNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ListWorkflowExecutionNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionNodes(ctx, req.(*ListWorkflowExecutionNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_RetryWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryWorkflowExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).RetryWorkflowExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/RetryWorkflowExecution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).RetryWorkflowExecution(ctx, req.(*RetryWorkflowExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_ResubmitWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResubmitWorkflowExecutionRequest)
 	if err := dec(in); err != nil {
@@ -508,6 +1026,60 @@ func _WorkflowService_ResubmitWorkflowExecution_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_SuspendWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendWorkflowExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).SuspendWorkflowExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/SuspendWorkflowExecution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).SuspendWorkflowExecution(ctx, req.(*SuspendWorkflowExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ResumeWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeWorkflowExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ResumeWorkflowExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ResumeWorkflowExecution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ResumeWorkflowExecution(ctx, req.(*ResumeWorkflowExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListWorkflowExecutionSuspendNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowExecutionSuspendNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionSuspendNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ListWorkflowExecutionSuspendNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionSuspendNodes(ctx, req.(*ListWorkflowExecutionSuspendNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_TerminateWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TerminateWorkflowExecutionRequest)
 	if err := dec(in); err != nil {
@@ -526,6 +1098,132 @@ func _WorkflowService_TerminateWorkflowExecution_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_ListQueuedWorkflowExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQueuedWorkflowExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListQueuedWorkflowExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ListQueuedWorkflowExecutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListQueuedWorkflowExecutions(ctx, req.(*ListQueuedWorkflowExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_MoveQueuedWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveQueuedWorkflowExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).MoveQueuedWorkflowExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/MoveQueuedWorkflowExecution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).MoveQueuedWorkflowExecution(ctx, req.(*MoveQueuedWorkflowExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_CancelQueuedWorkflowExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelQueuedWorkflowExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).CancelQueuedWorkflowExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/CancelQueuedWorkflowExecution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).CancelQueuedWorkflowExecution(ctx, req.(*CancelQueuedWorkflowExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_GetWorkflowExecutionRetentionReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowExecutionRetentionReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetWorkflowExecutionRetentionReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/GetWorkflowExecutionRetentionReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetWorkflowExecutionRetentionReport(ctx, req.(*GetWorkflowExecutionRetentionReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_BulkWorkflowExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkWorkflowExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).BulkWorkflowExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/BulkWorkflowExecutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).BulkWorkflowExecutions(ctx, req.(*BulkWorkflowExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_GetWorkflowExecutionLineage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowExecutionLineageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetWorkflowExecutionLineage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/GetWorkflowExecutionLineage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetWorkflowExecutionLineage(ctx, req.(*GetWorkflowExecutionLineageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListArtifactLineage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtifactLineageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListArtifactLineage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ListArtifactLineage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListArtifactLineage(ctx, req.(*ListArtifactLineageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_GetArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetArtifactRequest)
 	if err := dec(in); err != nil {
@@ -544,6 +1242,71 @@ func _WorkflowService_GetArtifact_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_GetArtifactURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtifactURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetArtifactURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/GetArtifactURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetArtifactURL(ctx, req.(*GetArtifactURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_DownloadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadArtifactRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServiceServer).DownloadArtifact(m, &workflowServiceDownloadArtifactServer{stream})
+}
+
+type WorkflowService_DownloadArtifactServer interface {
+	Send(*ArtifactChunk) error
+	grpc.ServerStream
+}
+
+type workflowServiceDownloadArtifactServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceDownloadArtifactServer) Send(m *ArtifactChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkflowService_UploadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).UploadArtifact(&workflowServiceUploadArtifactServer{stream})
+}
+
+type WorkflowService_UploadArtifactServer interface {
+	SendAndClose(*UploadArtifactResponse) error
+	Recv() (*UploadArtifactRequest, error)
+	grpc.ServerStream
+}
+
+type workflowServiceUploadArtifactServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceUploadArtifactServer) SendAndClose(m *UploadArtifactResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workflowServiceUploadArtifactServer) Recv() (*UploadArtifactRequest, error) {
+	m := new(UploadArtifactRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _WorkflowService_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListFilesRequest)
 	if err := dec(in); err != nil {
@@ -652,6 +1415,42 @@ func _WorkflowService_UpdateWorkflowExecutionMetrics_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_AddWorkflowExecutionMetricPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWorkflowExecutionMetricPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).AddWorkflowExecutionMetricPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/AddWorkflowExecutionMetricPoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).AddWorkflowExecutionMetricPoints(ctx, req.(*AddWorkflowExecutionMetricPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListWorkflowExecutionMetricSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowExecutionMetricSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionMetricSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowService/ListWorkflowExecutionMetricSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListWorkflowExecutionMetricSeries(ctx, req.(*ListWorkflowExecutionMetricSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WorkflowService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.WorkflowService",
 	HandlerType: (*WorkflowServiceServer)(nil),
@@ -676,22 +1475,78 @@ var _WorkflowService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListWorkflowExecutions",
 			Handler:    _WorkflowService_ListWorkflowExecutions_Handler,
 		},
+		{
+			MethodName: "CompareWorkflowExecutions",
+			Handler:    _WorkflowService_CompareWorkflowExecutions_Handler,
+		},
 		{
 			MethodName: "GetWorkflowExecutionMetrics",
 			Handler:    _WorkflowService_GetWorkflowExecutionMetrics_Handler,
 		},
+		{
+			MethodName: "ListWorkflowExecutionNodes",
+			Handler:    _WorkflowService_ListWorkflowExecutionNodes_Handler,
+		},
+		{
+			MethodName: "RetryWorkflowExecution",
+			Handler:    _WorkflowService_RetryWorkflowExecution_Handler,
+		},
 		{
 			MethodName: "ResubmitWorkflowExecution",
 			Handler:    _WorkflowService_ResubmitWorkflowExecution_Handler,
 		},
+		{
+			MethodName: "SuspendWorkflowExecution",
+			Handler:    _WorkflowService_SuspendWorkflowExecution_Handler,
+		},
+		{
+			MethodName: "ResumeWorkflowExecution",
+			Handler:    _WorkflowService_ResumeWorkflowExecution_Handler,
+		},
+		{
+			MethodName: "ListWorkflowExecutionSuspendNodes",
+			Handler:    _WorkflowService_ListWorkflowExecutionSuspendNodes_Handler,
+		},
 		{
 			MethodName: "TerminateWorkflowExecution",
 			Handler:    _WorkflowService_TerminateWorkflowExecution_Handler,
 		},
+		{
+			MethodName: "ListQueuedWorkflowExecutions",
+			Handler:    _WorkflowService_ListQueuedWorkflowExecutions_Handler,
+		},
+		{
+			MethodName: "MoveQueuedWorkflowExecution",
+			Handler:    _WorkflowService_MoveQueuedWorkflowExecution_Handler,
+		},
+		{
+			MethodName: "CancelQueuedWorkflowExecution",
+			Handler:    _WorkflowService_CancelQueuedWorkflowExecution_Handler,
+		},
+		{
+			MethodName: "GetWorkflowExecutionRetentionReport",
+			Handler:    _WorkflowService_GetWorkflowExecutionRetentionReport_Handler,
+		},
+		{
+			MethodName: "BulkWorkflowExecutions",
+			Handler:    _WorkflowService_BulkWorkflowExecutions_Handler,
+		},
+		{
+			MethodName: "GetWorkflowExecutionLineage",
+			Handler:    _WorkflowService_GetWorkflowExecutionLineage_Handler,
+		},
+		{
+			MethodName: "ListArtifactLineage",
+			Handler:    _WorkflowService_ListArtifactLineage_Handler,
+		},
 		{
 			MethodName: "GetArtifact",
 			Handler:    _WorkflowService_GetArtifact_Handler,
 		},
+		{
+			MethodName: "GetArtifactURL",
+			Handler:    _WorkflowService_GetArtifactURL_Handler,
+		},
 		{
 			MethodName: "ListFiles",
 			Handler:    _WorkflowService_ListFiles_Handler,
@@ -716,6 +1571,14 @@ var _WorkflowService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateWorkflowExecutionMetrics",
 			Handler:    _WorkflowService_UpdateWorkflowExecutionMetrics_Handler,
 		},
+		{
+			MethodName: "AddWorkflowExecutionMetricPoints",
+			Handler:    _WorkflowService_AddWorkflowExecutionMetricPoints_Handler,
+		},
+		{
+			MethodName: "ListWorkflowExecutionMetricSeries",
+			Handler:    _WorkflowService_ListWorkflowExecutionMetricSeries_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -723,11 +1586,31 @@ var _WorkflowService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _WorkflowService_WatchWorkflowExecution_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchWorkflowExecutions",
+			Handler:       _WorkflowService_WatchWorkflowExecutions_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "GetWorkflowExecutionLogs",
 			Handler:       _WorkflowService_GetWorkflowExecutionLogs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetMergedWorkflowExecutionLogs",
+			Handler:       _WorkflowService_GetMergedWorkflowExecutionLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadArtifact",
+			Handler:       _WorkflowService_DownloadArtifact_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadArtifact",
+			Handler:       _WorkflowService_UploadArtifact_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "workflow.proto",
 }
@@ -23,6 +23,15 @@ type WorkflowTemplateServiceClient interface {
 	CreateWorkflowTemplateVersion(ctx context.Context, in *CreateWorkflowTemplateRequest, opts ...grpc.CallOption) (*WorkflowTemplate, error)
 	GetWorkflowTemplate(ctx context.Context, in *GetWorkflowTemplateRequest, opts ...grpc.CallOption) (*WorkflowTemplate, error)
 	ListWorkflowTemplateVersions(ctx context.Context, in *ListWorkflowTemplateVersionsRequest, opts ...grpc.CallOption) (*ListWorkflowTemplateVersionsResponse, error)
+	// Get what changed from one version of a WorkflowTemplate to another. toVersion defaults to the latest version
+	DiffWorkflowTemplateVersions(ctx context.Context, in *DiffWorkflowTemplateVersionsRequest, opts ...grpc.CallOption) (*WorkflowTemplateDiff, error)
+	// Mark an existing version of a WorkflowTemplate as the latest one, optionally updating the cron workflows that use its other versions
+	SetLatestWorkflowTemplateVersion(ctx context.Context, in *SetLatestWorkflowTemplateVersionRequest, opts ...grpc.CallOption) (*SetLatestWorkflowTemplateVersionResponse, error)
+	// Export workflow templates and workspace templates as a bundle, a gzipped tarball that can be imported into another namespace or cluster
+	ExportTemplateBundle(ctx context.Context, in *ExportTemplateBundleRequest, opts ...grpc.CallOption) (*ExportTemplateBundleResponse, error)
+	// Import the templates of a bundle. collision is fail (default), rename or version.
+	// If a template fails, the ones imported before it are kept, and the error names them.
+	ImportTemplateBundle(ctx context.Context, in *ImportTemplateBundleRequest, opts ...grpc.CallOption) (*ImportTemplateBundleResponse, error)
 	ListWorkflowTemplates(ctx context.Context, in *ListWorkflowTemplatesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplatesResponse, error)
 	CloneWorkflowTemplate(ctx context.Context, in *CloneWorkflowTemplateRequest, opts ...grpc.CallOption) (*WorkflowTemplate, error)
 	ArchiveWorkflowTemplate(ctx context.Context, in *ArchiveWorkflowTemplateRequest, opts ...grpc.CallOption) (*ArchiveWorkflowTemplateResponse, error)
@@ -81,6 +90,42 @@ func (c *workflowTemplateServiceClient) ListWorkflowTemplateVersions(ctx context
 	return out, nil
 }
 
+func (c *workflowTemplateServiceClient) DiffWorkflowTemplateVersions(ctx context.Context, in *DiffWorkflowTemplateVersionsRequest, opts ...grpc.CallOption) (*WorkflowTemplateDiff, error) {
+	out := new(WorkflowTemplateDiff)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateService/DiffWorkflowTemplateVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateServiceClient) SetLatestWorkflowTemplateVersion(ctx context.Context, in *SetLatestWorkflowTemplateVersionRequest, opts ...grpc.CallOption) (*SetLatestWorkflowTemplateVersionResponse, error) {
+	out := new(SetLatestWorkflowTemplateVersionResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateService/SetLatestWorkflowTemplateVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateServiceClient) ExportTemplateBundle(ctx context.Context, in *ExportTemplateBundleRequest, opts ...grpc.CallOption) (*ExportTemplateBundleResponse, error) {
+	out := new(ExportTemplateBundleResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateService/ExportTemplateBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateServiceClient) ImportTemplateBundle(ctx context.Context, in *ImportTemplateBundleRequest, opts ...grpc.CallOption) (*ImportTemplateBundleResponse, error) {
+	out := new(ImportTemplateBundleResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateService/ImportTemplateBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowTemplateServiceClient) ListWorkflowTemplates(ctx context.Context, in *ListWorkflowTemplatesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplatesResponse, error) {
 	out := new(ListWorkflowTemplatesResponse)
 	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateService/ListWorkflowTemplates", in, out, opts...)
@@ -118,6 +163,15 @@ type WorkflowTemplateServiceServer interface {
 	CreateWorkflowTemplateVersion(context.Context, *CreateWorkflowTemplateRequest) (*WorkflowTemplate, error)
 	GetWorkflowTemplate(context.Context, *GetWorkflowTemplateRequest) (*WorkflowTemplate, error)
 	ListWorkflowTemplateVersions(context.Context, *ListWorkflowTemplateVersionsRequest) (*ListWorkflowTemplateVersionsResponse, error)
+	// Get what changed from one version of a WorkflowTemplate to another. toVersion defaults to the latest version
+	DiffWorkflowTemplateVersions(context.Context, *DiffWorkflowTemplateVersionsRequest) (*WorkflowTemplateDiff, error)
+	// Mark an existing version of a WorkflowTemplate as the latest one, optionally updating the cron workflows that use its other versions
+	SetLatestWorkflowTemplateVersion(context.Context, *SetLatestWorkflowTemplateVersionRequest) (*SetLatestWorkflowTemplateVersionResponse, error)
+	// Export workflow templates and workspace templates as a bundle, a gzipped tarball that can be imported into another namespace or cluster
+	ExportTemplateBundle(context.Context, *ExportTemplateBundleRequest) (*ExportTemplateBundleResponse, error)
+	// Import the templates of a bundle. collision is fail (default), rename or version.
+	// If a template fails, the ones imported before it are kept, and the error names them.
+	ImportTemplateBundle(context.Context, *ImportTemplateBundleRequest) (*ImportTemplateBundleResponse, error)
 	ListWorkflowTemplates(context.Context, *ListWorkflowTemplatesRequest) (*ListWorkflowTemplatesResponse, error)
 	CloneWorkflowTemplate(context.Context, *CloneWorkflowTemplateRequest) (*WorkflowTemplate, error)
 	ArchiveWorkflowTemplate(context.Context, *ArchiveWorkflowTemplateRequest) (*ArchiveWorkflowTemplateResponse, error)
@@ -143,6 +197,18 @@ func (UnimplementedWorkflowTemplateServiceServer) GetWorkflowTemplate(context.Co
 func (UnimplementedWorkflowTemplateServiceServer) ListWorkflowTemplateVersions(context.Context, *ListWorkflowTemplateVersionsRequest) (*ListWorkflowTemplateVersionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowTemplateVersions not implemented")
 }
+func (UnimplementedWorkflowTemplateServiceServer) DiffWorkflowTemplateVersions(context.Context, *DiffWorkflowTemplateVersionsRequest) (*WorkflowTemplateDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffWorkflowTemplateVersions not implemented")
+}
+func (UnimplementedWorkflowTemplateServiceServer) SetLatestWorkflowTemplateVersion(context.Context, *SetLatestWorkflowTemplateVersionRequest) (*SetLatestWorkflowTemplateVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLatestWorkflowTemplateVersion not implemented")
+}
+func (UnimplementedWorkflowTemplateServiceServer) ExportTemplateBundle(context.Context, *ExportTemplateBundleRequest) (*ExportTemplateBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTemplateBundle not implemented")
+}
+func (UnimplementedWorkflowTemplateServiceServer) ImportTemplateBundle(context.Context, *ImportTemplateBundleRequest) (*ImportTemplateBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTemplateBundle not implemented")
+}
 func (UnimplementedWorkflowTemplateServiceServer) ListWorkflowTemplates(context.Context, *ListWorkflowTemplatesRequest) (*ListWorkflowTemplatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowTemplates not implemented")
 }
@@ -256,6 +322,78 @@ func _WorkflowTemplateService_ListWorkflowTemplateVersions_Handler(srv interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowTemplateService_DiffWorkflowTemplateVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffWorkflowTemplateVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateServiceServer).DiffWorkflowTemplateVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateService/DiffWorkflowTemplateVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateServiceServer).DiffWorkflowTemplateVersions(ctx, req.(*DiffWorkflowTemplateVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateService_SetLatestWorkflowTemplateVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLatestWorkflowTemplateVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateServiceServer).SetLatestWorkflowTemplateVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateService/SetLatestWorkflowTemplateVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateServiceServer).SetLatestWorkflowTemplateVersion(ctx, req.(*SetLatestWorkflowTemplateVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateService_ExportTemplateBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTemplateBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateServiceServer).ExportTemplateBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateService/ExportTemplateBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateServiceServer).ExportTemplateBundle(ctx, req.(*ExportTemplateBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateService_ImportTemplateBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTemplateBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateServiceServer).ImportTemplateBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateService/ImportTemplateBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateServiceServer).ImportTemplateBundle(ctx, req.(*ImportTemplateBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowTemplateService_ListWorkflowTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListWorkflowTemplatesRequest)
 	if err := dec(in); err != nil {
@@ -334,6 +472,22 @@ var _WorkflowTemplateService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListWorkflowTemplateVersions",
 			Handler:    _WorkflowTemplateService_ListWorkflowTemplateVersions_Handler,
 		},
+		{
+			MethodName: "DiffWorkflowTemplateVersions",
+			Handler:    _WorkflowTemplateService_DiffWorkflowTemplateVersions_Handler,
+		},
+		{
+			MethodName: "SetLatestWorkflowTemplateVersion",
+			Handler:    _WorkflowTemplateService_SetLatestWorkflowTemplateVersion_Handler,
+		},
+		{
+			MethodName: "ExportTemplateBundle",
+			Handler:    _WorkflowTemplateService_ExportTemplateBundle_Handler,
+		},
+		{
+			MethodName: "ImportTemplateBundle",
+			Handler:    _WorkflowTemplateService_ImportTemplateBundle_Handler,
+		},
 		{
 			MethodName: "ListWorkflowTemplates",
 			Handler:    _WorkflowTemplateService_ListWorkflowTemplates_Handler,
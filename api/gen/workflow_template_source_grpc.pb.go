@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// WorkflowTemplateSourceServiceClient is the client API for WorkflowTemplateSourceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WorkflowTemplateSourceServiceClient interface {
+	// Registers a path in a branch of a git repository whose YAML files are synced to the workflow templates of the namespace
+	CreateWorkflowTemplateSource(ctx context.Context, in *CreateWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error)
+	GetWorkflowTemplateSource(ctx context.Context, in *GetWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error)
+	ListWorkflowTemplateSources(ctx context.Context, in *ListWorkflowTemplateSourcesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplateSourcesResponse, error)
+	// Stops syncing a source. The workflow templates it created are kept.
+	DeleteWorkflowTemplateSource(ctx context.Context, in *DeleteWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Lists the files of a source and the workflow templates they are synced to
+	ListWorkflowTemplateSourceFiles(ctx context.Context, in *ListWorkflowTemplateSourceFilesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplateSourceFilesResponse, error)
+	// Pulls a source right away instead of waiting for its sync interval
+	SyncWorkflowTemplateSource(ctx context.Context, in *SyncWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error)
+}
+
+type workflowTemplateSourceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowTemplateSourceServiceClient(cc grpc.ClientConnInterface) WorkflowTemplateSourceServiceClient {
+	return &workflowTemplateSourceServiceClient{cc}
+}
+
+func (c *workflowTemplateSourceServiceClient) CreateWorkflowTemplateSource(ctx context.Context, in *CreateWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error) {
+	out := new(WorkflowTemplateSource)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/CreateWorkflowTemplateSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateSourceServiceClient) GetWorkflowTemplateSource(ctx context.Context, in *GetWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error) {
+	out := new(WorkflowTemplateSource)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/GetWorkflowTemplateSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateSourceServiceClient) ListWorkflowTemplateSources(ctx context.Context, in *ListWorkflowTemplateSourcesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplateSourcesResponse, error) {
+	out := new(ListWorkflowTemplateSourcesResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/ListWorkflowTemplateSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateSourceServiceClient) DeleteWorkflowTemplateSource(ctx context.Context, in *DeleteWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/DeleteWorkflowTemplateSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateSourceServiceClient) ListWorkflowTemplateSourceFiles(ctx context.Context, in *ListWorkflowTemplateSourceFilesRequest, opts ...grpc.CallOption) (*ListWorkflowTemplateSourceFilesResponse, error) {
+	out := new(ListWorkflowTemplateSourceFilesResponse)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/ListWorkflowTemplateSourceFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowTemplateSourceServiceClient) SyncWorkflowTemplateSource(ctx context.Context, in *SyncWorkflowTemplateSourceRequest, opts ...grpc.CallOption) (*WorkflowTemplateSource, error) {
+	out := new(WorkflowTemplateSource)
+	err := c.cc.Invoke(ctx, "/api.WorkflowTemplateSourceService/SyncWorkflowTemplateSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkflowTemplateSourceServiceServer is the server API for WorkflowTemplateSourceService service.
+// All implementations must embed UnimplementedWorkflowTemplateSourceServiceServer
+// for forward compatibility
+type WorkflowTemplateSourceServiceServer interface {
+	// Registers a path in a branch of a git repository whose YAML files are synced to the workflow templates of the namespace
+	CreateWorkflowTemplateSource(context.Context, *CreateWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error)
+	GetWorkflowTemplateSource(context.Context, *GetWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error)
+	ListWorkflowTemplateSources(context.Context, *ListWorkflowTemplateSourcesRequest) (*ListWorkflowTemplateSourcesResponse, error)
+	// Stops syncing a source. The workflow templates it created are kept.
+	DeleteWorkflowTemplateSource(context.Context, *DeleteWorkflowTemplateSourceRequest) (*emptypb.Empty, error)
+	// Lists the files of a source and the workflow templates they are synced to
+	ListWorkflowTemplateSourceFiles(context.Context, *ListWorkflowTemplateSourceFilesRequest) (*ListWorkflowTemplateSourceFilesResponse, error)
+	// Pulls a source right away instead of waiting for its sync interval
+	SyncWorkflowTemplateSource(context.Context, *SyncWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error)
+	mustEmbedUnimplementedWorkflowTemplateSourceServiceServer()
+}
+
+// UnimplementedWorkflowTemplateSourceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWorkflowTemplateSourceServiceServer struct {
+}
+
+func (UnimplementedWorkflowTemplateSourceServiceServer) CreateWorkflowTemplateSource(context.Context, *CreateWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkflowTemplateSource not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) GetWorkflowTemplateSource(context.Context, *GetWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowTemplateSource not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) ListWorkflowTemplateSources(context.Context, *ListWorkflowTemplateSourcesRequest) (*ListWorkflowTemplateSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowTemplateSources not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) DeleteWorkflowTemplateSource(context.Context, *DeleteWorkflowTemplateSourceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkflowTemplateSource not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) ListWorkflowTemplateSourceFiles(context.Context, *ListWorkflowTemplateSourceFilesRequest) (*ListWorkflowTemplateSourceFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowTemplateSourceFiles not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) SyncWorkflowTemplateSource(context.Context, *SyncWorkflowTemplateSourceRequest) (*WorkflowTemplateSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncWorkflowTemplateSource not implemented")
+}
+func (UnimplementedWorkflowTemplateSourceServiceServer) mustEmbedUnimplementedWorkflowTemplateSourceServiceServer() {
+}
+
+// UnsafeWorkflowTemplateSourceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowTemplateSourceServiceServer will
+// result in compilation errors.
+type UnsafeWorkflowTemplateSourceServiceServer interface {
+	mustEmbedUnimplementedWorkflowTemplateSourceServiceServer()
+}
+
+func RegisterWorkflowTemplateSourceServiceServer(s grpc.ServiceRegistrar, srv WorkflowTemplateSourceServiceServer) {
+	s.RegisterService(&_WorkflowTemplateSourceService_serviceDesc, srv)
+}
+
+func _WorkflowTemplateSourceService_CreateWorkflowTemplateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkflowTemplateSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).CreateWorkflowTemplateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/CreateWorkflowTemplateSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).CreateWorkflowTemplateSource(ctx, req.(*CreateWorkflowTemplateSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateSourceService_GetWorkflowTemplateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowTemplateSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).GetWorkflowTemplateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/GetWorkflowTemplateSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).GetWorkflowTemplateSource(ctx, req.(*GetWorkflowTemplateSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateSourceService_ListWorkflowTemplateSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowTemplateSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).ListWorkflowTemplateSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/ListWorkflowTemplateSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).ListWorkflowTemplateSources(ctx, req.(*ListWorkflowTemplateSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateSourceService_DeleteWorkflowTemplateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWorkflowTemplateSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).DeleteWorkflowTemplateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/DeleteWorkflowTemplateSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).DeleteWorkflowTemplateSource(ctx, req.(*DeleteWorkflowTemplateSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateSourceService_ListWorkflowTemplateSourceFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowTemplateSourceFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).ListWorkflowTemplateSourceFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/ListWorkflowTemplateSourceFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).ListWorkflowTemplateSourceFiles(ctx, req.(*ListWorkflowTemplateSourceFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowTemplateSourceService_SyncWorkflowTemplateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncWorkflowTemplateSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowTemplateSourceServiceServer).SyncWorkflowTemplateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WorkflowTemplateSourceService/SyncWorkflowTemplateSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowTemplateSourceServiceServer).SyncWorkflowTemplateSource(ctx, req.(*SyncWorkflowTemplateSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WorkflowTemplateSourceService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.WorkflowTemplateSourceService",
+	HandlerType: (*WorkflowTemplateSourceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWorkflowTemplateSource",
+			Handler:    _WorkflowTemplateSourceService_CreateWorkflowTemplateSource_Handler,
+		},
+		{
+			MethodName: "GetWorkflowTemplateSource",
+			Handler:    _WorkflowTemplateSourceService_GetWorkflowTemplateSource_Handler,
+		},
+		{
+			MethodName: "ListWorkflowTemplateSources",
+			Handler:    _WorkflowTemplateSourceService_ListWorkflowTemplateSources_Handler,
+		},
+		{
+			MethodName: "DeleteWorkflowTemplateSource",
+			Handler:    _WorkflowTemplateSourceService_DeleteWorkflowTemplateSource_Handler,
+		},
+		{
+			MethodName: "ListWorkflowTemplateSourceFiles",
+			Handler:    _WorkflowTemplateSourceService_ListWorkflowTemplateSourceFiles_Handler,
+		},
+		{
+			MethodName: "SyncWorkflowTemplateSource",
+			Handler:    _WorkflowTemplateSourceService_SyncWorkflowTemplateSource_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "workflow_template_source.proto",
+}
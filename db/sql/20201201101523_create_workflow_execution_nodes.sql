@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workflow_execution_nodes
+(
+    id                    serial PRIMARY KEY,
+    workflow_execution_id integer      NOT NULL REFERENCES workflow_executions ON DELETE CASCADE,
+    node_id               varchar(255) NOT NULL CHECK (node_id <> ''),
+    name                  text         NOT NULL,
+    display_name          text         NOT NULL,
+    type                  varchar(30)  NOT NULL,
+    template_name         text         NOT NULL,
+    phase                 varchar(50)  NOT NULL,
+    message               text         NOT NULL,
+    boundary_id           varchar(255) NOT NULL,
+    pod_name              varchar(255) NOT NULL,
+    inputs                JSONB        NOT NULL,
+    outputs               JSONB        NOT NULL,
+    children              JSONB        NOT NULL,
+    exit_code             integer               DEFAULT NULL,
+    started_at            timestamp             DEFAULT NULL,
+    finished_at           timestamp             DEFAULT NULL,
+
+    -- auditing info
+    created_at            timestamp    NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at           timestamp             DEFAULT NULL
+);
+CREATE UNIQUE INDEX workflow_execution_nodes_execution_node ON workflow_execution_nodes (workflow_execution_id, node_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workflow_execution_nodes;
+-- +goose StatementEnd
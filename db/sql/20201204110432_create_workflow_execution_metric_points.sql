@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workflow_execution_metric_points
+(
+    id                    bigserial PRIMARY KEY,
+    workflow_execution_id integer          NOT NULL REFERENCES workflow_executions ON DELETE CASCADE,
+    name                  varchar(255)     NOT NULL CHECK (name <> ''),
+    step                  bigint           NOT NULL,
+    value                 double precision NOT NULL,
+    timestamp             timestamp        NOT NULL,
+
+    -- auditing info
+    created_at            timestamp        NOT NULL DEFAULT (NOW() at time zone 'utc')
+);
+CREATE UNIQUE INDEX workflow_execution_metric_points_execution_name_step ON workflow_execution_metric_points (workflow_execution_id, name, step);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workflow_execution_metric_points;
+-- +goose StatementEnd
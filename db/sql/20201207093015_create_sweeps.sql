@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sweeps
+(
+    id                           serial PRIMARY KEY,
+    uid                          varchar(63)  NOT NULL CHECK (uid <> ''),
+    name                         varchar(63)  NOT NULL CHECK (name <> ''),
+    namespace                    varchar(63)  NOT NULL,
+    workflow_template_version_id integer      NOT NULL REFERENCES workflow_template_versions ON DELETE CASCADE,
+    strategy                     varchar(30)  NOT NULL,
+    spec                         JSONB        NOT NULL,
+    objective_metric             varchar(255) NOT NULL,
+    objective_goal               varchar(30)  NOT NULL,
+    max_concurrency              integer      NOT NULL,
+    phase                        varchar(50)  NOT NULL,
+    message                      text         NOT NULL DEFAULT '',
+    best_run_id                  integer               DEFAULT NULL,
+    labels                       JSONB        NOT NULL DEFAULT '{}'::JSONB,
+    started_at                   timestamp             DEFAULT NULL,
+    finished_at                  timestamp             DEFAULT NULL,
+
+    -- auditing info
+    created_at                   timestamp    NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at                  timestamp             DEFAULT NULL
+);
+CREATE UNIQUE INDEX sweeps_uid_namespace_key ON sweeps (uid, namespace);
+
+CREATE TABLE sweep_runs
+(
+    id                           serial PRIMARY KEY,
+    sweep_id                     integer     NOT NULL REFERENCES sweeps ON DELETE CASCADE,
+    workflow_execution_id        integer              DEFAULT NULL REFERENCES workflow_executions ON DELETE SET NULL,
+    trial                        integer     NOT NULL,
+    rung                         integer     NOT NULL DEFAULT 0,
+    parameters                   JSONB       NOT NULL,
+    phase                        varchar(50) NOT NULL,
+    objective_value              double precision     DEFAULT NULL,
+
+    -- auditing info
+    created_at                   timestamp   NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at                  timestamp            DEFAULT NULL
+);
+CREATE UNIQUE INDEX sweep_runs_sweep_trial_rung ON sweep_runs (sweep_id, trial, rung);
+
+ALTER TABLE sweeps ADD CONSTRAINT sweeps_best_run_id_fkey FOREIGN KEY (best_run_id) REFERENCES sweep_runs ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sweeps DROP CONSTRAINT sweeps_best_run_id_fkey;
+DROP TABLE sweep_runs;
+DROP TABLE sweeps;
+-- +goose StatementEnd
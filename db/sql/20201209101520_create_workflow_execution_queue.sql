@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workflow_execution_queue
+(
+    id                    serial PRIMARY KEY,
+    workflow_execution_id integer     NOT NULL REFERENCES workflow_executions ON DELETE CASCADE,
+    namespace             varchar(63) NOT NULL,
+    position              integer     NOT NULL,
+    manifest              JSONB       NOT NULL,
+
+    -- auditing info
+    created_at            timestamp   NOT NULL DEFAULT (NOW() at time zone 'utc')
+);
+CREATE UNIQUE INDEX workflow_execution_queue_workflow_execution_id_key ON workflow_execution_queue (workflow_execution_id);
+CREATE INDEX workflow_execution_queue_namespace_position_idx ON workflow_execution_queue (namespace, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workflow_execution_queue;
+-- +goose StatementEnd
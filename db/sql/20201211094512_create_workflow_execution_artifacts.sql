@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workflow_execution_artifacts
+(
+    id                    serial PRIMARY KEY,
+    workflow_execution_id integer      NOT NULL REFERENCES workflow_executions ON DELETE CASCADE,
+    node_id               varchar(255) NOT NULL,
+    direction             varchar(10)  NOT NULL CHECK (direction IN ('input', 'output')),
+    name                  text         NOT NULL,
+    bucket                text         NOT NULL,
+    key                   text         NOT NULL CHECK (key <> ''),
+
+    -- auditing info
+    created_at            timestamp    NOT NULL DEFAULT (NOW() at time zone 'utc')
+);
+CREATE UNIQUE INDEX workflow_execution_artifacts_execution_node_artifact ON workflow_execution_artifacts (workflow_execution_id, node_id, direction, name);
+CREATE INDEX workflow_execution_artifacts_bucket_key_idx ON workflow_execution_artifacts (bucket, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workflow_execution_artifacts;
+-- +goose StatementEnd
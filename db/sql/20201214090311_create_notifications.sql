@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE notification_subscriptions
+(
+    id          serial PRIMARY KEY,
+    uid         varchar(63) NOT NULL CHECK (uid <> ''),
+    name        varchar(63) NOT NULL CHECK (name <> ''),
+    namespace   varchar(63) NOT NULL,
+    url         text        NOT NULL CHECK (url <> ''),
+    filter      JSONB       NOT NULL DEFAULT '{}'::JSONB,
+
+    -- auditing info
+    created_at  timestamp   NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at timestamp            DEFAULT NULL
+);
+CREATE UNIQUE INDEX notification_subscriptions_namespace_uid_key ON notification_subscriptions (namespace, uid);
+
+CREATE TABLE notification_deliveries
+(
+    id                     serial PRIMARY KEY,
+    subscription_id        integer     NOT NULL REFERENCES notification_subscriptions ON DELETE CASCADE,
+    event_id               varchar(63) NOT NULL,
+    event_type             varchar(63) NOT NULL,
+    workflow_execution_uid varchar(63) NOT NULL DEFAULT '',
+    payload                JSONB       NOT NULL,
+    status                 varchar(20) NOT NULL,
+    attempts               integer     NOT NULL DEFAULT 0,
+    response_code          integer              DEFAULT NULL,
+    error                  text        NOT NULL DEFAULT '',
+    next_attempt_at        timestamp            DEFAULT NULL,
+    delivered_at           timestamp            DEFAULT NULL,
+
+    -- auditing info
+    created_at             timestamp   NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at            timestamp            DEFAULT NULL
+);
+CREATE INDEX notification_deliveries_status_next_attempt_at_idx ON notification_deliveries (status, next_attempt_at);
+CREATE INDEX notification_deliveries_subscription_id_created_at_idx ON notification_deliveries (subscription_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notification_deliveries;
+DROP TABLE notification_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workflow_template_sources
+(
+    id              serial PRIMARY KEY,
+    uid             varchar(63)  NOT NULL CHECK (uid <> ''),
+    name            varchar(63)  NOT NULL CHECK (name <> ''),
+    namespace       varchar(63)  NOT NULL,
+    repository      text         NOT NULL CHECK (repository <> ''),
+    branch          varchar(255) NOT NULL CHECK (branch <> ''),
+    path            text         NOT NULL DEFAULT '',
+    sync_interval   integer      NOT NULL,
+    last_commit_sha varchar(40)  NOT NULL DEFAULT '',
+    last_synced_at  timestamp             DEFAULT NULL,
+    last_error      text         NOT NULL DEFAULT '',
+
+    -- auditing info
+    created_at      timestamp    NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at     timestamp             DEFAULT NULL
+);
+CREATE UNIQUE INDEX workflow_template_sources_namespace_uid_key ON workflow_template_sources (namespace, uid);
+
+CREATE TABLE workflow_template_source_files
+(
+    id                    serial PRIMARY KEY,
+    source_id             integer     NOT NULL REFERENCES workflow_template_sources ON DELETE CASCADE,
+    path                  text        NOT NULL,
+    content_sha256        varchar(64) NOT NULL,
+    commit_sha            varchar(40) NOT NULL,
+    workflow_template_uid varchar(30) NOT NULL,
+
+    -- auditing info
+    created_at            timestamp   NOT NULL DEFAULT (NOW() at time zone 'utc'),
+    modified_at           timestamp            DEFAULT NULL
+);
+CREATE UNIQUE INDEX workflow_template_source_files_source_id_path_key ON workflow_template_source_files (source_id, path);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workflow_template_source_files;
+DROP TABLE workflow_template_sources;
+-- +goose StatementEnd
@@ -4,8 +4,7 @@ go 1.15
 
 require (
 	cloud.google.com/go/storage v1.10.0
-	github.com/Azure/go-autorest v14.0.0+incompatible // indirect
-	github.com/Azure/go-autorest/autorest/adal v0.8.2 // indirect
+	github.com/Azure/azure-storage-blob-go v0.13.0
 	github.com/Masterminds/squirrel v1.1.0
 	github.com/argoproj/argo v0.0.0-20200331233432-4d1175eb68f6
 	github.com/argoproj/pkg v0.0.0-20200318225345-d3be5f29b1a8
@@ -27,6 +26,7 @@ require (
 	github.com/mattn/go-sqlite3 v2.0.3+incompatible // indirect
 	github.com/minio/minio-go/v6 v6.0.45
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/pressly/goose v2.6.0+incompatible
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5 // indirect
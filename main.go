@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
 	"github.com/gorilla/handlers"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
@@ -14,6 +15,7 @@ import (
 	migrations "github.com/onepanelio/core/db/go"
 	v1 "github.com/onepanelio/core/pkg"
 	"github.com/onepanelio/core/pkg/util/env"
+	"github.com/onepanelio/core/pkg/util/label"
 	"github.com/onepanelio/core/server"
 	"github.com/onepanelio/core/server/auth"
 	"github.com/pressly/goose"
@@ -34,6 +36,20 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
+)
+
+const (
+	// sweepReconcileInterval is how often sweeps check their runs
+	sweepReconcileInterval = 15 * time.Second
+	// workflowExecutionDispatchInterval is how often queued workflow executions are started if their concurrency limits allow it
+	workflowExecutionDispatchInterval = 10 * time.Second
+	// retentionInterval is how often the retention policies of the namespaces are enforced
+	retentionInterval = time.Hour
+	// notificationDeliveryInterval is how often pending notifications are sent to their subscriptions
+	notificationDeliveryInterval = 10 * time.Second
+	// workflowTemplateSourceSyncInterval is how often workflow template sources are checked for a sync that is due
+	workflowTemplateSourceSyncInterval = time.Minute
 )
 
 var (
@@ -90,8 +106,17 @@ func main() {
 
 			s := startRPCServer(v1.NewDB(db), kubeConfig, sysConfig, stopCh)
 
+			reconcilerStopCh := make(chan struct{})
+			go watchWorkflowExecutionChanges(v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+			go reconcilePeriodically("sweep reconciler", sweepReconcileInterval, (*v1.Client).ReconcileSweeps, v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+			go reconcilePeriodically("workflow execution dispatcher", workflowExecutionDispatchInterval, (*v1.Client).DispatchQueuedWorkflowExecutions, v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+			go reconcilePeriodically("retention policies", retentionInterval, (*v1.Client).EnforceRetentionPolicies, v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+			go reconcilePeriodically("notification deliveries", notificationDeliveryInterval, (*v1.Client).DeliverNotifications, v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+			go reconcilePeriodically("workflow template sources", workflowTemplateSourceSyncInterval, (*v1.Client).SyncWorkflowTemplateSources, v1.NewDB(db), kubeConfig, sysConfig, reconcilerStopCh)
+
 			<-stopCh
 
+			close(reconcilerStopCh)
 			s.Stop()
 			if err := db.Close(); err != nil {
 				log.Printf("[error] closing db connection")
@@ -147,6 +172,9 @@ func startRPCServer(db *v1.DB, kubeConfig *v1.Config, sysConfig v1.SystemConfig,
 	api.RegisterWorkspaceServiceServer(s, server.NewWorkspaceServer())
 	api.RegisterConfigServiceServer(s, server.NewConfigServer())
 	api.RegisterServiceServiceServer(s, server.NewServiceServer())
+	api.RegisterSweepServiceServer(s, server.NewSweepServer())
+	api.RegisterNotificationServiceServer(s, server.NewNotificationServer())
+	api.RegisterWorkflowTemplateSourceServiceServer(s, server.NewWorkflowTemplateSourceServer())
 
 	go func() {
 		if err := s.Serve(lis); err != nil {
@@ -182,6 +210,20 @@ func startHTTPProxy() {
 	registerHandler(api.RegisterWorkspaceServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
 	registerHandler(api.RegisterConfigServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
 	registerHandler(api.RegisterServiceServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
+	registerHandler(api.RegisterSweepServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
+	registerHandler(api.RegisterNotificationServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
+	registerHandler(api.RegisterWorkflowTemplateSourceServiceHandlerFromEndpoint, ctx, mux, endpoint, opts)
+
+	conn, err := grpc.DialContext(ctx, endpoint, opts...)
+	if err != nil {
+		log.Fatalf("Failed to connect to RPC server: %v", err)
+	}
+	defer conn.Close()
+	workflowClient := api.NewWorkflowServiceClient(conn)
+	handler := server.ArtifactDownloadHandler(workflowClient, mux)
+	handler = server.ArtifactUploadHandler(workflowClient, handler)
+	handler = server.LogDownloadHandler(workflowClient, handler)
+	handler = server.ComparisonDownloadHandler(workflowClient, handler)
 
 	log.Printf("Starting HTTP proxy on port %v", *httpPort)
 
@@ -191,14 +233,17 @@ func startHTTPProxy() {
 	}
 
 	// Allow Content-Type for JSON
-	allowedHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Authorization"})
+	allowedHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Authorization", "Range"})
+
+	// Allow reading the headers of artifact downloads
+	exposedHeaders := handlers.ExposedHeaders([]string{"Content-Disposition", "Content-Range", "Accept-Ranges"})
 
 	// Allow PUT. Have to include all others as it clears them out.
 	allowedMethods := handlers.AllowedMethods([]string{"HEAD", "GET", "POST", "PUT", "DELETE", "PATCH"})
 
 	if err := http.ListenAndServe(*httpPort, wsproxy.WebsocketProxy(
 		handlers.CORS(
-			handlers.AllowedOriginValidator(ogValidator), allowedHeaders, allowedMethods)(mux),
+			handlers.AllowedOriginValidator(ogValidator), allowedHeaders, allowedMethods, exposedHeaders)(handler),
 		wsproxy.WithTokenCookieName("auth-token"),
 	)); err != nil {
 		log.Fatalf("Failed to serve HTTP listener: %v", err)
@@ -268,6 +313,104 @@ func watchConfigmapChanges(namespace string, stopCh <-chan struct{}, onChange fu
 	controller.Run(neverStopCh)
 }
 
+// watchWorkflowExecutionChanges keeps the workflow executions in the database in sync with the status of the argo workflows.
+// Executions that were left unfinished while the server was not running are repaired before the watch starts.
+func watchWorkflowExecutionChanges(db *v1.DB, kubeConfig *v1.Config, sysConfig v1.SystemConfig, stopCh <-chan struct{}) {
+	client, err := v1.NewClient(kubeConfig, db, sysConfig)
+	if err != nil {
+		log.Errorf("Failed to create workflow execution reconciler client: %v", err)
+		return
+	}
+
+	if err := client.RepairWorkflowExecutions(); err != nil {
+		log.Errorf("Failed to repair workflow executions: %v", err)
+	}
+
+	workflows := client.ArgoprojV1alpha1().Workflows(apiv1.NamespaceAll)
+	listFunc := func(options apiv1.ListOptions) (k8runtime.Object, error) {
+		options.LabelSelector = label.WorkflowTemplateUid
+		return workflows.List(options)
+	}
+	watchFunc := func(options apiv1.ListOptions) (watch.Interface, error) {
+		options.Watch = true
+		options.LabelSelector = label.WorkflowTemplateUid
+		return workflows.Watch(options)
+	}
+
+	syncStatus := func(obj interface{}) {
+		wf, ok := obj.(*wfv1.Workflow)
+		if !ok {
+			return
+		}
+		if err := client.SyncWorkflowExecutionStatus(wf); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": wf.Namespace,
+				"UID":       wf.Name,
+				"Error":     err.Error(),
+			}).Error("Failed to sync workflow execution status.")
+		}
+	}
+
+	source := &cache.ListWatch{ListFunc: listFunc, WatchFunc: watchFunc}
+	_, controller := cache.NewInformer(
+		source,
+		&wfv1.Workflow{},
+		0,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: syncStatus,
+			UpdateFunc: func(old, new interface{}) {
+				oldWf := old.(*wfv1.Workflow)
+				newWf := new.(*wfv1.Workflow)
+				if oldWf.ResourceVersion == newWf.ResourceVersion {
+					return
+				}
+				syncStatus(newWf)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				wf, ok := obj.(*wfv1.Workflow)
+				if !ok {
+					return
+				}
+				if err := client.FailMissingWorkflowExecution(wf.Namespace, wf.Name); err != nil {
+					log.WithFields(log.Fields{
+						"Namespace": wf.Namespace,
+						"UID":       wf.Name,
+						"Error":     err.Error(),
+					}).Error("Failed to update deleted workflow execution.")
+				}
+			},
+		})
+
+	controller.Run(stopCh)
+}
+
+// reconcilePeriodically calls reconcile with a client every interval, until stopCh is closed.
+// name is used in the logs.
+func reconcilePeriodically(name string, interval time.Duration, reconcile func(client *v1.Client) error, db *v1.DB, kubeConfig *v1.Config, sysConfig v1.SystemConfig, stopCh <-chan struct{}) {
+	client, err := v1.NewClient(kubeConfig, db, sysConfig)
+	if err != nil {
+		log.Errorf("Failed to create %v client: %v", name, err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := reconcile(client); err != nil {
+				log.Errorf("Failed to run %v: %v", name, err)
+			}
+		}
+	}
+}
+
 // customHeaderMatcher is used to allow certain headers so we don't require a grpc-gateway prefix
 func customHeaderMatcher(key string) (string, bool) {
 	lowerCaseKey := strings.ToLower(key)
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// privateNetworks are the private address ranges of IPv4, RFC 1918, and of IPv6, RFC 4193
+var privateNetworks = parseNetworks("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+// parseNetworks parses CIDRs that are known to be valid
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks
+}
+
+// isExternalAddress returns false for the addresses of the cluster and its hosts: loopback, link-local,
+// which includes the metadata services of clouds, private and unspecified addresses.
+// Requests the server makes to addresses given by users, like notifications and git repositories, are only sent to external addresses.
+func isExternalAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isExternalHost resolves host and returns false if it is localhost, or if any of its addresses is not external.
+// A host that can't be resolved is not refused, as the request to it fails anyway.
+func isExternalHost(ctx context.Context, host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return isExternalAddress(ip)
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return true
+	}
+	for _, address := range addresses {
+		if !isExternalAddress(address.IP) {
+			return false
+		}
+	}
+
+	return true
+}
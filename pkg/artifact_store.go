@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArtifactStore is the storage of an artifact repository, like a S3 or GCS bucket.
+// Keys are '/' separated paths relative to the root of the repository.
+// Errors for artifacts that do not exist are returned as a UserError with codes.NotFound.
+type ArtifactStore interface {
+	// Get returns a stream of length bytes of the artifact, starting at offset.
+	// If length is 0, the artifact is read until the end. A negative offset reads the last -offset bytes.
+	// The caller is responsible for closing the stream.
+	Get(key string, offset, length int64) (io.ReadCloser, error)
+	// List returns a page of the files and directories directly under the directory prefix, in lexicographic order of their paths.
+	// prefix is expected to be empty or end with a '/'. nextContinuationToken is empty for the last page.
+	List(prefix string, opts ArtifactListOptions) (files []*File, nextContinuationToken string, err error)
+	// Stat returns the information of the artifact, without reading its content.
+	Stat(key string) (*File, error)
+	// Put writes the content of reader into the artifact and returns the number of bytes written.
+	// size may be -1 if it is not known.
+	Put(key string, reader io.Reader, size int64, contentType string) (int64, error)
+	// Delete removes the artifact.
+	Delete(key string) error
+	// Presign returns a URL that can be used to download the artifact without authenticating, until expiry has passed.
+	Presign(key string, expiry time.Duration) (string, error)
+}
+
+// ArtifactListOptions are the options to list a page of the files of a directory in an ArtifactStore
+type ArtifactListOptions struct {
+	// PageSize is the maximum number of files in the page, the store may return fewer.
+	// If it is 0, the default of the store is used.
+	PageSize int
+	// ContinuationToken is the token returned with the previous page, it is empty for the first page
+	ContinuationToken string
+}
+
+// ArtifactFolderSizer is implemented by the ArtifactStores that can calculate the size of a directory.
+// Object stores list all of the objects under the directory to add up their sizes, a request for each page of objects.
+type ArtifactFolderSizer interface {
+	// FolderSize returns the total size of the files under the directory prefix, including subdirectories
+	FolderSize(prefix string) (int64, error)
+}
+
+// GetArtifactStore returns the ArtifactStore of the artifact repository configured for the namespace.
+func (c *Client) GetArtifactStore(namespace string) (store ArtifactStore, err error) {
+	config, err := c.GetNamespaceConfig(namespace)
+	if err != nil {
+		return
+	}
+
+	return c.getArtifactStore(namespace, &config.ArtifactRepository)
+}
+
+// getArtifactStore returns the ArtifactStore for the configured provider of the artifact repository.
+func (c *Client) getArtifactStore(namespace string, config *ArtifactRepositoryProvider) (store ArtifactStore, err error) {
+	switch {
+	case config.S3 != nil:
+		s3Client, err := c.GetS3Client(namespace, config.S3)
+		if err != nil {
+			return nil, util.NewUserError(codes.Unavailable, "Can't connect to S3 storage.")
+		}
+		store = &s3ArtifactStore{
+			client: s3Client,
+			bucket: config.S3.Bucket,
+		}
+	case config.GCS != nil:
+		gcsClient, err := c.GetGCSClient(namespace, config.GCS)
+		if err != nil {
+			return nil, util.NewUserError(codes.Unavailable, "Can't connect to GCS storage.")
+		}
+		store = &gcsArtifactStore{
+			client:             gcsClient,
+			bucket:             config.GCS.Bucket,
+			serviceAccountJSON: config.GCS.ServiceAccountJSON,
+		}
+	case config.Azure != nil:
+		azureClient, err := c.GetAzureClient(namespace, config.Azure)
+		if err != nil {
+			return nil, util.NewUserError(codes.Unavailable, "Can't connect to Azure storage.")
+		}
+		store = &azureArtifactStore{
+			client: azureClient,
+		}
+	case config.Filesystem != nil:
+		store = NewFilesystemArtifactStore(config.Filesystem.Path)
+	default:
+		return nil, util.NewUserError(codes.FailedPrecondition, "Artifact repository is not configured.")
+	}
+
+	return
+}
+
+// artifactStoreError returns err if it is a UserError, like codes.NotFound.
+// Any other error is logged and replaced with an error with codes.Unknown and message.
+func artifactStoreError(err error, namespace, key, message string) error {
+	if _, ok := err.(*util.UserError); ok {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"Namespace": namespace,
+		"Key":       key,
+		"Error":     err.Error(),
+	}).Error(message)
+
+	return util.NewUserError(codes.Unknown, message)
+}
+
+// artifactNotFoundError is returned by the ArtifactStore implementations for artifacts that do not exist
+func artifactNotFoundError() error {
+	return util.NewUserError(codes.NotFound, "Artifact does not exist.")
+}
+
+// sortFilesByPath sorts files in the lexicographic order of their paths, the order of ArtifactStore.List
+func sortFilesByPath(files []*File) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
+
+// deleteArtifactDirectory deletes the artifacts under the directory prefix, including subdirectories.
+// It returns the number of artifacts deleted.
+func deleteArtifactDirectory(store ArtifactStore, prefix string) (deleted int, err error) {
+	opts := ArtifactListOptions{}
+	for {
+		files, nextContinuationToken, err := store.List(prefix, opts)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, file := range files {
+			if file.Directory {
+				path := file.Path
+				if !strings.HasSuffix(path, "/") {
+					path += "/"
+				}
+
+				count, err := deleteArtifactDirectory(store, path)
+				deleted += count
+				if err != nil {
+					return deleted, err
+				}
+				continue
+			}
+
+			if err := store.Delete(file.Path); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if nextContinuationToken == "" {
+			return deleted, nil
+		}
+		opts.ContinuationToken = nextContinuationToken
+	}
+}
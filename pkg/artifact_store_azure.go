@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"context"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/onepanelio/core/pkg/util/azure"
+	"io"
+	"mime"
+	"strings"
+	"time"
+)
+
+const (
+	// azureUploadBufferSize is the size of the blocks of uploads to Azure Blob Storage. Each block is buffered in memory.
+	azureUploadBufferSize = 8 * 1024 * 1024
+	// azureUploadMaxBuffers is the number of blocks that are uploaded at the same time
+	azureUploadMaxBuffers = 2
+	// azureDownloadMaxRetries is the number of times a download is resumed after a connection failure
+	azureDownloadMaxRetries = 3
+)
+
+// azureArtifactStore is an ArtifactStore for a container of Azure Blob Storage
+type azureArtifactStore struct {
+	client *azure.Client
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	io.Reader
+	count int64
+}
+
+// Read reads from the underlying reader and adds the number of bytes read to count
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.Reader.Read(p)
+	r.count += int64(n)
+	return
+}
+
+// Get returns a stream of the blob, see ArtifactStore.Get
+func (a *azureArtifactStore) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	ctx := context.Background()
+	blobURL := a.client.NewBlobURL(key)
+
+	// Azure does not support suffix ranges, so the size is needed to find the offset
+	if offset < 0 {
+		file, err := a.Stat(key)
+		if err != nil {
+			return nil, err
+		}
+		offset += file.Size
+		if offset < 0 {
+			offset = 0
+		}
+		length = azblob.CountToEnd
+	}
+
+	resp, err := blobURL.Download(ctx, offset, length, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if azure.IsNotFound(err) {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: azureDownloadMaxRetries}), nil
+}
+
+// List returns a page of the blobs and prefixes under prefix, see ArtifactStore.List
+func (a *azureArtifactStore) List(prefix string, opts ArtifactListOptions) (files []*File, nextContinuationToken string, err error) {
+	marker := azblob.Marker{}
+	if opts.ContinuationToken != "" {
+		marker.Val = &opts.ContinuationToken
+	}
+	listOptions := azblob.ListBlobsSegmentOptions{
+		Prefix: prefix,
+	}
+	if opts.PageSize > 0 {
+		listOptions.MaxResults = int32(opts.PageSize)
+	}
+
+	resp, err := a.client.ListBlobsHierarchySegment(context.Background(), marker, "/", listOptions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	files = make([]*File, 0, len(resp.Segment.BlobPrefixes)+len(resp.Segment.BlobItems))
+	for _, blobPrefix := range resp.Segment.BlobPrefixes {
+		files = append(files, &File{
+			Path:      blobPrefix.Name,
+			Name:      FilePathToName(blobPrefix.Name),
+			Directory: true,
+		})
+	}
+
+	for _, blob := range resp.Segment.BlobItems {
+		if blob.Name == prefix {
+			continue
+		}
+
+		file := &File{
+			Path:         blob.Name,
+			Name:         FilePathToName(blob.Name),
+			Extension:    FilePathToExtension(blob.Name),
+			LastModified: blob.Properties.LastModified,
+		}
+		if blob.Properties.ContentLength != nil {
+			file.Size = *blob.Properties.ContentLength
+		}
+		if blob.Properties.ContentType != nil {
+			file.ContentType = *blob.Properties.ContentType
+		}
+		file.Directory = strings.HasSuffix(blob.Name, "/") && file.Size == 0
+
+		files = append(files, file)
+	}
+	sortFilesByPath(files)
+
+	if resp.NextMarker.NotDone() && resp.NextMarker.Val != nil {
+		nextContinuationToken = *resp.NextMarker.Val
+	}
+
+	return
+}
+
+// FolderSize returns the total size of the blobs under prefix, see ArtifactFolderSizer.
+// The blobs are listed flat, so subdirectories don't need requests of their own.
+func (a *azureArtifactStore) FolderSize(prefix string) (size int64, err error) {
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := a.client.ListBlobsFlatSegment(context.Background(), marker, azblob.ListBlobsSegmentOptions{
+			Prefix: prefix,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, blob := range resp.Segment.BlobItems {
+			if blob.Properties.ContentLength != nil {
+				size += *blob.Properties.ContentLength
+			}
+		}
+		marker = resp.NextMarker
+	}
+
+	return size, nil
+}
+
+// Stat returns the properties of the blob, see ArtifactStore.Stat
+func (a *azureArtifactStore) Stat(key string) (*File, error) {
+	props, err := a.client.NewBlobURL(key).GetProperties(context.Background(), azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if azure.IsNotFound(err) {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path:         key,
+		Name:         FilePathToName(key),
+		Extension:    FilePathToExtension(key),
+		Size:         props.ContentLength(),
+		LastModified: props.LastModified(),
+		ContentType:  props.ContentType(),
+	}, nil
+}
+
+// Put uploads the blob as a block blob, see ArtifactStore.Put
+// The blob is uploaded in blocks of azureUploadBufferSize, so it doesn't have to be held in memory.
+func (a *azureArtifactStore) Put(key string, reader io.Reader, size int64, contentType string) (int64, error) {
+	counter := &countingReader{Reader: reader}
+	_, err := azblob.UploadStreamToBlockBlob(context.Background(), counter, a.client.NewBlockBlobURL(key), azblob.UploadStreamToBlockBlobOptions{
+		BufferSize: azureUploadBufferSize,
+		MaxBuffers: azureUploadMaxBuffers,
+		BlobHTTPHeaders: azblob.BlobHTTPHeaders{
+			ContentType: contentType,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return counter.count, nil
+}
+
+// Delete removes the blob and its snapshots, see ArtifactStore.Delete
+func (a *azureArtifactStore) Delete(key string) error {
+	_, err := a.client.NewBlobURL(key).Delete(context.Background(), azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{})
+	if azure.IsNotFound(err) {
+		return artifactNotFoundError()
+	}
+
+	return err
+}
+
+// Presign returns a GET URL of the blob with a shared access signature, see ArtifactStore.Presign
+func (a *azureArtifactStore) Presign(key string, expiry time.Duration) (string, error) {
+	contentDisposition := mime.FormatMediaType("attachment", map[string]string{"filename": FilePathToName(key)})
+
+	return a.client.SignedURL(key, expiry, contentDisposition)
+}
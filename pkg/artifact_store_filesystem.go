@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/onepanelio/core/pkg/util"
+	"google.golang.org/grpc/codes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// filesystemUploadPattern is the pattern of the temporary files of uploads in progress, like .model.bin.123456.upload.
+// They are created next to the file they are written to, see filesystemArtifactStore.Put.
+const filesystemUploadPattern = ".%v.*.upload"
+
+// reFilesystemUpload matches the names of the temporary files of uploads in progress, see filesystemUploadPattern
+var reFilesystemUpload = regexp.MustCompile(`^\..+\.[0-9]+\.upload$`)
+
+// filesystemArtifactStore is an ArtifactStore for a directory, like the mount path of a PersistentVolumeClaim.
+// Keys are mapped to files under the root directory.
+type filesystemArtifactStore struct {
+	root string
+}
+
+// NewFilesystemArtifactStore returns an ArtifactStore that keeps the artifacts in files under root
+func NewFilesystemArtifactStore(root string) ArtifactStore {
+	return &filesystemArtifactStore{
+		root: root,
+	}
+}
+
+// limitedReadCloser reads at most N bytes of the underlying file and closes it
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// filePath returns the path of the file of key. Keys can not point outside of the root directory.
+func (f *filesystemArtifactStore) filePath(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+// fileFromInfo returns the File with the information of the file at key
+func fileFromInfo(key string, info os.FileInfo) *File {
+	file := &File{
+		Path:         key,
+		Name:         FilePathToName(key),
+		Extension:    FilePathToExtension(key),
+		Size:         info.Size(),
+		LastModified: info.ModTime().UTC(),
+		Directory:    info.IsDir(),
+	}
+	if info.IsDir() {
+		file.Size = 0
+		file.Extension = ""
+	} else {
+		file.ContentType = mime.TypeByExtension(path.Ext(key))
+	}
+
+	return file
+}
+
+// Get returns a stream of the file, see ArtifactStore.Get
+func (f *filesystemArtifactStore) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	file, err := os.Open(f.filePath(key))
+	if os.IsNotExist(err) {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, artifactNotFoundError()
+	}
+
+	if offset < 0 {
+		offset += info.Size()
+		if offset < 0 {
+			offset = 0
+		}
+		length = 0
+	}
+	if offset > info.Size() {
+		file.Close()
+		return nil, util.NewUserError(codes.OutOfRange, "Invalid range.")
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if length <= 0 {
+		return file, nil
+	}
+
+	return &limitedReadCloser{
+		Reader: io.LimitReader(file, length),
+		Closer: file,
+	}, nil
+}
+
+// List returns a page of the files and directories in the directory prefix, see ArtifactStore.List
+// Uploads in progress are not listed. The continuation token is the path of the last file of the previous page.
+func (f *filesystemArtifactStore) List(prefix string, opts ArtifactListOptions) (files []*File, nextContinuationToken string, err error) {
+	files = make([]*File, 0)
+
+	infos, err := ioutil.ReadDir(f.filePath(prefix))
+	if os.IsNotExist(err) {
+		return files, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, info := range infos {
+		if !info.IsDir() && reFilesystemUpload.MatchString(info.Name()) {
+			continue
+		}
+
+		key := prefix + info.Name()
+		if info.IsDir() {
+			key += "/"
+		}
+
+		files = append(files, fileFromInfo(key, info))
+	}
+	sortFilesByPath(files)
+
+	if opts.ContinuationToken != "" {
+		start := sort.Search(len(files), func(i int) bool {
+			return files[i].Path > opts.ContinuationToken
+		})
+		files = files[start:]
+	}
+
+	if opts.PageSize > 0 && len(files) > opts.PageSize {
+		files = files[:opts.PageSize]
+		nextContinuationToken = files[len(files)-1].Path
+	}
+
+	return
+}
+
+// FolderSize returns the total size of the files under the directory prefix, without uploads in progress, see ArtifactFolderSizer
+func (f *filesystemArtifactStore) FolderSize(prefix string) (size int64, err error) {
+	err = filepath.Walk(f.filePath(prefix), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !reFilesystemUpload.MatchString(info.Name()) {
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, artifactNotFoundError()
+	}
+
+	return
+}
+
+// Stat returns the information of the file, see ArtifactStore.Stat
+func (f *filesystemArtifactStore) Stat(key string) (*File, error) {
+	info, err := os.Stat(f.filePath(key))
+	if os.IsNotExist(err) {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fileFromInfo(key, info), nil
+}
+
+// Put writes the file, see ArtifactStore.Put
+// The content is written to a temporary file first, so readers never see a partially written file.
+func (f *filesystemArtifactStore) Put(key string, reader io.Reader, size int64, contentType string) (written int64, err error) {
+	filePath := f.filePath(key)
+	if strings.HasSuffix(key, "/") || filePath == filepath.Clean(f.root) {
+		return 0, util.NewUserError(codes.InvalidArgument, "Invalid key.")
+	}
+
+	dir := filepath.Dir(filePath)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	tmpFile, err := ioutil.TempFile(dir, fmt.Sprintf(filesystemUploadPattern, filepath.Base(filePath)))
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+
+	written, err = io.Copy(tmpFile, reader)
+	if err != nil {
+		tmpFile.Close()
+		return 0, err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return 0, err
+	}
+
+	if err = os.Rename(tmpFile.Name(), filePath); err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+// Delete removes the file, see ArtifactStore.Delete
+func (f *filesystemArtifactStore) Delete(key string) error {
+	err := os.Remove(f.filePath(key))
+	if os.IsNotExist(err) {
+		return artifactNotFoundError()
+	}
+
+	return err
+}
+
+// Presign is not supported by the filesystem, files can only be downloaded through the API.
+func (f *filesystemArtifactStore) Presign(key string, expiry time.Duration) (string, error) {
+	return "", util.NewUserError(codes.Unimplemented, "Artifact URLs are not supported by the artifact repository.")
+}
@@ -0,0 +1,204 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testFilesystemArtifactStore(t *testing.T) (store ArtifactStore, root string) {
+	root, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(root)
+	})
+
+	return NewFilesystemArtifactStore(root), root
+}
+
+// TestFilesystemArtifactStore_Put makes sure files are written under the root, including missing directories
+func TestFilesystemArtifactStore_Put(t *testing.T) {
+	store, root := testFilesystemArtifactStore(t)
+
+	written, err := store.Put("a/b/test.txt", strings.NewReader("hello world"), -1, "text/plain")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), written)
+
+	content, err := ioutil.ReadFile(filepath.Join(root, "a", "b", "test.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	// Keys can't escape the root
+	_, err = store.Put("../../outside.txt", strings.NewReader("hello"), -1, "text/plain")
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(root, "outside.txt"))
+	assert.Nil(t, err)
+}
+
+// TestFilesystemArtifactStore_Get makes sure ranges are read correctly
+func TestFilesystemArtifactStore_Get(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	_, err := store.Put("test.txt", strings.NewReader("0123456789"), 10, "text/plain")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		offset   int64
+		length   int64
+		expected string
+	}{
+		{0, 0, "0123456789"},
+		{2, 3, "234"},
+		{8, 0, "89"},
+		{8, 10, "89"},
+		{-3, 0, "789"},
+		{-20, 0, "0123456789"},
+	}
+	for _, test := range tests {
+		stream, err := store.Get("test.txt", test.offset, test.length)
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(stream)
+		assert.Nil(t, err)
+		assert.Nil(t, stream.Close())
+		assert.Equal(t, test.expected, string(content))
+	}
+
+	_, err = store.Get("test.txt", 11, 0)
+	assert.Equal(t, codes.OutOfRange, err.(*util.UserError).Code)
+
+	_, err = store.Get("missing.txt", 0, 0)
+	assert.Equal(t, codes.NotFound, err.(*util.UserError).Code)
+}
+
+// TestFilesystemArtifactStore_List makes sure only the direct children of the prefix are listed
+func TestFilesystemArtifactStore_List(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	for _, key := range []string{"a/1.txt", "a/b/2.txt", "c.json"} {
+		_, err := store.Put(key, strings.NewReader("{}"), 2, "")
+		assert.Nil(t, err)
+	}
+
+	files, _, err := store.List("", ArtifactListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "a/", files[0].Path)
+	assert.True(t, files[0].Directory)
+	assert.Equal(t, "c.json", files[1].Path)
+	assert.Equal(t, "json", files[1].Extension)
+	assert.Equal(t, int64(2), files[1].Size)
+
+	files, _, err = store.List("a/", ArtifactListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "a/1.txt", files[0].Path)
+	assert.Equal(t, "a/b/", files[1].Path)
+
+	files, _, err = store.List("missing/", ArtifactListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, files, 0)
+}
+
+// TestFilesystemArtifactStore_ListPages makes sure all files are returned once when listing page by page
+func TestFilesystemArtifactStore_ListPages(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	for _, key := range []string{"1.txt", "2.txt", "3.txt", "4/5.txt", "6.txt"} {
+		_, err := store.Put(key, strings.NewReader("{}"), 2, "")
+		assert.Nil(t, err)
+	}
+
+	paths := make([]string, 0)
+	token := ""
+	for pages := 1; ; pages++ {
+		files, nextToken, err := store.List("", ArtifactListOptions{PageSize: 2, ContinuationToken: token})
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, len(files), 2)
+		for _, file := range files {
+			paths = append(paths, file.Path)
+		}
+
+		if nextToken == "" {
+			assert.Equal(t, 3, pages)
+			break
+		}
+		token = nextToken
+	}
+
+	assert.Equal(t, []string{"1.txt", "2.txt", "3.txt", "4/", "6.txt"}, paths)
+}
+
+// TestFilesystemArtifactStore_FolderSize makes sure the sizes of the files in subdirectories are included
+func TestFilesystemArtifactStore_FolderSize(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	for _, key := range []string{"a/1.txt", "a/b/2.txt", "c.txt"} {
+		_, err := store.Put(key, strings.NewReader("12345"), 5, "")
+		assert.Nil(t, err)
+	}
+
+	size, err := store.(ArtifactFolderSizer).FolderSize("a/")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), size)
+
+	_, err = store.(ArtifactFolderSizer).FolderSize("missing/")
+	assert.Equal(t, codes.NotFound, err.(*util.UserError).Code)
+}
+
+// TestFilesystemArtifactStore_Uploads makes sure the temporary files of uploads in progress are not listed or counted
+func TestFilesystemArtifactStore_Uploads(t *testing.T) {
+	store, root := testFilesystemArtifactStore(t)
+
+	_, err := store.Put("a/model.bin", strings.NewReader("12345"), 5, "")
+	assert.Nil(t, err)
+	upload, err := ioutil.TempFile(filepath.Join(root, "a"), fmt.Sprintf(filesystemUploadPattern, "next.bin"))
+	assert.Nil(t, err)
+	_, err = upload.WriteString("123")
+	assert.Nil(t, err)
+	assert.Nil(t, upload.Close())
+
+	// Files that only look like hidden files are still listed
+	_, err = store.Put("a/.model.bin.2", strings.NewReader("1"), 1, "")
+	assert.Nil(t, err)
+
+	files, _, err := store.List("a/", ArtifactListOptions{})
+	assert.Nil(t, err)
+	paths := make([]string, 0)
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{"a/.model.bin.2", "a/model.bin"}, paths)
+
+	size, err := store.(ArtifactFolderSizer).FolderSize("a/")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), size)
+}
+
+// TestFilesystemArtifactStore_Delete makes sure files are removed and missing files are reported
+func TestFilesystemArtifactStore_Delete(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	_, err := store.Put("test.txt", strings.NewReader("test"), 4, "text/plain")
+	assert.Nil(t, err)
+
+	file, err := store.Stat("test.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), file.Size)
+	assert.Equal(t, "test.txt", file.Name)
+
+	assert.Nil(t, store.Delete("test.txt"))
+
+	_, err = store.Stat("test.txt")
+	assert.Equal(t, codes.NotFound, err.(*util.UserError).Code)
+
+	err = store.Delete("test.txt")
+	assert.Equal(t, codes.NotFound, err.(*util.UserError).Code)
+}
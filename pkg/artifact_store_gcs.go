@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"cloud.google.com/go/storage"
+	"context"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/gcs"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// gcsUploadChunkSize is the size of the chunks of resumable uploads to GCS. Each chunk is buffered in memory.
+	gcsUploadChunkSize = 16 * 1024 * 1024
+	// gcsListPageSize is the number of objects listed at once, if no page size is requested
+	gcsListPageSize = 1000
+)
+
+// gcsArtifactStore is an ArtifactStore for a GCS bucket
+type gcsArtifactStore struct {
+	client             *gcs.Client
+	bucket             string
+	serviceAccountJSON string
+}
+
+// Get returns a stream of the object, see ArtifactStore.Get
+func (g *gcsArtifactStore) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	gcsLength := length
+	if gcsLength == 0 {
+		gcsLength = -1
+	}
+
+	stream, err := g.client.Bucket(g.bucket).Object(key).NewRangeReader(context.Background(), offset, gcsLength)
+	if err == storage.ErrObjectNotExist || err == storage.ErrBucketNotExist {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// List returns a page of the objects and prefixes under prefix, see ArtifactStore.List
+func (g *gcsArtifactStore) List(prefix string, opts ArtifactListOptions) (files []*File, nextContinuationToken string, err error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = gcsListPageSize
+	}
+
+	objects := g.client.Bucket(g.bucket).Objects(context.Background(), &storage.Query{
+		Delimiter: "/",
+		Prefix:    prefix,
+		Versions:  false,
+	})
+
+	page := make([]*storage.ObjectAttrs, 0, pageSize)
+	nextContinuationToken, err = iterator.NewPager(objects, pageSize, opts.ContinuationToken).NextPage(&page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	files = make([]*File, 0, len(page))
+	for _, attrs := range page {
+		// Prefixes are returned with only the Prefix set
+		if attrs.Prefix != "" {
+			files = append(files, &File{
+				Path:      attrs.Prefix,
+				Name:      FilePathToName(attrs.Prefix),
+				Directory: true,
+			})
+			continue
+		}
+
+		if attrs.Name == prefix {
+			continue
+		}
+
+		isDirectory := (attrs.Etag == "" || strings.HasSuffix(attrs.Name, "/")) && attrs.Size == 0
+
+		files = append(files, &File{
+			Path:         attrs.Name,
+			Name:         FilePathToName(attrs.Name),
+			Extension:    FilePathToExtension(attrs.Name),
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+			ContentType:  attrs.ContentType,
+			Directory:    isDirectory,
+		})
+	}
+	sortFilesByPath(files)
+
+	return
+}
+
+// FolderSize returns the total size of the objects under prefix, see ArtifactFolderSizer.
+// The objects are listed without a delimiter, so subdirectories don't need requests of their own.
+func (g *gcsArtifactStore) FolderSize(prefix string) (size int64, err error) {
+	objects := g.client.Bucket(g.bucket).Objects(context.Background(), &storage.Query{
+		Prefix:   prefix,
+		Versions: false,
+	})
+
+	for {
+		attrs, err := objects.Next()
+		if err == iterator.Done {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		size += attrs.Size
+	}
+}
+
+// Stat returns the information of the object, see ArtifactStore.Stat
+func (g *gcsArtifactStore) Stat(key string) (*File, error) {
+	attrs, err := g.client.Bucket(g.bucket).Object(key).Attrs(context.Background())
+	if err == storage.ErrObjectNotExist || err == storage.ErrBucketNotExist {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path:         attrs.Name,
+		Name:         FilePathToName(attrs.Name),
+		Extension:    FilePathToExtension(attrs.Name),
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+		ContentType:  attrs.ContentType,
+	}, nil
+}
+
+// Put uploads the object, see ArtifactStore.Put
+// The object is written with a resumable upload in chunks of gcsUploadChunkSize, so it doesn't have to be held in memory.
+func (g *gcsArtifactStore) Put(key string, reader io.Reader, size int64, contentType string) (written int64, err error) {
+	writer := g.client.Bucket(g.bucket).Object(key).NewWriter(context.Background())
+	writer.ContentType = contentType
+	writer.ChunkSize = gcsUploadChunkSize
+	if written, err = io.Copy(writer, reader); err != nil {
+		writer.Close()
+		return 0, err
+	}
+	if err = writer.Close(); err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+// Delete removes the object, see ArtifactStore.Delete
+func (g *gcsArtifactStore) Delete(key string) error {
+	err := g.client.Bucket(g.bucket).Object(key).Delete(context.Background())
+	if err == storage.ErrObjectNotExist || err == storage.ErrBucketNotExist {
+		return artifactNotFoundError()
+	}
+
+	return err
+}
+
+// Presign returns a signed GET URL of the object, see ArtifactStore.Presign
+func (g *gcsArtifactStore) Presign(key string, expiry time.Duration) (string, error) {
+	if g.serviceAccountJSON == "" {
+		return "", util.NewUserError(codes.FailedPrecondition, "A service account key is required to sign artifact URLs.")
+	}
+
+	return gcs.SignedURL(g.serviceAccountJSON, g.bucket, key, expiry)
+}
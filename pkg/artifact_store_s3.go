@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/s3"
+	"google.golang.org/grpc/codes"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3UnknownSizePartSize is the part size of uploads whose size is not known. Each part is buffered in memory,
+// and an upload has at most 10000 parts, so these objects can be up to 320GiB.
+// Without it, the parts are sized for the largest object S3 allows, 5TiB.
+const s3UnknownSizePartSize = 32 * 1024 * 1024
+
+// s3ArtifactStore is an ArtifactStore for a S3 bucket
+type s3ArtifactStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// Get returns a stream of the object, see ArtifactStore.Get
+func (s *s3ArtifactStore) Get(key string, offset, length int64) (stream io.ReadCloser, err error) {
+	opts := s3.GetObjectOptions{}
+	if offset < 0 {
+		err = opts.SetRange(0, offset)
+	} else if length > 0 {
+		err = opts.SetRange(offset, offset+length-1)
+	} else if offset > 0 {
+		err = opts.SetRange(offset, 0)
+	}
+	if err != nil {
+		return nil, util.NewUserError(codes.OutOfRange, "Invalid range.")
+	}
+
+	stream, err = s.client.GetObject(s.bucket, key, opts)
+	if s3.IsNotFound(err) {
+		return nil, artifactNotFoundError()
+	}
+
+	return
+}
+
+// List returns a page of the objects and common prefixes under prefix, see ArtifactStore.List
+func (s *s3ArtifactStore) List(prefix string, opts ArtifactListOptions) (files []*File, nextContinuationToken string, err error) {
+	result, err := s3.Core{Client: s.client.Client}.ListObjectsV2(s.bucket, prefix, opts.ContinuationToken, false, "/", opts.PageSize, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	files = make([]*File, 0, len(result.CommonPrefixes)+len(result.Contents))
+	for _, commonPrefix := range result.CommonPrefixes {
+		files = append(files, &File{
+			Path:      commonPrefix.Prefix,
+			Name:      FilePathToName(commonPrefix.Prefix),
+			Directory: true,
+		})
+	}
+
+	for _, objInfo := range result.Contents {
+		if objInfo.Key == prefix {
+			continue
+		}
+
+		isDirectory := (objInfo.ETag == "" || strings.HasSuffix(objInfo.Key, "/")) && objInfo.Size == 0
+
+		files = append(files, &File{
+			Path:         objInfo.Key,
+			Name:         FilePathToName(objInfo.Key),
+			Extension:    FilePathToExtension(objInfo.Key),
+			Size:         objInfo.Size,
+			LastModified: objInfo.LastModified,
+			ContentType:  objInfo.ContentType,
+			Directory:    isDirectory,
+		})
+	}
+	sortFilesByPath(files)
+
+	if result.IsTruncated {
+		nextContinuationToken = result.NextContinuationToken
+	}
+
+	return
+}
+
+// FolderSize returns the total size of the objects under prefix, see ArtifactFolderSizer.
+// The objects are listed without a delimiter, so subdirectories don't need requests of their own.
+func (s *s3ArtifactStore) FolderSize(prefix string) (size int64, err error) {
+	core := s3.Core{Client: s.client.Client}
+	continuationToken := ""
+	for {
+		result, err := core.ListObjectsV2(s.bucket, prefix, continuationToken, false, "", 0, "")
+		if err != nil {
+			return 0, err
+		}
+
+		for _, objInfo := range result.Contents {
+			size += objInfo.Size
+		}
+
+		if !result.IsTruncated {
+			return size, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Stat returns the information of the object, see ArtifactStore.Stat
+func (s *s3ArtifactStore) Stat(key string) (*File, error) {
+	objInfo, err := s.client.StatObject(s.bucket, key, s3.StatObjectOptions{})
+	if s3.IsNotFound(err) {
+		return nil, artifactNotFoundError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path:         objInfo.Key,
+		Name:         FilePathToName(objInfo.Key),
+		Extension:    FilePathToExtension(objInfo.Key),
+		Size:         objInfo.Size,
+		LastModified: objInfo.LastModified,
+		ContentType:  objInfo.ContentType,
+	}, nil
+}
+
+// Put uploads the object, see ArtifactStore.Put
+// Large objects are uploaded with a multipart upload, so they don't have to be held in memory.
+func (s *s3ArtifactStore) Put(key string, reader io.Reader, size int64, contentType string) (int64, error) {
+	opts := s3.PutObjectOptions{
+		ContentType: contentType,
+	}
+	if size < 0 {
+		opts.PartSize = s3UnknownSizePartSize
+	}
+
+	return s.client.PutObject(s.bucket, key, reader, size, opts)
+}
+
+// Delete removes the object, see ArtifactStore.Delete
+func (s *s3ArtifactStore) Delete(key string) error {
+	if _, err := s.Stat(key); err != nil {
+		return err
+	}
+
+	return s.client.RemoveObject(s.bucket, key)
+}
+
+// Presign returns a pre-signed GET URL of the object, see ArtifactStore.Presign
+func (s *s3ArtifactStore) Presign(key string, expiry time.Duration) (string, error) {
+	reqParams := url.Values{}
+	reqParams.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": FilePathToName(key)}))
+	presignedURL, err := s.client.PresignedGetObject(s.bucket, key, expiry, reqParams)
+	if err != nil {
+		return "", err
+	}
+
+	return presignedURL.String(), nil
+}
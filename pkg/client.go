@@ -5,6 +5,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	argoprojv1alpha1 "github.com/argoproj/argo/pkg/client/clientset/versioned/typed/workflow/v1alpha1"
 	"github.com/jmoiron/sqlx"
+	"github.com/onepanelio/core/pkg/util/azure"
 	"github.com/onepanelio/core/pkg/util/env"
 	"github.com/onepanelio/core/pkg/util/gcs"
 	"github.com/onepanelio/core/pkg/util/router"
@@ -130,6 +131,24 @@ func (c *Client) GetGCSClient(namespace string, config *ArtifactRepositoryGCSPro
 	return gcs.NewClient(namespace, config.ServiceAccountJSON)
 }
 
+// GetAzureClient initializes a client to a container of Azure Blob Storage.
+func (c *Client) GetAzureClient(namespace string, config *ArtifactRepositoryAzureProvider) (azureClient *azure.Client, err error) {
+	azureClient, err = azure.NewClient(azure.Config{
+		AccountName: config.AccountName,
+		AccountKey:  config.AccountKey,
+		Container:   config.Container,
+		Endpoint:    config.Endpoint,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"Error":     err.Error(),
+		}).Error("GetAzureClient failed when initializing a new Azure client.")
+		return
+	}
+	return
+}
+
 // GetWebRouter creates a new web router using the system configuration
 func (c *Client) GetWebRouter() (router.Web, error) {
 	sysConfig, err := c.GetSystemConfig()
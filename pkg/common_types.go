@@ -2,8 +2,14 @@ package v1
 
 import (
 	"fmt"
+	"github.com/onepanelio/core/pkg/util"
 	"github.com/onepanelio/core/pkg/util/ptr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"gopkg.in/yaml.v2"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 // +genclient
@@ -23,17 +29,33 @@ type Parameter struct {
 	Hint        *string            `json:"hint,omitempty" protobuf:"bytes,5,opt,name=hint"`
 	Options     []*ParameterOption `json:"options,omitempty" protobuf:"bytes,6,opt,name=options"`
 	Required    bool               `json:"required,omitempty" protobuf:"bytes,7,opt,name=required"`
+	// Min, Max and Step bound the value of an input.number parameter. Step is counted from Min, or 0.
+	Min  *float64 `json:"min,omitempty"`
+	Max  *float64 `json:"max,omitempty"`
+	Step *float64 `json:"step,omitempty"`
+	// Pattern is a regular expression the whole value of a text parameter must match
+	Pattern *string `json:"pattern,omitempty"`
 }
 
 // IsValidParameter returns nil if the parameter is valid or an error otherwise
 func IsValidParameter(parameter Parameter) error {
-	if parameter.Visibility == nil {
-		return nil
+	if parameter.Visibility != nil {
+		visibility := *parameter.Visibility
+		if visibility != "public" && visibility != "protected" && visibility != "internal" && visibility != "private" {
+			return fmt.Errorf("invalid visibility '%v' for parameter '%v'", visibility, parameter.Name)
+		}
 	}
 
-	visibility := *parameter.Visibility
-	if visibility != "public" && visibility != "protected" && visibility != "internal" && visibility != "private" {
-		return fmt.Errorf("invalid visibility '%v' for parameter '%v'", visibility, parameter.Name)
+	if parameter.Min != nil && parameter.Max != nil && *parameter.Min > *parameter.Max {
+		return fmt.Errorf("min of parameter '%v' is greater than its max", parameter.Name)
+	}
+	if parameter.Step != nil && *parameter.Step <= 0 {
+		return fmt.Errorf("step of parameter '%v' must be greater than 0", parameter.Name)
+	}
+	if parameter.Pattern != nil {
+		if _, err := compileParameterPattern(*parameter.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern for parameter '%v': %v", parameter.Name, err)
+		}
 	}
 
 	return nil
@@ -50,6 +72,100 @@ func IsValidParameters(parameters []Parameter) error {
 	return nil
 }
 
+// compileParameterPattern compiles the pattern of a parameter so that it matches the whole value
+func compileParameterPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+// parameterValueViolation returns why value is not valid for the parameter, or "" if it is
+func parameterValueViolation(parameter Parameter, value *string) string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		if parameter.Required {
+			return "is required"
+		}
+		return ""
+	}
+
+	switch parameter.Type {
+	case "select.select", "radio.radio":
+		if len(parameter.Options) == 0 {
+			return ""
+		}
+		for _, option := range parameter.Options {
+			if option.Value == *value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%v' is not one of the options", *value)
+	case "input.number":
+		number, err := strconv.ParseFloat(strings.TrimSpace(*value), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return fmt.Sprintf("'%v' is not a number", *value)
+		}
+		if parameter.Min != nil && number < *parameter.Min {
+			return fmt.Sprintf("must be at least %v", *parameter.Min)
+		}
+		if parameter.Max != nil && number > *parameter.Max {
+			return fmt.Sprintf("must be at most %v", *parameter.Max)
+		}
+		if parameter.Step != nil && *parameter.Step > 0 {
+			start := 0.0
+			if parameter.Min != nil {
+				start = *parameter.Min
+			}
+			steps := (number - start) / *parameter.Step
+			if math.Abs(steps-math.Round(steps)) > 1e-9 {
+				return fmt.Sprintf("must be in steps of %v from %v", *parameter.Step, start)
+			}
+		}
+	}
+
+	if parameter.Pattern != nil {
+		pattern, err := compileParameterPattern(*parameter.Pattern)
+		if err != nil {
+			return fmt.Sprintf("has an invalid pattern: %v", err)
+		}
+		if !pattern.MatchString(*value) {
+			return fmt.Sprintf("must match the pattern '%v'", *parameter.Pattern)
+		}
+	}
+
+	return ""
+}
+
+// ValidateParameterValues checks values against the parameters a template declares: required parameters,
+// options of select.select and radio.radio parameters, the min, max and step of input.number parameters,
+// and patterns. Declared parameters without a value use their default. Values of parameters that are not
+// declared, such as system parameters, are not checked.
+// The error is an InvalidArgument UserError with a field violation for each invalid parameter.
+func ValidateParameterValues(declared []Parameter, values []Parameter) error {
+	valuesByName := make(map[string]*string)
+	for _, value := range values {
+		valuesByName[value.Name] = value.Value
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0)
+	for _, parameter := range declared {
+		value := parameter.Value
+		if provided, ok := valuesByName[parameter.Name]; ok {
+			value = provided
+		}
+
+		if description := parameterValueViolation(parameter, value); description != "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "parameters." + parameter.Name,
+				Description: description,
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return util.NewFieldViolationsError("Invalid parameters.", violations)
+}
+
 // Arguments are the arguments in a manifest file.
 type Arguments struct {
 	Parameters []Parameter `json:"parameters"`
@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/ptr"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 	"testing"
 )
 
@@ -66,3 +69,87 @@ func TestParseParametersFromManifest(t *testing.T) {
 	// Make sure string values are correctly parsed
 	assert.Equal(t, *keyedParameters["extras"].Value, "none")
 }
+
+// TestIsValidParameter_Bounds makes sure min, max, step and pattern are checked when a template is parsed
+func TestIsValidParameter_Bounds(t *testing.T) {
+	assert.Nil(t, IsValidParameter(Parameter{Name: "epochs", Min: ptr.Float64(1), Max: ptr.Float64(10), Step: ptr.Float64(1)}))
+	assert.NotNil(t, IsValidParameter(Parameter{Name: "epochs", Min: ptr.Float64(10), Max: ptr.Float64(1)}))
+	assert.NotNil(t, IsValidParameter(Parameter{Name: "epochs", Step: ptr.Float64(0)}))
+	assert.NotNil(t, IsValidParameter(Parameter{Name: "image", Pattern: ptr.String("[a-z")}))
+
+	manifest := `arguments:
+  parameters:
+  - name: epochs
+    type: input.number
+    value: 10
+    min: 1
+    max: 100
+    step: 0.5
+    pattern: '\d+'
+`
+	parameters, err := ParseParametersFromManifest([]byte(manifest))
+	assert.Nil(t, err)
+	assert.Len(t, parameters, 1)
+	assert.Equal(t, 1.0, *parameters[0].Min)
+	assert.Equal(t, 100.0, *parameters[0].Max)
+	assert.Equal(t, 0.5, *parameters[0].Step)
+	assert.Equal(t, `\d+`, *parameters[0].Pattern)
+}
+
+// TestValidateParameterValues makes sure values are checked against the parameters a template declares
+func TestValidateParameterValues(t *testing.T) {
+	declared := []Parameter{
+		{Name: "dataset", Type: "input.text", Required: true},
+		{Name: "optimizer", Type: "select.select", Value: ptr.String("adam"), Options: []*ParameterOption{
+			{Name: "Adam", Value: "adam"},
+			{Name: "SGD", Value: "sgd"},
+		}},
+		{Name: "epochs", Type: "input.number", Value: ptr.String("10"), Min: ptr.Float64(1), Max: ptr.Float64(100)},
+		{Name: "learning-rate", Type: "input.number", Min: ptr.Float64(0.1), Step: ptr.Float64(0.1)},
+		{Name: "image", Type: "input.text", Pattern: ptr.String(`[a-z]+:[\w.-]+`)},
+		{Name: "sys-node-pool", Type: "select.nodepool", Value: ptr.String("default"), Required: true},
+	}
+
+	// Defaults are used for parameters without a value, and parameters that aren't declared are not checked
+	assert.Nil(t, ValidateParameterValues(declared, []Parameter{
+		{Name: "dataset", Value: ptr.String("datasets/mnist")},
+		{Name: "learning-rate", Value: ptr.String("0.3")},
+		{Name: "image", Value: ptr.String("tensorflow:2.3.0")},
+		{Name: "sys-host", Value: ptr.String("")},
+	}))
+
+	err := ValidateParameterValues(declared, []Parameter{
+		{Name: "dataset", Value: ptr.String(" ")},
+		{Name: "optimizer", Value: ptr.String("rmsprop")},
+		{Name: "epochs", Value: ptr.String("1000")},
+		{Name: "learning-rate", Value: ptr.String("0.25")},
+		{Name: "image", Value: ptr.String("tensorflow:2.3.0 && rm")},
+	})
+	assert.NotNil(t, err)
+
+	userErr, ok := err.(*util.UserError)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, userErr.Code)
+
+	violations := make(map[string]string)
+	for _, violation := range userErr.FieldViolations {
+		violations[violation.Field] = violation.Description
+	}
+	assert.Equal(t, map[string]string{
+		"parameters.dataset":       "is required",
+		"parameters.optimizer":     "'rmsprop' is not one of the options",
+		"parameters.epochs":        "must be at most 100",
+		"parameters.learning-rate": "must be in steps of 0.1 from 0.1",
+		"parameters.image":         `must match the pattern '[a-z]+:[\w.-]+'`,
+	}, violations)
+
+	details := userErr.GRPCStatus().Details()
+	assert.Len(t, details, 1)
+
+	err = ValidateParameterValues(declared, []Parameter{
+		{Name: "dataset", Value: ptr.String("datasets/mnist")},
+		{Name: "epochs", Value: ptr.String("ten")},
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid parameters. parameters.epochs: 'ten' is not a number", err.Error())
+}
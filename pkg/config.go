@@ -62,6 +62,38 @@ func (c *Client) GetDefaultConfig() (config *ConfigMap, err error) {
 	return
 }
 
+// GetNamespaceConcurrencyConfig returns the concurrency limits of the namespace.
+// Unlike GetNamespaceConfig, it does not need an artifact repository.
+func (c *Client) GetNamespaceConcurrencyConfig(namespace string) (config *ConcurrencyConfig, err error) {
+	configMap, err := c.getConfigMap(namespace, "onepanel")
+	if err != nil {
+		return nil, err
+	}
+
+	config = &ConcurrencyConfig{}
+	if err = yaml.Unmarshal([]byte(configMap.Data["concurrency"]), config); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid concurrency config.")
+	}
+
+	return
+}
+
+// GetNamespaceRetentionConfig returns the retention policies of the namespace.
+// Unlike GetNamespaceConfig, it does not need an artifact repository.
+func (c *Client) GetNamespaceRetentionConfig(namespace string) (config *RetentionConfig, err error) {
+	configMap, err := c.getConfigMap(namespace, "onepanel")
+	if err != nil {
+		return nil, err
+	}
+
+	config = &RetentionConfig{}
+	if err = yaml.Unmarshal([]byte(configMap.Data["retention"]), config); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid retention config.")
+	}
+
+	return
+}
+
 func (c *Client) GetNamespaceConfig(namespace string) (config *NamespaceConfig, err error) {
 	configMap, err := c.getConfigMap(namespace, "onepanel")
 	if err != nil {
@@ -76,7 +108,8 @@ func (c *Client) GetNamespaceConfig(namespace string) (config *NamespaceConfig,
 	}
 
 	err = yaml.Unmarshal([]byte(configMap.Data["artifactRepository"]), &config.ArtifactRepository)
-	if err != nil || (config.ArtifactRepository.S3 == nil && config.ArtifactRepository.GCS == nil) {
+	if err != nil || (config.ArtifactRepository.S3 == nil && config.ArtifactRepository.GCS == nil &&
+		config.ArtifactRepository.Azure == nil && config.ArtifactRepository.Filesystem == nil) {
 		return nil, util.NewUserError(codes.NotFound, "Artifact repository config not found.")
 	}
 
@@ -102,6 +135,13 @@ func (c *Client) GetNamespaceConfig(namespace string) (config *NamespaceConfig,
 			serviceJSON, _ := base64.StdEncoding.DecodeString(secret.Data[config.ArtifactRepository.GCS.ServiceAccountKeySecret.Key])
 			config.ArtifactRepository.GCS.ServiceAccountJSON = string(serviceJSON)
 		}
+	case config.ArtifactRepository.Azure != nil:
+		{
+			accountKey, _ := base64.StdEncoding.DecodeString(secret.Data[config.ArtifactRepository.Azure.AccountKeySecret.Key])
+			config.ArtifactRepository.Azure.AccountKey = string(accountKey)
+		}
+	case config.ArtifactRepository.Filesystem != nil:
+		// No credentials are needed
 	default:
 		return nil, util.NewUserError(codes.NotFound, "Artifact repository config not found.")
 	}
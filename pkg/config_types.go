@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8yaml "sigs.k8s.io/yaml"
 	"strings"
+	"time"
 )
 
 // SystemConfig is configuration loaded from kubernetes config and secrets that includes information about the
@@ -206,6 +207,51 @@ func (s SystemConfig) UpdateNodePoolOptions(parameters []Parameter) ([]Parameter
 	return result, nil
 }
 
+// WorkflowStatusCallbacksEnabled returns false if workflowStatusCallbacks is set to "false" in the config.
+// When enabled, workflows call back into the API to report their status. The server also syncs the status
+// from the argo workflows, so the callbacks can be turned off.
+func (s SystemConfig) WorkflowStatusCallbacksEnabled() bool {
+	value := s.GetValue("workflowStatusCallbacks")
+	if value == nil {
+		return true
+	}
+
+	return *value != "false"
+}
+
+// ArtifactURLExpiry returns how long pre-signed artifact URLs are valid for.
+// It is read from artifactURLExpiry as a duration, e.g. 30m, and defaults to 15 minutes.
+func (s SystemConfig) ArtifactURLExpiry() time.Duration {
+	defaultExpiry := 15 * time.Minute
+
+	value := s.GetValue("artifactURLExpiry")
+	if value == nil {
+		return defaultExpiry
+	}
+
+	expiry, err := time.ParseDuration(*value)
+	if err != nil || expiry <= 0 {
+		log.WithFields(log.Fields{
+			"Method": "SystemConfig.ArtifactURLExpiry",
+			"Value":  *value,
+		}).Warn("Invalid artifactURLExpiry, using the default.")
+		return defaultExpiry
+	}
+
+	return expiry
+}
+
+// ArtifactUploadPrefix returns the key prefix that uploaded artifacts are stored under.
+// It is read from artifactUploadPrefix and defaults to "uploads".
+func (s SystemConfig) ArtifactUploadPrefix() string {
+	value := s.GetValue("artifactUploadPrefix")
+	if value == nil || strings.Trim(*value, "/") == "" {
+		return "uploads"
+	}
+
+	return strings.Trim(*value, "/")
+}
+
 // HMACKey gets the HMAC value, or nil.
 func (s SystemConfig) HMACKey() []byte {
 	hmac := s.GetValue("hmac")
@@ -244,14 +290,34 @@ type ArtifactRepositoryGCSProvider struct {
 	ServiceAccountJSON      string                   `yaml:"serviceAccountJSON,omitempty"`
 }
 
-// ArtifactRepositoryProvider is used to setup access into AWS Cloud Storage
-// or Google Cloud storage.
-// - The relevant sub-struct (S3, GCS) is unmarshalled into from the cluster configmap.
-// Right now, either the S3 or GCS struct will be filled in. Multiple cloud
+// ArtifactRepositoryAzureProvider is used to access a container of Azure Blob Storage.
+// The account key is loaded from the AccountKeySecret.
+type ArtifactRepositoryAzureProvider struct {
+	KeyFormat        string `yaml:"keyFormat"`
+	Container        string
+	Endpoint         string
+	AccountName      string                   `yaml:"accountName"`
+	AccountKeySecret ArtifactRepositorySecret `yaml:"accountKeySecret"`
+	AccountKey       string                   `yaml:"accountKey,omitempty"`
+}
+
+// ArtifactRepositoryFilesystemProvider is used to store artifacts in a directory of the API server,
+// usually a mounted PersistentVolumeClaim.
+type ArtifactRepositoryFilesystemProvider struct {
+	KeyFormat string `yaml:"keyFormat"`
+	Path      string
+}
+
+// ArtifactRepositoryProvider is used to setup access into AWS Cloud Storage,
+// Google Cloud storage, Azure Blob Storage or a filesystem.
+// - The relevant sub-struct (S3, GCS, Azure, Filesystem) is unmarshalled into from the cluster configmap.
+// Right now, only one of the structs will be filled in. Multiple cloud
 // providers are not supported at the same time in params.yaml (manifests deployment).
 type ArtifactRepositoryProvider struct {
-	S3  *ArtifactRepositoryS3Provider  `yaml:"s3,omitempty"`
-	GCS *ArtifactRepositoryGCSProvider `yaml:"gcs,omitempty"`
+	S3         *ArtifactRepositoryS3Provider         `yaml:"s3,omitempty"`
+	GCS        *ArtifactRepositoryGCSProvider        `yaml:"gcs,omitempty"`
+	Azure      *ArtifactRepositoryAzureProvider      `yaml:"azure,omitempty"`
+	Filesystem *ArtifactRepositoryFilesystemProvider `yaml:"filesystem,omitempty"`
 }
 
 // ArtifactRepositorySecret holds information about a kubernetes Secret.
@@ -327,22 +393,90 @@ func (g *ArtifactRepositoryGCSProvider) MarshalToYaml() (string, error) {
 // {{workflow.name}} -> workflowName
 // {{pod.name}} -> podName
 func (a *ArtifactRepositoryS3Provider) FormatKey(namespace, workflowName, podName string) string {
-	keyFormat := a.KeyFormat
+	return formatArtifactKey(a.KeyFormat, namespace, workflowName, podName)
+}
 
-	keyFormat = strings.Replace(keyFormat, "{{workflow.namespace}}", namespace, -1)
-	keyFormat = strings.Replace(keyFormat, "{{workflow.name}}", workflowName, -1)
-	keyFormat = strings.Replace(keyFormat, "{{pod.name}}", podName, -1)
+// FormatKey replaces placeholder values with their actual values and returns this string.
+// {{workflow.namespace}} -> namespace
+// {{workflow.name}} -> workflowName
+// {{pod.name}} -> podName
+func (g *ArtifactRepositoryGCSProvider) FormatKey(namespace, workflowName, podName string) string {
+	return formatArtifactKey(g.KeyFormat, namespace, workflowName, podName)
+}
 
-	return keyFormat
+// FormatKey replaces placeholder values with their actual values and returns this string.
+// {{workflow.namespace}} -> namespace
+// {{workflow.name}} -> workflowName
+// {{pod.name}} -> podName
+func (a *ArtifactRepositoryAzureProvider) FormatKey(namespace, workflowName, podName string) string {
+	return formatArtifactKey(a.KeyFormat, namespace, workflowName, podName)
 }
 
 // FormatKey replaces placeholder values with their actual values and returns this string.
 // {{workflow.namespace}} -> namespace
 // {{workflow.name}} -> workflowName
 // {{pod.name}} -> podName
-func (g *ArtifactRepositoryGCSProvider) FormatKey(namespace, workflowName, podName string) string {
-	keyFormat := g.KeyFormat
+func (f *ArtifactRepositoryFilesystemProvider) FormatKey(namespace, workflowName, podName string) string {
+	return formatArtifactKey(f.KeyFormat, namespace, workflowName, podName)
+}
+
+// FormatKey formats the key with the KeyFormat of the configured provider. See ArtifactRepositoryS3Provider.FormatKey
+func (a *ArtifactRepositoryProvider) FormatKey(namespace, workflowName, podName string) string {
+	switch {
+	case a.S3 != nil:
+		return a.S3.FormatKey(namespace, workflowName, podName)
+	case a.GCS != nil:
+		return a.GCS.FormatKey(namespace, workflowName, podName)
+	case a.Azure != nil:
+		return a.Azure.FormatKey(namespace, workflowName, podName)
+	case a.Filesystem != nil:
+		return a.Filesystem.FormatKey(namespace, workflowName, podName)
+	}
+
+	return ""
+}
+
+// WorkflowKeyPrefix returns the directory of the artifacts of a workflow, like "artifacts/namespace/workflow-name/".
+// It is empty if the key format of the configured provider does not have such a directory.
+func (a *ArtifactRepositoryProvider) WorkflowKeyPrefix(namespace, workflowName string) string {
+	keyFormat := ""
+	switch {
+	case a.S3 != nil:
+		keyFormat = a.S3.KeyFormat
+	case a.GCS != nil:
+		keyFormat = a.GCS.KeyFormat
+	case a.Azure != nil:
+		keyFormat = a.Azure.KeyFormat
+	case a.Filesystem != nil:
+		keyFormat = a.Filesystem.KeyFormat
+	}
+
+	return workflowArtifactKeyPrefix(keyFormat, namespace, workflowName)
+}
+
+// workflowArtifactKeyPrefix returns the part of keyFormat up to the directory named after the workflow, see WorkflowKeyPrefix
+func workflowArtifactKeyPrefix(keyFormat, namespace, workflowName string) string {
+	placeholder := "{{workflow.name}}"
+	if podIndex := strings.Index(keyFormat, "{{pod.name}}"); podIndex >= 0 {
+		keyFormat = keyFormat[:podIndex]
+	}
+
+	index := strings.Index(keyFormat, placeholder)
+	if index < 0 || workflowName == "" {
+		return ""
+	}
+
+	// The workflow has to be a whole directory, so the prefix does not match other workflows with the same prefix
+	end := index + len(placeholder)
+	if end < len(keyFormat) && keyFormat[end] != '/' {
+		return ""
+	}
+
+	return formatArtifactKey(keyFormat[:end], namespace, workflowName, "") + "/"
+}
 
+// formatArtifactKey replaces the placeholders of keyFormat, see ArtifactRepositoryS3Provider.FormatKey
+func formatArtifactKey(keyFormat, namespace, workflowName, podName string) string {
 	keyFormat = strings.Replace(keyFormat, "{{workflow.namespace}}", namespace, -1)
 	keyFormat = strings.Replace(keyFormat, "{{workflow.name}}", workflowName, -1)
 	keyFormat = strings.Replace(keyFormat, "{{pod.name}}", podName, -1)
@@ -353,3 +487,37 @@ func (g *ArtifactRepositoryGCSProvider) FormatKey(namespace, workflowName, podNa
 type NamespaceConfig struct {
 	ArtifactRepository ArtifactRepositoryProvider
 }
+
+// ConcurrencyConfig limits how many workflow executions of a namespace can run at the same time.
+// Workflow executions over a limit are queued until others finish. A limit of 0 means there is none.
+// It is set in the "concurrency" key of the onepanel config map of the namespace, for example:
+//
+//	maxRunning: 20
+//	workflowTemplates:
+//	  train-model: 4
+type ConcurrencyConfig struct {
+	// MaxRunning is the maximum number of running workflow executions in the namespace
+	MaxRunning int `json:"maxRunning"`
+	// WorkflowTemplates maps workflow template uids to the maximum number of their running workflow executions
+	WorkflowTemplates map[string]int `json:"workflowTemplates"`
+}
+
+// HasLimits returns true if any limit is set
+func (c *ConcurrencyConfig) HasLimits() bool {
+	if c.MaxRunning > 0 {
+		return true
+	}
+
+	for _, limit := range c.WorkflowTemplates {
+		if limit > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WorkflowTemplateLimit returns the maximum number of running workflow executions of the workflow template, 0 if there is none
+func (c *ConcurrencyConfig) WorkflowTemplateLimit(workflowTemplateUID string) int {
+	return c.WorkflowTemplates[workflowTemplateUID]
+}
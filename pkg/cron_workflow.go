@@ -131,6 +131,14 @@ func (c *Client) CreateCronWorkflow(namespace string, cronWorkflow *CronWorkflow
 		return nil, util.NewUserError(codes.NotFound, "Error with getting workflow template.")
 	}
 
+	declaredParameters, err := ParseParametersFromManifest([]byte(workflowTemplate.Manifest))
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if err := ValidateParameterValues(declaredParameters, workflow.Parameters); err != nil {
+		return nil, err
+	}
+
 	// TODO: Need to pull system parameters from k8s config/secret here, example: HOST
 	opts := &WorkflowExecutionOptions{
 		Labels: make(map[string]string),
@@ -373,10 +381,16 @@ func (c *Client) buildCronWorkflowDefinition(namespace string, workflowTemplateI
 		cwf.ObjectMeta.Labels = opts.Labels
 	}
 
-	err = injectExitHandlerWorkflowExecutionStatistic(wf, workflowTemplateId)
+	sysConfig, err := c.GetSystemConfig()
 	if err != nil {
 		return nil, err
 	}
+	if sysConfig.WorkflowStatusCallbacksEnabled() {
+		err = injectExitHandlerWorkflowExecutionStatistic(wf, workflowTemplateId)
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = injectInitHandlerWorkflowExecutionStatistic(wf, workflowTemplateId)
 	if err != nil {
 		return nil, err
@@ -617,3 +631,102 @@ func (c *Client) selectCronWorkflowWithWorkflowTemplateVersion(namespace, uid st
 
 	return cronWorkflow, nil
 }
+
+// selectCronWorkflowsOfOtherVersions returns the cron workflows that are not archived and run a version of the
+// workflow template other than workflowTemplateVersionID
+func (c *Client) selectCronWorkflowsOfOtherVersions(workflowTemplateID, workflowTemplateVersionID uint64) (cronWorkflows []*CronWorkflow, err error) {
+	query := sb.Select(getCronWorkflowColumns("cw")...).
+		From("cron_workflows cw").
+		Join("workflow_template_versions wtv ON wtv.id = cw.workflow_template_version_id").
+		Where(sq.Eq{
+			"wtv.workflow_template_id": workflowTemplateID,
+			"cw.is_archived":           false,
+		}).
+		Where(sq.NotEq{"cw.workflow_template_version_id": workflowTemplateVersionID}).
+		OrderBy("cw.id")
+
+	cronWorkflows = make([]*CronWorkflow, 0)
+	err = c.DB.Selectx(&cronWorkflows, query)
+
+	return
+}
+
+// updateCronWorkflowWorkflowTemplateVersion updates the cron workflow to run the version of the workflow template,
+// keeping its schedule, labels and parameter values
+func (c *Client) updateCronWorkflowWorkflowTemplateVersion(runner sq.BaseRunner, namespace string, cronWorkflow *CronWorkflow, workflowTemplate *WorkflowTemplate) error {
+	parameters, err := cronWorkflow.GetParametersFromWorkflowSpec()
+	if err != nil {
+		return err
+	}
+
+	argoCronWorkflow, err := c.ArgoprojV1alpha1().CronWorkflows(namespace).Get(cronWorkflow.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	opts := &WorkflowExecutionOptions{
+		Labels: make(map[string]string),
+	}
+	opts.GenerateName, err = uid2.GenerateUID(workflowTemplate.Name, 63)
+	if err != nil {
+		return err
+	}
+	opts.GenerateName += "-"
+	for _, param := range parameters {
+		opts.Parameters = append(opts.Parameters, Parameter{
+			Name:  param.Name,
+			Value: param.Value,
+		})
+	}
+	for key, value := range argoCronWorkflow.Labels {
+		opts.Labels[key] = value
+	}
+	opts.Labels[workflowTemplateUIDLabelKey] = workflowTemplate.UID
+	opts.Labels[workflowTemplateVersionLabelKey] = fmt.Sprint(workflowTemplate.Version)
+
+	// Replace the parameters of a copy, so the manifest of the workflow template is the same for every cron workflow
+	parametrizedWorkflowTemplate := *workflowTemplate
+	if err := parametrizedWorkflowTemplate.ReplaceManifestParameters(parameters); err != nil {
+		return err
+	}
+	if err := cronWorkflow.AddToManifestSpec("workflowSpec", parametrizedWorkflowTemplate.Manifest); err != nil {
+		return err
+	}
+
+	manifestBytes, err := workflowTemplate.GetWorkflowManifestBytes()
+	if err != nil {
+		return err
+	}
+
+	workflows, err := UnmarshalWorkflows(manifestBytes, true)
+	if err != nil {
+		return err
+	}
+	if len(workflows) != 1 {
+		return fmt.Errorf("more than one workflow in spec")
+	}
+
+	wf := workflows[0]
+	cwf := &wfv1.CronWorkflow{Spec: argoCronWorkflow.Spec}
+	cwf.Spec.WorkflowSpec = wf.Spec
+	if _, err := c.updateCronWorkflow(namespace, cronWorkflow.Name, &workflowTemplate.ID, &wf, cwf, opts); err != nil {
+		return err
+	}
+
+	_, err = sb.Update("cron_workflows").
+		SetMap(sq.Eq{
+			"workflow_template_version_id": workflowTemplate.WorkflowTemplateVersionID,
+			"manifest":                     cronWorkflow.Manifest,
+		}).
+		Where(sq.Eq{"id": cronWorkflow.ID}).
+		RunWith(runner).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	cronWorkflow.WorkflowTemplateVersionID = workflowTemplate.WorkflowTemplateVersionID
+	cronWorkflow.Version = workflowTemplate.Version
+
+	return nil
+}
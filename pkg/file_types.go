@@ -1,6 +1,11 @@
 package v1
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/onepanelio/core/pkg/util/request/sort"
+	gosort "sort"
 	"strings"
 	"time"
 )
@@ -71,3 +76,125 @@ func FilePathToName(path string) string {
 
 	return path[lastSlashIndex+1:]
 }
+
+// ListFilesOptions are the options to list the files of a directory with Client.ListFiles
+type ListFilesOptions struct {
+	// PageSize is the maximum number of files in the page. If it is 0, all of the files are returned.
+	PageSize int
+	// ContinuationToken is the NextContinuationToken of the previous page, it is empty for the first page
+	ContinuationToken string
+	// Sort orders the files by "name", "size" or "lastModified". Only the first property is used.
+	// Directories always come before files. The default is name, asc.
+	// Pages of name, asc are read from the artifact repository one at a time, so large directories are not loaded
+	// whole. Each page has its directories first, but a later page can still have directories, unlike the
+	// other orders and listings without a PageSize, which are sorted as a whole.
+	// Sorting as a whole loads the directory in memory, so directories of more than listFilesSortLimit files
+	// return a FailedPrecondition error unless they are listed in pages of name, asc.
+	Sort *sort.Criteria
+	// FolderSizes sets the size of directories to the total size of their files, including subdirectories.
+	// On object stores, every object under each directory of the page is listed, so it is slow for large directories.
+	FolderSizes bool
+}
+
+// ListFilesResult is a page of the files of a directory
+type ListFilesResult struct {
+	Files []*File
+	// NextContinuationToken is used to request the next page, it is empty for the last page
+	NextContinuationToken string
+}
+
+// listFilesToken is the content of the continuation tokens of ListFiles.
+// Pages in the order of the artifact repository use its token, other orders are sorted in memory and use an offset.
+type listFilesToken struct {
+	Store  string `json:"s,omitempty"`
+	Offset int    `json:"o,omitempty"`
+}
+
+// encode returns the token as an opaque string, or an empty string if there are no more pages
+func (t *listFilesToken) encode() string {
+	if t.Store == "" && t.Offset == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListFilesToken parses a token created by listFilesToken.encode
+func decodeListFilesToken(token string) (*listFilesToken, error) {
+	result := &listFilesToken{}
+	if token == "" {
+		return result, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	if result.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset %v", result.Offset)
+	}
+
+	return result, nil
+}
+
+// getFilesOrder returns the property and direction to sort files by, from the first property of criteria.
+// The default is name, asc.
+func getFilesOrder(criteria *sort.Criteria) (property string, desc bool, err error) {
+	if criteria == nil || len(criteria.Properties) == 0 {
+		return "name", false, nil
+	}
+
+	order := criteria.Properties[0]
+	switch order.Property {
+	case "name", "size", "lastModified":
+		return order.Property, order.Direction == "desc", nil
+	}
+
+	return "", false, fmt.Errorf("unknown sort property '%v'", order.Property)
+}
+
+// SortFiles sorts the files by property, which is "name", "size" or "lastModified".
+// Directories come before files, files that are equal by property are ordered by path.
+func SortFiles(files []*File, property string, desc bool) {
+	gosort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if a.Directory != b.Directory {
+			return a.Directory
+		}
+
+		var cmp int
+		switch property {
+		case "size":
+			cmp = compareInt64(a.Size, b.Size)
+		case "lastModified":
+			cmp = compareInt64(a.LastModified.UnixNano(), b.LastModified.UnixNano())
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(a.Path, b.Path)
+		}
+
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// compareInt64 returns -1 if a < b, 1 if a > b and 0 otherwise
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
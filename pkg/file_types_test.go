@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util/request/sort"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// TestSortFiles makes sure directories come first and files are sorted by the property
+func TestSortFiles(t *testing.T) {
+	now := time.Now()
+	files := []*File{
+		{Path: "b.txt", Size: 1, LastModified: now},
+		{Path: "dir/", Directory: true},
+		{Path: "a.txt", Size: 3, LastModified: now.Add(-time.Hour)},
+		{Path: "c.txt", Size: 2, LastModified: now.Add(time.Hour)},
+	}
+
+	paths := func() []string {
+		result := make([]string, len(files))
+		for i, file := range files {
+			result[i] = file.Path
+		}
+		return result
+	}
+
+	SortFiles(files, "name", false)
+	assert.Equal(t, []string{"dir/", "a.txt", "b.txt", "c.txt"}, paths())
+
+	SortFiles(files, "name", true)
+	assert.Equal(t, []string{"dir/", "c.txt", "b.txt", "a.txt"}, paths())
+
+	SortFiles(files, "size", true)
+	assert.Equal(t, []string{"dir/", "a.txt", "c.txt", "b.txt"}, paths())
+
+	SortFiles(files, "lastModified", false)
+	assert.Equal(t, []string{"dir/", "a.txt", "b.txt", "c.txt"}, paths())
+}
+
+// TestGetFilesOrder makes sure only the supported properties are accepted
+func TestGetFilesOrder(t *testing.T) {
+	property, desc, err := getFilesOrder(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "name", property)
+	assert.False(t, desc)
+
+	criteria, err := sort.New("size,desc")
+	assert.Nil(t, err)
+	property, desc, err = getFilesOrder(criteria)
+	assert.Nil(t, err)
+	assert.Equal(t, "size", property)
+	assert.True(t, desc)
+
+	criteria, err = sort.New("owner,asc")
+	assert.Nil(t, err)
+	_, _, err = getFilesOrder(criteria)
+	assert.NotNil(t, err)
+}
+
+// TestListFilesToken makes sure tokens survive encoding and invalid tokens are rejected
+func TestListFilesToken(t *testing.T) {
+	assert.Equal(t, "", (&listFilesToken{}).encode())
+
+	token, err := decodeListFilesToken((&listFilesToken{Store: "next"}).encode())
+	assert.Nil(t, err)
+	assert.Equal(t, "next", token.Store)
+
+	token, err = decodeListFilesToken((&listFilesToken{Offset: 100}).encode())
+	assert.Nil(t, err)
+	assert.Equal(t, 100, token.Offset)
+
+	_, err = decodeListFilesToken("not a token")
+	assert.NotNil(t, err)
+}
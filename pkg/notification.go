@@ -0,0 +1,524 @@
+package v1
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/google/uuid"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/request/pagination"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// notificationHTTPClient sends notifications. Subscribers that don't answer in time are retried later.
+// It refuses internal addresses, see notificationDialControl, and does not use a proxy, so the check applies to subscribers.
+var notificationHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: notificationDialControl,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+const (
+	// notificationDeliveryBatchSize is the number of due deliveries sent by each run of DeliverNotifications
+	notificationDeliveryBatchSize = 100
+	// notificationDeliveryParallelism is the number of subscriptions DeliverNotifications sends to at the same time
+	notificationDeliveryParallelism = 10
+	// notificationDeliveryLease is how long the deliveries claimed by a run of DeliverNotifications are not sent by others.
+	// Deliveries of a server that stops while sending them are sent once it ends.
+	notificationDeliveryLease = 15 * time.Minute
+)
+
+// CreateNotificationSubscription creates a subscription to the workflow execution events of the namespace
+func (c *Client) CreateNotificationSubscription(namespace string, subscription *NotificationSubscription) (*NotificationSubscription, error) {
+	if err := subscription.validate(); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if err := subscription.GenerateUID(subscription.Name); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+
+	count := 0
+	err := sb.Select("COUNT(*)").
+		From("notification_subscriptions").
+		Where(sq.Eq{"namespace": namespace, "uid": subscription.UID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, util.NewUserError(codes.AlreadyExists, "Notification subscription already exists.")
+	}
+
+	subscription.Namespace = namespace
+	err = sb.Insert("notification_subscriptions").
+		SetMap(sq.Eq{
+			"uid":       subscription.UID,
+			"name":      subscription.Name,
+			"namespace": namespace,
+			"url":       subscription.URL,
+			"filter":    subscription.Filter,
+		}).
+		Suffix("RETURNING id, created_at").
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// GetNotificationSubscription returns the subscription identified by (namespace, uid)
+func (c *Client) GetNotificationSubscription(namespace, uid string) (*NotificationSubscription, error) {
+	query := sb.Select(getNotificationSubscriptionColumns("ns")...).
+		From("notification_subscriptions ns").
+		Where(sq.Eq{
+			"ns.namespace": namespace,
+			"ns.uid":       uid,
+		})
+
+	subscription := &NotificationSubscription{}
+	if err := c.DB.Getx(subscription, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, util.NewUserError(codes.NotFound, "Notification subscription not found.")
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// ListNotificationSubscriptions returns the subscriptions of the namespace, ordered by name
+func (c *Client) ListNotificationSubscriptions(namespace string) (subscriptions []*NotificationSubscription, err error) {
+	query := sb.Select(getNotificationSubscriptionColumns("ns")...).
+		From("notification_subscriptions ns").
+		Where(sq.Eq{"ns.namespace": namespace}).
+		OrderBy("ns.name")
+
+	subscriptions = make([]*NotificationSubscription, 0)
+	err = c.DB.Selectx(&subscriptions, query)
+
+	return
+}
+
+// DeleteNotificationSubscription deletes the subscription identified by (namespace, uid) and its delivery logs
+func (c *Client) DeleteNotificationSubscription(namespace, uid string) error {
+	result, err := sb.Delete("notification_subscriptions").
+		Where(sq.Eq{
+			"namespace": namespace,
+			"uid":       uid,
+		}).
+		RunWith(c.DB).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return util.NewUserError(codes.NotFound, "Notification subscription not found.")
+	}
+
+	return nil
+}
+
+// ListNotificationDeliveries returns the delivery logs of the subscription identified by (namespace, uid), newest first
+func (c *Client) ListNotificationDeliveries(namespace, uid string, paginator *pagination.PaginationRequest) (deliveries []*NotificationDelivery, err error) {
+	subscription, err := c.GetNotificationSubscription(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	query := sb.Select(getNotificationDeliveryColumns("nd")...).
+		From("notification_deliveries nd").
+		Where(sq.Eq{"nd.subscription_id": subscription.ID}).
+		OrderBy("nd.created_at DESC", "nd.id DESC")
+	query = *paginator.ApplyToSelect(&query)
+
+	deliveries = make([]*NotificationDelivery, 0)
+	if err := c.DB.Selectx(&deliveries, query); err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.URL = subscription.URL
+	}
+
+	return
+}
+
+// CountNotificationDeliveries returns the number of delivery logs of the subscription identified by (namespace, uid)
+func (c *Client) CountNotificationDeliveries(namespace, uid string) (count int, err error) {
+	err = sb.Select("COUNT(*)").
+		From("notification_deliveries nd").
+		Join("notification_subscriptions ns ON ns.id = nd.subscription_id").
+		Where(sq.Eq{
+			"ns.namespace": namespace,
+			"ns.uid":       uid,
+		}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&count)
+
+	return
+}
+
+// newNotificationDelivery returns a pending delivery of the event to the subscription
+func newNotificationDelivery(subscription *NotificationSubscription, event *NotificationEvent) (*NotificationDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &NotificationDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         NotificationDeliveryPending,
+		URL:            subscription.URL,
+		CreatedAt:      event.CreatedAt,
+	}
+	if event.WorkflowExecution != nil {
+		delivery.WorkflowExecutionUID = event.WorkflowExecution.UID
+	}
+
+	return delivery, nil
+}
+
+// insertNotificationDelivery stores a delivery and sets its id
+func (c *Client) insertNotificationDelivery(delivery *NotificationDelivery) error {
+	return sb.Insert("notification_deliveries").
+		SetMap(sq.Eq{
+			"subscription_id":        delivery.SubscriptionID,
+			"event_id":               delivery.EventID,
+			"event_type":             delivery.EventType,
+			"workflow_execution_uid": delivery.WorkflowExecutionUID,
+			"payload":                string(delivery.Payload),
+			"status":                 delivery.Status,
+			"next_attempt_at":        delivery.NextAttemptAt,
+			"created_at":             delivery.CreatedAt,
+		}).
+		Suffix("RETURNING id").
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&delivery.ID)
+}
+
+// queueWorkflowExecutionNotifications stores a pending delivery of the phase of the workflow execution identified by
+// (namespace, uid) for each subscription of the namespace whose filter matches it. DeliverNotifications sends them.
+func (c *Client) queueWorkflowExecutionNotifications(namespace, uid string) error {
+	subscriptions, err := c.ListNotificationSubscriptions(namespace)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	query := sb.Select("we.uid", "we.name", "we.phase", "we.labels", "we.started_at", "we.finished_at").
+		Columns(`wt.uid "workflow_template_uid"`, `wt.name "workflow_template_name"`).
+		From("workflow_executions we").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{
+			"we.namespace": namespace,
+			"we.uid":       uid,
+		})
+
+	workflowExecution := &NotificationWorkflowExecution{}
+	if err := c.DB.Getx(workflowExecution, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	event := &NotificationEvent{
+		ID:                uuid.New().String(),
+		Type:              NotificationEventWorkflowExecutionPhase,
+		Namespace:         namespace,
+		CreatedAt:         now,
+		WorkflowExecution: workflowExecution,
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Filter.matches(workflowExecution) {
+			continue
+		}
+
+		delivery, err := newNotificationDelivery(subscription, event)
+		if err != nil {
+			return err
+		}
+		delivery.NextAttemptAt = &now
+
+		if err := c.insertNotificationDelivery(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postNotification sends the payload of the delivery to url, signed with key.
+// It returns the status code of the response, if there is one, and an error unless it is a 2xx.
+func postNotification(client *http.Client, url string, key []byte, delivery *NotificationDelivery) (*int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(notificationEventHeader, string(delivery.EventType))
+	req.Header.Set(notificationDeliveryHeader, delivery.EventID)
+	if signature := signNotificationPayload(key, delivery.Payload); signature != "" {
+		req.Header.Set(notificationSignatureHeader, signature)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	code := resp.StatusCode
+	if code < 200 || code > 299 {
+		return &code, fmt.Errorf("unexpected response status %v", resp.Status)
+	}
+
+	return &code, nil
+}
+
+// attemptNotificationDelivery sends the delivery once, and updates its status, attempts and next attempt
+func (c *Client) attemptNotificationDelivery(delivery *NotificationDelivery, key []byte) error {
+	now := time.Now().UTC()
+	code, err := postNotification(notificationHTTPClient, delivery.URL, key, delivery)
+
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	delivery.Error = ""
+	if err == nil {
+		delivery.Status = NotificationDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	} else {
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = nextNotificationAttempt(delivery.Attempts, now)
+		if delivery.NextAttemptAt == nil {
+			delivery.Status = NotificationDeliveryFailed
+		}
+	}
+
+	_, err = sb.Update("notification_deliveries").
+		SetMap(sq.Eq{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"error":           delivery.Error,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"modified_at":     now,
+		}).
+		Where(sq.Eq{"id": delivery.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// SendTestNotification sends a test event to the subscription identified by (namespace, uid) right away,
+// and returns its delivery log. A failed test is not retried.
+func (c *Client) SendTestNotification(namespace, uid string) (*NotificationDelivery, error) {
+	subscription, err := c.GetNotificationSubscription(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sysConfig, err := c.GetSystemConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := newNotificationDelivery(subscription, &NotificationEvent{
+		ID:        uuid.New().String(),
+		Type:      NotificationEventTest,
+		Namespace: namespace,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.insertNotificationDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	if err := c.attemptNotificationDelivery(delivery, sysConfig.HMACKey()); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == NotificationDeliveryPending {
+		delivery.Status = NotificationDeliveryFailed
+		delivery.NextAttemptAt = nil
+		_, err = sb.Update("notification_deliveries").
+			SetMap(sq.Eq{
+				"status":          delivery.Status,
+				"next_attempt_at": nil,
+			}).
+			Where(sq.Eq{"id": delivery.ID}).
+			RunWith(c.DB).
+			Exec()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return delivery, nil
+}
+
+// DeliverNotifications sends the pending deliveries that are due. Failed deliveries are retried with a growing delay,
+// up to notificationMaxAttempts times.
+// Subscriptions are sent to in parallel, and the deliveries of a subscription in order. Once one fails, the rest of
+// the subscription waits for the next run, so a subscriber that does not answer only holds up its own deliveries.
+// Deliveries are claimed before they are sent, so servers running at the same time don't send them twice.
+func (c *Client) DeliverNotifications() error {
+	ids, err := c.claimNotificationDeliveries(time.Now().UTC())
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	query := sb.Select(getNotificationDeliveryColumns("nd")...).
+		Columns("ns.url").
+		From("notification_deliveries nd").
+		Join("notification_subscriptions ns ON ns.id = nd.subscription_id").
+		Where(sq.Eq{"nd.id": ids}).
+		OrderBy("nd.id")
+
+	deliveries := make([]*NotificationDelivery, 0)
+	if err := c.DB.Selectx(&deliveries, query); err != nil {
+		return err
+	}
+
+	sysConfig, err := c.GetSystemConfig()
+	if err != nil {
+		return err
+	}
+
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, notificationDeliveryParallelism)
+	for _, group := range groupNotificationDeliveries(deliveries) {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(group []*NotificationDelivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			for i, delivery := range group {
+				if err := c.attemptNotificationDelivery(delivery, sysConfig.HMACKey()); err != nil {
+					log.WithFields(log.Fields{
+						"EventID": delivery.EventID,
+						"URL":     delivery.URL,
+						"Error":   err.Error(),
+					}).Error("Unable to update notification delivery.")
+				}
+				if delivery.Status != NotificationDeliverySucceeded {
+					c.releaseNotificationDeliveries(group[i+1:])
+					return
+				}
+			}
+		}(group)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// claimNotificationDeliveries returns the ids of up to notificationDeliveryBatchSize pending deliveries that are due,
+// and moves their next attempt after notificationDeliveryLease, so other runs skip them while they are sent.
+// Rows that another run is claiming are skipped instead of waited for.
+func (c *Client) claimNotificationDeliveries(now time.Time) ([]uint64, error) {
+	query, args, err := sb.Update("notification_deliveries").
+		Set("next_attempt_at", now.Add(notificationDeliveryLease)).
+		Where(sq.Expr(`id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)`, NotificationDeliveryPending, now, notificationDeliveryBatchSize)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0)
+	err = c.DB.Select(&ids, query, args...)
+
+	return ids, err
+}
+
+// releaseNotificationDeliveries makes claimed deliveries that were not sent due again, for the next run
+func (c *Client) releaseNotificationDeliveries(deliveries []*NotificationDelivery) {
+	if len(deliveries) == 0 {
+		return
+	}
+
+	ids := make([]uint64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	_, err := sb.Update("notification_deliveries").
+		Set("next_attempt_at", time.Now().UTC()).
+		Where(sq.Eq{
+			"id":     ids,
+			"status": NotificationDeliveryPending,
+		}).
+		RunWith(c.DB).
+		Exec()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error": err.Error(),
+		}).Error("Unable to release notification deliveries.")
+	}
+}
+
+// notifyWorkflowExecutionPhase queues the notifications of a workflow execution that changed phase.
+// Errors are logged, so they don't stop the sync of the workflow execution.
+func (c *Client) notifyWorkflowExecutionPhase(namespace, uid string, phase wfv1.NodePhase) {
+	if !notificationPhases[phase] {
+		return
+	}
+
+	if err := c.queueWorkflowExecutionNotifications(namespace, uid); err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Phase":     phase,
+			"Error":     err.Error(),
+		}).Error("Unable to queue workflow execution notifications.")
+	}
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// createNotificationSubscriptionForTest replaces the subscriptions of the namespace with one to every phase
+func createNotificationSubscriptionForTest(t *testing.T, c *Client, namespace string) *NotificationSubscription {
+	_, err := database.Exec(`DELETE FROM notification_subscriptions`)
+	assert.Nil(t, err)
+
+	subscription, err := c.CreateNotificationSubscription(namespace, &NotificationSubscription{
+		Name: "hooks",
+		URL:  "https://hooks.example.com/onepanel",
+	})
+	assert.Nil(t, err)
+
+	return subscription
+}
+
+// TestClient_SyncWorkflowExecutionStatus_NotifiesOnce makes sure syncing the same phase again does not queue another notification
+func TestClient_SyncWorkflowExecutionStatus_NotifiesOnce(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	createNotificationSubscriptionForTest(t, c, namespace)
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	wf := setArgoWorkflowPhaseForTest(t, c, namespace, we.UID, wfv1.NodeSucceeded, true)
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+
+	count := 0
+	assert.Nil(t, database.Get(&count, `SELECT COUNT(*) FROM notification_deliveries WHERE workflow_execution_uid = $1`, we.UID))
+	assert.Equal(t, 1, count)
+}
+
+// TestClient_ClaimNotificationDeliveries makes sure claimed deliveries are not claimed again until they are released
+func TestClient_ClaimNotificationDeliveries(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	subscription := createNotificationSubscriptionForTest(t, c, "onepanel")
+
+	now := time.Now().UTC()
+	delivery, err := newNotificationDelivery(subscription, &NotificationEvent{
+		ID:        "event-1",
+		Type:      NotificationEventTest,
+		Namespace: "onepanel",
+		CreatedAt: now,
+	})
+	assert.Nil(t, err)
+	delivery.NextAttemptAt = &now
+	assert.Nil(t, c.insertNotificationDelivery(delivery))
+
+	ids, err := c.claimNotificationDeliveries(now)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{delivery.ID}, ids)
+
+	ids, err = c.claimNotificationDeliveries(now)
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+
+	c.releaseNotificationDeliveries([]*NotificationDelivery{delivery})
+	ids, err = c.claimNotificationDeliveries(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{delivery.ID}, ids)
+}
@@ -0,0 +1,322 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util/sql"
+	"github.com/onepanelio/core/pkg/util/types"
+	uid2 "github.com/onepanelio/core/pkg/util/uid"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// notificationMaxAttempts is the number of times a notification is sent before its delivery fails
+	notificationMaxAttempts = 6
+	// notificationRetryDelay is the delay before the first retry of a notification. It doubles with each attempt.
+	notificationRetryDelay = 30 * time.Second
+	// notificationMaxRetryDelay bounds the delay between retries of a notification
+	notificationMaxRetryDelay = time.Hour
+	// notificationSignatureHeader has the HMAC-SHA256 of the payload signed with the HMAC key of the system config,
+	// as sha256=<hex>
+	notificationSignatureHeader = "X-Onepanel-Signature"
+	// notificationEventHeader has the type of the event
+	notificationEventHeader = "X-Onepanel-Event"
+	// notificationDeliveryHeader has the id of the event, which is the same for every attempt to deliver it
+	notificationDeliveryHeader = "X-Onepanel-Delivery"
+)
+
+// NotificationEventType is what a notification is about
+type NotificationEventType string
+
+const (
+	// NotificationEventWorkflowExecutionPhase is sent when a workflow execution changes phase
+	NotificationEventWorkflowExecutionPhase NotificationEventType = "workflow_execution.phase"
+	// NotificationEventTest is sent to check that a subscription works
+	NotificationEventTest NotificationEventType = "test"
+)
+
+// NotificationDeliveryStatus is the status of the delivery of a notification to a subscription
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending   NotificationDeliveryStatus = "Pending"
+	NotificationDeliverySucceeded NotificationDeliveryStatus = "Succeeded"
+	NotificationDeliveryFailed    NotificationDeliveryStatus = "Failed"
+)
+
+// notificationPhases are the phases of workflow executions that can be notified
+var notificationPhases = map[wfv1.NodePhase]bool{
+	wfv1.NodeRunning:   true,
+	wfv1.NodeSucceeded: true,
+	wfv1.NodeFailed:    true,
+	wfv1.NodeError:     true,
+}
+
+// defaultNotificationPhases are notified when a filter has no phases
+var defaultNotificationPhases = []wfv1.NodePhase{wfv1.NodeSucceeded, wfv1.NodeFailed, wfv1.NodeError}
+
+// NotificationFilter selects the workflow executions a subscription is notified about.
+// Empty fields match everything, except Phases, which defaults to defaultNotificationPhases.
+type NotificationFilter struct {
+	Phases              []wfv1.NodePhase `json:"phases,omitempty"`
+	WorkflowTemplateUID string           `json:"workflowTemplateUid,omitempty"`
+	// Labels the workflow execution must all have
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NotificationSubscription sends the events of the workflow executions of a namespace, that match its filter, to a URL
+type NotificationSubscription struct {
+	ID         uint64
+	UID        string
+	Name       string
+	Namespace  string
+	URL        string
+	Filter     NotificationFilter
+	CreatedAt  time.Time  `db:"created_at"`
+	ModifiedAt *time.Time `db:"modified_at"`
+}
+
+// NotificationWorkflowExecution is the workflow execution of a notification event
+type NotificationWorkflowExecution struct {
+	UID                  string           `json:"uid"`
+	Name                 string           `json:"name"`
+	Phase                wfv1.NodePhase   `json:"phase"`
+	WorkflowTemplateUID  string           `json:"workflowTemplateUid" db:"workflow_template_uid"`
+	WorkflowTemplateName string           `json:"workflowTemplateName" db:"workflow_template_name"`
+	Labels               types.JSONLabels `json:"labels"`
+	StartedAt            *time.Time       `json:"startedAt,omitempty" db:"started_at"`
+	FinishedAt           *time.Time       `json:"finishedAt,omitempty" db:"finished_at"`
+}
+
+// NotificationEvent is the payload sent to subscriptions
+type NotificationEvent struct {
+	ID                string                         `json:"id"`
+	Type              NotificationEventType          `json:"type"`
+	Namespace         string                         `json:"namespace"`
+	CreatedAt         time.Time                      `json:"createdAt"`
+	WorkflowExecution *NotificationWorkflowExecution `json:"workflowExecution,omitempty"`
+}
+
+// NotificationDelivery is the log of sending a notification event to a subscription
+type NotificationDelivery struct {
+	ID                   uint64
+	SubscriptionID       uint64                `db:"subscription_id"`
+	EventID              string                `db:"event_id"`
+	EventType            NotificationEventType `db:"event_type"`
+	WorkflowExecutionUID string                `db:"workflow_execution_uid"`
+	Payload              []byte
+	Status               NotificationDeliveryStatus
+	Attempts             int
+	ResponseCode         *int `db:"response_code"`
+	Error                string
+	NextAttemptAt        *time.Time `db:"next_attempt_at"`
+	DeliveredAt          *time.Time `db:"delivered_at"`
+	CreatedAt            time.Time  `db:"created_at"`
+	ModifiedAt           *time.Time `db:"modified_at"`
+	// URL is the URL of the subscription
+	URL string
+}
+
+// Value returns the filter as JSON, to support the JSONB column
+func (f NotificationFilter) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan reads the filter from JSON, to support the JSONB column
+func (f *NotificationFilter) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(t), f)
+	case []byte:
+		return json.Unmarshal(t, f)
+	case nil:
+		*f = NotificationFilter{}
+		return nil
+	}
+
+	return errors.New("incompatible type for NotificationFilter")
+}
+
+// validate returns an error if the filter has phases that can't be notified
+func (f *NotificationFilter) validate() error {
+	for _, phase := range f.Phases {
+		if !notificationPhases[phase] {
+			return fmt.Errorf("phase '%v' can't be notified, use Running, Succeeded, Failed or Error", phase)
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if the filter selects the workflow execution
+func (f *NotificationFilter) matches(workflowExecution *NotificationWorkflowExecution) bool {
+	phases := f.Phases
+	if len(phases) == 0 {
+		phases = defaultNotificationPhases
+	}
+
+	phaseMatches := false
+	for _, phase := range phases {
+		if phase == workflowExecution.Phase {
+			phaseMatches = true
+			break
+		}
+	}
+	if !phaseMatches {
+		return false
+	}
+
+	if f.WorkflowTemplateUID != "" && f.WorkflowTemplateUID != workflowExecution.WorkflowTemplateUID {
+		return false
+	}
+
+	for key, value := range f.Labels {
+		if labelValue, ok := workflowExecution.Labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GenerateUID generates a uid from the input name and sets it on the subscription
+func (s *NotificationSubscription) GenerateUID(name string) error {
+	result, err := uid2.GenerateUID(name, 63)
+	if err != nil {
+		return err
+	}
+
+	s.UID = result
+
+	return nil
+}
+
+// validate returns an error if the subscription has no name, a URL that is not http(s), or an invalid filter
+func (s *NotificationSubscription) validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an http or https URL")
+	}
+
+	host := target.Hostname()
+	if ip := net.ParseIP(host); strings.EqualFold(host, "localhost") || (ip != nil && !isExternalAddress(ip)) {
+		return errors.New("url can't be a loopback, link-local or private address")
+	}
+
+	return s.Filter.validate()
+}
+
+// notificationDialControl refuses connections to addresses that are not allowed, see isExternalAddress.
+// It runs once the host name is resolved, so names and redirects that lead to these addresses are refused too.
+func notificationDialControl(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !isExternalAddress(ip) {
+		return fmt.Errorf("notifications can't be sent to %v", host)
+	}
+
+	return nil
+}
+
+// groupNotificationDeliveries splits deliveries by subscription, keeping their order
+func groupNotificationDeliveries(deliveries []*NotificationDelivery) [][]*NotificationDelivery {
+	groups := make([][]*NotificationDelivery, 0)
+	index := make(map[uint64]int)
+	for _, delivery := range deliveries {
+		i, ok := index[delivery.SubscriptionID]
+		if !ok {
+			i = len(groups)
+			index[delivery.SubscriptionID] = i
+			groups = append(groups, make([]*NotificationDelivery, 0))
+		}
+		groups[i] = append(groups[i], delivery)
+	}
+
+	return groups
+}
+
+// signNotificationPayload returns the value of notificationSignatureHeader for the payload, or an empty string without a key
+func signNotificationPayload(key, payload []byte) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// nextNotificationAttempt returns when a delivery that failed attempts times is retried, or nil if it is not retried
+func nextNotificationAttempt(attempts int, now time.Time) *time.Time {
+	if attempts >= notificationMaxAttempts {
+		return nil
+	}
+
+	delay := notificationRetryDelay
+	for i := 1; i < attempts && delay < notificationMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > notificationMaxRetryDelay {
+		delay = notificationMaxRetryDelay
+	}
+
+	next := now.Add(delay)
+
+	return &next
+}
+
+// getNotificationSubscriptionColumns returns all of the columns for notificationSubscription modified by alias, destination.
+// see formatColumnSelect
+func getNotificationSubscriptionColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"uid",
+		"name",
+		"namespace",
+		"url",
+		"filter",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
+
+// getNotificationDeliveryColumns returns all of the columns for notificationDelivery modified by alias, destination.
+// see formatColumnSelect
+func getNotificationDeliveryColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"subscription_id",
+		"event_id",
+		"event_type",
+		"workflow_execution_uid",
+		"payload",
+		"status",
+		"attempts",
+		"response_code",
+		"error",
+		"next_attempt_at",
+		"delivered_at",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestNotificationFilter_Matches makes sure phases, workflow template and labels are all checked
+func TestNotificationFilter_Matches(t *testing.T) {
+	workflowExecution := &NotificationWorkflowExecution{
+		Phase:               wfv1.NodeFailed,
+		WorkflowTemplateUID: "train",
+		Labels:              types.JSONLabels{"team": "vision", "sweep": "abc"},
+	}
+
+	assert.True(t, (&NotificationFilter{}).matches(workflowExecution))
+	assert.True(t, (&NotificationFilter{WorkflowTemplateUID: "train", Labels: map[string]string{"team": "vision"}}).matches(workflowExecution))
+	assert.False(t, (&NotificationFilter{Phases: []wfv1.NodePhase{wfv1.NodeSucceeded}}).matches(workflowExecution))
+	assert.False(t, (&NotificationFilter{WorkflowTemplateUID: "export"}).matches(workflowExecution))
+	assert.False(t, (&NotificationFilter{Labels: map[string]string{"team": "nlp"}}).matches(workflowExecution))
+
+	workflowExecution.Phase = wfv1.NodeRunning
+	assert.False(t, (&NotificationFilter{}).matches(workflowExecution))
+	assert.True(t, (&NotificationFilter{Phases: []wfv1.NodePhase{wfv1.NodeRunning}}).matches(workflowExecution))
+}
+
+// TestNotificationSubscription_Validate makes sure the name, url and phases are checked
+func TestNotificationSubscription_Validate(t *testing.T) {
+	assert.Nil(t, (&NotificationSubscription{Name: "slack", URL: "https://hooks.example.com/abc"}).validate())
+	assert.NotNil(t, (&NotificationSubscription{URL: "https://hooks.example.com/abc"}).validate())
+	assert.NotNil(t, (&NotificationSubscription{Name: "slack", URL: "ftp://hooks.example.com"}).validate())
+	assert.NotNil(t, (&NotificationSubscription{Name: "slack", URL: "hooks.example.com/abc"}).validate())
+	assert.NotNil(t, (&NotificationSubscription{
+		Name:   "slack",
+		URL:    "https://hooks.example.com/abc",
+		Filter: NotificationFilter{Phases: []wfv1.NodePhase{"Skipped"}},
+	}).validate())
+}
+
+// TestNotificationSubscription_Validate_InternalAddress makes sure urls of internal addresses are refused
+func TestNotificationSubscription_Validate_InternalAddress(t *testing.T) {
+	for _, target := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.12/hook",
+		"http://192.168.1.1/hook",
+		"http://172.20.3.4/hook",
+		"http://[fd12:3456::1]/hook",
+		"http://[::ffff:10.1.2.3]/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		assert.NotNil(t, (&NotificationSubscription{Name: "internal", URL: target}).validate(), target)
+	}
+
+	assert.Nil(t, (&NotificationSubscription{Name: "public", URL: "https://8.8.8.8/hook"}).validate())
+	assert.Nil(t, (&NotificationSubscription{Name: "public", URL: "https://172.32.0.1/hook"}).validate())
+}
+
+// TestNextNotificationAttempt makes sure the retry delay doubles, is bounded, and stops after the last attempt
+func TestNextNotificationAttempt(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, now.Add(30*time.Second), *nextNotificationAttempt(1, now))
+	assert.Equal(t, now.Add(time.Minute), *nextNotificationAttempt(2, now))
+	assert.Equal(t, now.Add(4*time.Minute), *nextNotificationAttempt(4, now))
+	assert.Nil(t, nextNotificationAttempt(notificationMaxAttempts, now))
+}
+
+// TestPostNotification makes sure the payload is sent signed with the HMAC key, and non 2xx responses are errors
+func TestPostNotification(t *testing.T) {
+	key := []byte("secret")
+	status := http.StatusOK
+	var received []byte
+	var signature, eventType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(notificationSignatureHeader)
+		eventType = r.Header.Get(notificationEventHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	delivery := &NotificationDelivery{
+		EventID:   "event-1",
+		EventType: NotificationEventTest,
+		Payload:   []byte(`{"id":"event-1","type":"test"}`),
+	}
+
+	code, err := postNotification(server.Client(), server.URL, key, delivery)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, *code)
+	assert.Equal(t, delivery.Payload, received)
+	assert.Equal(t, "test", eventType)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(delivery.Payload)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	status = http.StatusInternalServerError
+	code, err = postNotification(server.Client(), server.URL, nil, delivery)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, *code)
+	assert.Empty(t, signature)
+}
+
+// TestNotificationHTTPClient makes sure notifications are not sent to internal addresses, like the loopback of the test server
+func TestNotificationHTTPClient(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	code, err := postNotification(notificationHTTPClient, server.URL, nil, &NotificationDelivery{Payload: []byte("{}")})
+	assert.NotNil(t, err)
+	assert.Nil(t, code)
+	assert.False(t, requested)
+}
+
+// TestGroupNotificationDeliveries makes sure deliveries are grouped by subscription in order
+func TestGroupNotificationDeliveries(t *testing.T) {
+	deliveries := []*NotificationDelivery{
+		{ID: 1, SubscriptionID: 1},
+		{ID: 2, SubscriptionID: 2},
+		{ID: 3, SubscriptionID: 1},
+	}
+
+	groups := groupNotificationDeliveries(deliveries)
+	assert.Equal(t, [][]*NotificationDelivery{
+		{deliveries[0], deliveries[2]},
+		{deliveries[1]},
+	}, groups)
+}
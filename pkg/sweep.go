@@ -0,0 +1,572 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/request/pagination"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"time"
+)
+
+// lockSweep takes the advisory lock of the sweep in a new transaction, and returns that transaction.
+// It makes sure a sweep is not reconciled or terminated by more than one server at the same time, which could start too many runs.
+// The lock is released when the transaction ends.
+func (c *Client) lockSweep(sweepID uint64) (*sql.Tx, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", fmt.Sprintf("sweep/%v", sweepID)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// sweepSelectBuilder returns a select for the sweeps of a namespace, with the uid and version of their workflow template
+func sweepSelectBuilder(namespace string) sq.SelectBuilder {
+	return sb.Select(getSweepColumns("s")...).
+		Columns(`wt.uid "workflow_template.uid"`, `wt.name "workflow_template.name"`, `wtv.version "workflow_template.version"`).
+		From("sweeps s").
+		Join("workflow_template_versions wtv ON wtv.id = s.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{"s.namespace": namespace})
+}
+
+// selectSweepRuns returns the runs of a sweep ordered by rung and trial, with the status of their workflow executions
+func (c *Client) selectSweepRuns(sweepID uint64) (runs []*SweepRun, err error) {
+	query := sb.Select(getSweepRunColumns("sr")...).
+		Columns(
+			`COALESCE(we.uid, '') "workflow_execution.uid"`,
+			`COALESCE(we.phase, '') "workflow_execution.phase"`,
+			`we.metrics "workflow_execution.metrics"`,
+			`we.started_at "workflow_execution.started_at"`,
+			`we.finished_at "workflow_execution.finished_at"`,
+		).
+		From("sweep_runs sr").
+		LeftJoin("workflow_executions we ON we.id = sr.workflow_execution_id").
+		Where(sq.Eq{"sr.sweep_id": sweepID}).
+		OrderBy("sr.rung", "sr.trial")
+
+	runs = make([]*SweepRun, 0)
+	if err = c.DB.Selectx(&runs, query); err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		if err = json.Unmarshal(run.ParametersBytes, &run.Parameters); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// insertSweepRuns inserts pending runs of a sweep
+func insertSweepRuns(runner sq.BaseRunner, sweepID uint64, rung int, trials map[int][]Parameter) error {
+	if len(trials) == 0 {
+		return nil
+	}
+
+	insert := sb.Insert("sweep_runs").
+		Columns("sweep_id", "trial", "rung", "parameters", "phase")
+	for trial, parameters := range trials {
+		parametersBytes, err := json.Marshal(parameters)
+		if err != nil {
+			return err
+		}
+		insert = insert.Values(sweepID, trial, rung, string(parametersBytes), SweepPending)
+	}
+
+	_, err := insert.RunWith(runner).Exec()
+
+	return err
+}
+
+// CreateSweep validates the search space of the sweep, creates its runs and starts the first ones.
+// The sweep runs the workflow template version in sweep.WorkflowTemplate, identified by uid and version.
+func (c *Client) CreateSweep(namespace string, sweep *Sweep) (*Sweep, error) {
+	if err := sweep.Spec.validate(sweep.Strategy); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if sweep.ObjectiveMetric == "" {
+		return nil, util.NewUserError(codes.InvalidArgument, "An objective metric is required.")
+	}
+	if sweep.ObjectiveGoal == "" {
+		sweep.ObjectiveGoal = SweepObjectiveMinimize
+	}
+	if sweep.ObjectiveGoal != SweepObjectiveMinimize && sweep.ObjectiveGoal != SweepObjectiveMaximize {
+		return nil, util.NewUserError(codes.InvalidArgument, "The objective goal must be minimize or maximize.")
+	}
+	if sweep.MaxConcurrency <= 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "Max concurrency must be at least 1.")
+	}
+	if err := sweep.GenerateUID(sweep.Name); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if sweep.Spec.Seed == 0 {
+		sweep.Spec.Seed = time.Now().UnixNano()
+	}
+
+	workflowTemplate, err := c.GetWorkflowTemplate(namespace, sweep.WorkflowTemplate.UID, sweep.WorkflowTemplate.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	trials, err := sweep.Spec.generateTrials(sweep.Strategy)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+
+	count := 0
+	err = sb.Select("COUNT(*)").
+		From("sweeps").
+		Where(sq.Eq{"namespace": namespace, "uid": sweep.UID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, util.NewUserError(codes.AlreadyExists, "Sweep already exists.")
+	}
+
+	sweep.Namespace = namespace
+	sweep.WorkflowTemplate = workflowTemplate
+	sweep.WorkflowTemplateVersionID = workflowTemplate.WorkflowTemplateVersionID
+	sweep.Phase = SweepRunning
+	now := time.Now().UTC()
+	sweep.StartedAt = &now
+	if sweep.Labels == nil {
+		sweep.Labels = make(map[string]string)
+	}
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = sb.Insert("sweeps").
+		SetMap(sq.Eq{
+			"uid":                          sweep.UID,
+			"name":                         sweep.Name,
+			"namespace":                    namespace,
+			"workflow_template_version_id": sweep.WorkflowTemplateVersionID,
+			"strategy":                     sweep.Strategy,
+			"spec":                         sweep.Spec,
+			"objective_metric":             sweep.ObjectiveMetric,
+			"objective_goal":               sweep.ObjectiveGoal,
+			"max_concurrency":              sweep.MaxConcurrency,
+			"phase":                        sweep.Phase,
+			"labels":                       sweep.Labels,
+			"started_at":                   sweep.StartedAt,
+		}).
+		Suffix("RETURNING id, created_at").
+		RunWith(tx).
+		QueryRow().
+		Scan(&sweep.ID, &sweep.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rungTrials := make(map[int][]Parameter)
+	for i, trial := range trials {
+		if sweep.Strategy == SweepStrategySuccessiveHalving {
+			resource := sweep.Spec.rungResource(0)
+			trial = append(trial, Parameter{Name: sweep.Spec.ResourceParameter, Value: &resource})
+		}
+		rungTrials[i] = sweep.Spec.trialParameters(trial)
+	}
+	if err = insertSweepRuns(tx, sweep.ID, 0, rungTrials); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// The sweep exists now, so it is returned even if its first runs can't be started.
+	// ReconcileSweeps tries again.
+	if err := c.reconcileSweep(sweep); err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       sweep.UID,
+			"Error":     err.Error(),
+		}).Error("Unable to start the runs of the sweep.")
+	}
+
+	return c.GetSweep(namespace, sweep.UID)
+}
+
+// GetSweep returns the sweep identified by (namespace, uid), with its runs
+func (c *Client) GetSweep(namespace, uid string) (*Sweep, error) {
+	sweep := &Sweep{}
+	query := sweepSelectBuilder(namespace).
+		Where(sq.Eq{"s.uid": uid})
+
+	if err := c.DB.Getx(sweep, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, util.NewUserError(codes.NotFound, "Sweep not found.")
+		}
+		return nil, err
+	}
+
+	runs, err := c.selectSweepRuns(sweep.ID)
+	if err != nil {
+		return nil, err
+	}
+	sweep.Runs = runs
+
+	return sweep, nil
+}
+
+// ListSweeps returns the sweeps of a namespace, newest first, without their runs
+func (c *Client) ListSweeps(namespace string, paginator *pagination.PaginationRequest) (sweeps []*Sweep, err error) {
+	query := sweepSelectBuilder(namespace).
+		OrderBy("s.created_at DESC")
+	query = *paginator.ApplyToSelect(&query)
+
+	sweeps = make([]*Sweep, 0)
+	err = c.DB.Selectx(&sweeps, query)
+
+	return
+}
+
+// CountSweeps returns the number of sweeps of a namespace
+func (c *Client) CountSweeps(namespace string) (count int, err error) {
+	err = sb.Select("COUNT(*)").
+		From("sweeps").
+		Where(sq.Eq{"namespace": namespace}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&count)
+
+	return
+}
+
+// TerminateSweep stops a sweep: runs that did not start are skipped and running workflow executions are terminated.
+func (c *Client) TerminateSweep(namespace, uid string) (*Sweep, error) {
+	sweep, err := c.GetSweep(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.lockSweep(sweep.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The sweep may have been reconciled while waiting for the lock
+	sweep, err = c.GetSweep(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+	if sweep.Phase != SweepRunning {
+		return nil, util.NewUserError(codes.FailedPrecondition, "Sweep is not running.")
+	}
+
+	for _, run := range sweep.Runs {
+		if run.Phase != SweepPending && run.Phase != SweepRunning {
+			continue
+		}
+
+		if run.Phase == SweepRunning && run.WorkflowExecution.UID != "" {
+			if err := c.TerminateWorkflowExecution(namespace, run.WorkflowExecution.UID); err != nil {
+				log.WithFields(log.Fields{
+					"Namespace": namespace,
+					"UID":       uid,
+					"Run":       run.WorkflowExecution.UID,
+					"Error":     err.Error(),
+				}).Error("Unable to terminate sweep run.")
+			}
+		}
+
+		run.Phase = SweepTerminated
+		if err := c.updateSweepRun(run); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.finishSweep(sweep, SweepTerminated, "Terminated by user."); err != nil {
+		return nil, err
+	}
+
+	return c.GetSweep(namespace, uid)
+}
+
+// updateSweepRun saves the workflow execution, phase and objective value of a run
+func (c *Client) updateSweepRun(run *SweepRun) error {
+	_, err := sb.Update("sweep_runs").
+		SetMap(sq.Eq{
+			"workflow_execution_id": run.WorkflowExecutionID,
+			"phase":                 run.Phase,
+			"objective_value":       run.ObjectiveValue,
+			"modified_at":           time.Now().UTC(),
+		}).
+		Where(sq.Eq{"id": run.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// finishSweep sets the final phase of a sweep and its best run
+func (c *Client) finishSweep(sweep *Sweep, phase SweepPhase, message string) error {
+	now := time.Now().UTC()
+	sweep.Phase = phase
+	sweep.Message = message
+	sweep.FinishedAt = &now
+
+	sweep.BestRunID = nil
+	if best := bestSweepRun(sweep.Runs, sweep.ObjectiveGoal); best != nil {
+		sweep.BestRunID = &best.ID
+	}
+
+	_, err := sb.Update("sweeps").
+		SetMap(sq.Eq{
+			"phase":       sweep.Phase,
+			"message":     sweep.Message,
+			"best_run_id": sweep.BestRunID,
+			"finished_at": sweep.FinishedAt,
+			"modified_at": now,
+		}).
+		Where(sq.Eq{"id": sweep.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// startSweepRun creates the workflow execution of a pending run
+func (c *Client) startSweepRun(sweep *Sweep, workflowTemplate *WorkflowTemplate, run *SweepRun) error {
+	labels := make(map[string]string)
+	for key, value := range sweep.Labels {
+		labels[key] = value
+	}
+	labels[sweepLabelKey] = sweep.UID
+
+	workflowExecution, err := c.CreateWorkflowExecution(sweep.Namespace, &WorkflowExecution{
+		Parameters: run.Parameters,
+		Labels:     labels,
+	}, workflowTemplate)
+	if err != nil {
+		return err
+	}
+
+	run.WorkflowExecutionID = &workflowExecution.ID
+	run.WorkflowExecution = workflowExecution
+	run.Phase = SweepRunning
+
+	return c.updateSweepRun(run)
+}
+
+// reconcileSweep updates the runs of a running sweep from their workflow executions, promotes the best runs of
+// successive halving, starts pending runs up to the max concurrency, and finishes the sweep when all runs are done.
+func (c *Client) reconcileSweep(sweep *Sweep) error {
+	tx, err := c.lockSweep(sweep.ID)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Another server may have finished or terminated the sweep while waiting for the lock
+	phase := SweepPhase("")
+	err = sb.Select("phase").
+		From("sweeps").
+		Where(sq.Eq{"id": sweep.ID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&phase)
+	if err != nil {
+		return err
+	}
+	if phase != SweepRunning {
+		return nil
+	}
+
+	runs, err := c.selectSweepRuns(sweep.ID)
+	if err != nil {
+		return err
+	}
+	sweep.Runs = runs
+
+	running := 0
+	for _, run := range runs {
+		if run.Phase != SweepRunning {
+			continue
+		}
+
+		phase := SweepFailed // the workflow execution was removed
+		if run.WorkflowExecutionID != nil {
+			phase = sweepRunPhase(run.WorkflowExecution.Phase)
+		}
+		if phase == SweepRunning {
+			running++
+			continue
+		}
+
+		run.Phase = phase
+		if phase == SweepSucceeded {
+			run.ObjectiveValue = sweepObjectiveValue(run.WorkflowExecution.Metrics, sweep.ObjectiveMetric)
+		}
+		if err := c.updateSweepRun(run); err != nil {
+			return err
+		}
+	}
+
+	pending := make([]*SweepRun, 0)
+	for _, run := range runs {
+		if run.Phase == SweepPending {
+			pending = append(pending, run)
+		}
+	}
+
+	if sweep.Strategy == SweepStrategySuccessiveHalving && running == 0 && len(pending) == 0 {
+		promoted, err := c.promoteSweepRung(sweep)
+		if err != nil {
+			return err
+		}
+		if promoted {
+			return c.reconcileSweepRuns(sweep)
+		}
+	}
+
+	if running == 0 && len(pending) == 0 {
+		if bestSweepRun(sweep.Runs, sweep.ObjectiveGoal) == nil {
+			return c.finishSweep(sweep, SweepFailed, "No run reported the objective metric "+sweep.ObjectiveMetric+".")
+		}
+		return c.finishSweep(sweep, SweepSucceeded, "")
+	}
+
+	return c.startSweepRuns(sweep, pending, sweep.MaxConcurrency-running)
+}
+
+// reconcileSweepRuns starts the pending runs of a sweep after a promotion
+func (c *Client) reconcileSweepRuns(sweep *Sweep) error {
+	runs, err := c.selectSweepRuns(sweep.ID)
+	if err != nil {
+		return err
+	}
+	sweep.Runs = runs
+
+	pending := make([]*SweepRun, 0)
+	for _, run := range runs {
+		if run.Phase == SweepPending {
+			pending = append(pending, run)
+		}
+	}
+
+	return c.startSweepRuns(sweep, pending, sweep.MaxConcurrency)
+}
+
+// startSweepRuns starts up to count of the pending runs
+func (c *Client) startSweepRuns(sweep *Sweep, pending []*SweepRun, count int) error {
+	if count <= 0 || len(pending) == 0 {
+		return nil
+	}
+
+	workflowTemplate, err := c.GetWorkflowTemplate(sweep.Namespace, sweep.WorkflowTemplate.UID, sweep.WorkflowTemplate.Version)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count && i < len(pending); i++ {
+		if err := c.startSweepRun(sweep, workflowTemplate, pending[i]); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": sweep.Namespace,
+				"UID":       sweep.UID,
+				"Trial":     pending[i].Trial,
+				"Error":     err.Error(),
+			}).Error("Unable to start sweep run.")
+
+			pending[i].Phase = SweepFailed
+			if err := c.updateSweepRun(pending[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// promoteSweepRung creates the runs of the next rung of a successive halving sweep from the best runs of the last rung.
+// It returns false if the last rung is the final one, or if no run of it has an objective value.
+func (c *Client) promoteSweepRung(sweep *Sweep) (bool, error) {
+	lastRung := 0
+	for _, run := range sweep.Runs {
+		if run.Rung > lastRung {
+			lastRung = run.Rung
+		}
+	}
+	if lastRung >= sweep.Spec.maxRung() {
+		return false, nil
+	}
+
+	rungRuns := make([]*SweepRun, 0)
+	for _, run := range sweep.Runs {
+		if run.Rung == lastRung {
+			rungRuns = append(rungRuns, run)
+		}
+	}
+
+	promoted := promoteSweepRuns(rungRuns, sweep.Spec.reductionFactor(), sweep.ObjectiveGoal)
+	if len(promoted) == 0 {
+		return false, nil
+	}
+
+	resource := sweep.Spec.rungResource(lastRung + 1)
+	trials := make(map[int][]Parameter)
+	for _, run := range promoted {
+		parameters := make([]Parameter, 0, len(run.Parameters))
+		for _, parameter := range run.Parameters {
+			if parameter.Name == sweep.Spec.ResourceParameter {
+				parameter.Value = &resource
+			}
+			parameters = append(parameters, parameter)
+		}
+		trials[run.Trial] = parameters
+	}
+
+	if err := insertSweepRuns(c.DB, sweep.ID, lastRung+1, trials); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReconcileSweeps reconciles all running sweeps, see reconcileSweep.
+// Errors of a sweep are logged, so they don't stop the other sweeps.
+func (c *Client) ReconcileSweeps() error {
+	query := sb.Select(getSweepColumns("s")...).
+		Columns(`wt.uid "workflow_template.uid"`, `wt.name "workflow_template.name"`, `wtv.version "workflow_template.version"`).
+		From("sweeps s").
+		Join("workflow_template_versions wtv ON wtv.id = s.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{"s.phase": SweepRunning})
+
+	sweeps := make([]*Sweep, 0)
+	if err := c.DB.Selectx(&sweeps, query); err != nil {
+		return err
+	}
+
+	for _, sweep := range sweeps {
+		if err := c.reconcileSweep(sweep); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": sweep.Namespace,
+				"UID":       sweep.UID,
+				"Error":     err.Error(),
+			}).Error("Unable to reconcile sweep.")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,554 @@
+package v1
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util/sql"
+	"github.com/onepanelio/core/pkg/util/types"
+	uid2 "github.com/onepanelio/core/pkg/util/uid"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// sweepMaxRuns is the maximum number of runs a sweep can start with
+	sweepMaxRuns = 1000
+	// sweepDefaultReductionFactor is the default fraction, 1/sweepDefaultReductionFactor, of the runs that are promoted
+	// to the next rung of a successive halving sweep
+	sweepDefaultReductionFactor = 3
+	// sweepLabelKey is the label the workflow executions of a sweep have, with the uid of the sweep as value
+	sweepLabelKey = "sweep"
+)
+
+// SweepStrategy is how a sweep picks the parameters of its runs
+type SweepStrategy string
+
+const (
+	// SweepStrategyGrid runs every combination of the parameter values
+	SweepStrategyGrid SweepStrategy = "grid"
+	// SweepStrategyRandom runs MaxRuns random samples of the parameter values
+	SweepStrategyRandom SweepStrategy = "random"
+	// SweepStrategySuccessiveHalving runs MaxRuns random samples with a small resource, like epochs, and keeps
+	// running the best 1/ReductionFactor of them with ReductionFactor times the resource, until MaxResource is reached.
+	SweepStrategySuccessiveHalving SweepStrategy = "successiveHalving"
+)
+
+// SweepPhase is the phase of a sweep or of one of its runs
+type SweepPhase string
+
+const (
+	SweepPending    SweepPhase = "Pending"
+	SweepRunning    SweepPhase = "Running"
+	SweepSucceeded  SweepPhase = "Succeeded"
+	SweepFailed     SweepPhase = "Failed"
+	SweepTerminated SweepPhase = "Terminated"
+)
+
+// SweepObjectiveGoal is whether the objective metric of a sweep should be minimized or maximized
+type SweepObjectiveGoal string
+
+const (
+	SweepObjectiveMinimize SweepObjectiveGoal = "minimize"
+	SweepObjectiveMaximize SweepObjectiveGoal = "maximize"
+)
+
+// SweepParameter is the search space of a parameter of the workflow template.
+// It is either a list of Values, or a numeric range from Min to Max.
+// Grid sweeps need a Step for ranges. Random sweeps sample ranges uniformly, or log-uniformly if Scale is "log".
+// If Integer is true, the values of ranges are rounded to integers.
+type SweepParameter struct {
+	Name    string   `json:"name"`
+	Values  []string `json:"values,omitempty"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Step    *float64 `json:"step,omitempty"`
+	Scale   string   `json:"scale,omitempty"`
+	Integer bool     `json:"integer,omitempty"`
+}
+
+// SweepSpec is the search space of a sweep and the options of its strategy
+type SweepSpec struct {
+	Parameters []*SweepParameter `json:"parameters"`
+	// FixedParameters are passed to every run as they are
+	FixedParameters []Parameter `json:"fixedParameters,omitempty"`
+	// MaxRuns is the number of samples of random and successive halving sweeps, and the maximum number of grid combinations
+	MaxRuns int `json:"maxRuns,omitempty"`
+	// Seed makes the samples reproducible
+	Seed int64 `json:"seed"`
+	// ResourceParameter is the parameter successive halving increases for the best runs, like the number of epochs.
+	// It goes from MinResource to MaxResource, multiplied by ReductionFactor at each rung.
+	ResourceParameter string  `json:"resourceParameter,omitempty"`
+	MinResource       float64 `json:"minResource,omitempty"`
+	MaxResource       float64 `json:"maxResource,omitempty"`
+	ReductionFactor   int     `json:"reductionFactor,omitempty"`
+}
+
+// Value returns the spec as JSON, to support the JSONB column
+func (s SweepSpec) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan reads the spec from JSON, to support the JSONB column
+func (s *SweepSpec) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(t), s)
+	case []byte:
+		return json.Unmarshal(t, s)
+	case nil:
+		*s = SweepSpec{}
+		return nil
+	}
+
+	return errors.New("incompatible type for SweepSpec")
+}
+
+// Sweep runs a workflow template version with many values of its parameters, to find the ones
+// with the best value of an objective metric.
+type Sweep struct {
+	ID                        uint64
+	UID                       string
+	Name                      string
+	Namespace                 string
+	WorkflowTemplateVersionID uint64            `db:"workflow_template_version_id"`
+	WorkflowTemplate          *WorkflowTemplate `db:"workflow_template"`
+	Strategy                  SweepStrategy
+	Spec                      SweepSpec
+	ObjectiveMetric           string             `db:"objective_metric"`
+	ObjectiveGoal             SweepObjectiveGoal `db:"objective_goal"`
+	MaxConcurrency            int                `db:"max_concurrency"`
+	Phase                     SweepPhase
+	Message                   string
+	BestRunID                 *uint64 `db:"best_run_id"`
+	Labels                    types.JSONLabels
+	StartedAt                 *time.Time  `db:"started_at"`
+	FinishedAt                *time.Time  `db:"finished_at"`
+	CreatedAt                 time.Time   `db:"created_at"`
+	ModifiedAt                *time.Time  `db:"modified_at"`
+	Runs                      []*SweepRun `db:"-"`
+}
+
+// SweepRun is a run of a sweep with one set of parameter values.
+// Successive halving runs the same trial again at higher rungs.
+type SweepRun struct {
+	ID                  uint64
+	SweepID             uint64  `db:"sweep_id"`
+	WorkflowExecutionID *uint64 `db:"workflow_execution_id"`
+	Trial               int
+	Rung                int
+	ParametersBytes     []byte      `db:"parameters"`
+	Parameters          []Parameter `db:"-"`
+	Phase               SweepPhase
+	ObjectiveValue      *float64   `db:"objective_value"`
+	CreatedAt           time.Time  `db:"created_at"`
+	ModifiedAt          *time.Time `db:"modified_at"`
+	// WorkflowExecution has the uid, phase and metrics of the workflow execution of the run, if it was started
+	WorkflowExecution *WorkflowExecution `db:"workflow_execution"`
+}
+
+// GenerateUID generates a uid from the input name and sets it on the sweep
+func (s *Sweep) GenerateUID(name string) error {
+	result, err := uid2.GenerateUID(name, 63)
+	if err != nil {
+		return err
+	}
+
+	s.UID = result
+
+	return nil
+}
+
+// BestRun returns the run with the best objective value, or nil if there is none yet
+func (s *Sweep) BestRun() *SweepRun {
+	if s.BestRunID == nil {
+		return bestSweepRun(s.Runs, s.ObjectiveGoal)
+	}
+
+	for _, run := range s.Runs {
+		if run.ID == *s.BestRunID {
+			return run
+		}
+	}
+
+	return nil
+}
+
+// getSweepColumns returns all of the columns for sweep modified by alias, destination.
+// see formatColumnSelect
+func getSweepColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"uid",
+		"name",
+		"namespace",
+		"workflow_template_version_id",
+		"strategy",
+		"spec",
+		"objective_metric",
+		"objective_goal",
+		"max_concurrency",
+		"phase",
+		"message",
+		"best_run_id",
+		"labels",
+		"started_at",
+		"finished_at",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
+
+// getSweepRunColumns returns all of the columns for sweepRun modified by alias, destination.
+// see formatColumnSelect
+func getSweepRunColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"sweep_id",
+		"workflow_execution_id",
+		"trial",
+		"rung",
+		"parameters",
+		"phase",
+		"objective_value",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
+
+// formatSweepValue formats a numeric parameter value.
+// Values are rounded to 12 significant digits, so steps like 0.1 give "0.3" rather than "0.30000000000000004".
+func formatSweepValue(value float64, integer bool) string {
+	if integer {
+		return strconv.FormatInt(int64(math.Round(value)), 10)
+	}
+
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 12, 64), 64)
+	if err != nil {
+		rounded = value
+	}
+
+	return strconv.FormatFloat(rounded, 'g', -1, 64)
+}
+
+// validate returns an error if the search space of the parameter is invalid for the strategy
+func (p *SweepParameter) validate(strategy SweepStrategy) error {
+	if p.Name == "" {
+		return errors.New("sweep parameters need a name")
+	}
+
+	if len(p.Values) > 0 {
+		if p.Min != nil || p.Max != nil {
+			return fmt.Errorf("sweep parameter '%v' can have values or a range, but not both", p.Name)
+		}
+		return nil
+	}
+
+	if p.Min == nil || p.Max == nil {
+		return fmt.Errorf("sweep parameter '%v' needs values, or a min and max", p.Name)
+	}
+	if *p.Max < *p.Min {
+		return fmt.Errorf("the max of sweep parameter '%v' is less than its min", p.Name)
+	}
+	if p.Scale != "" && p.Scale != "linear" && p.Scale != "log" {
+		return fmt.Errorf("unknown scale '%v' of sweep parameter '%v'", p.Scale, p.Name)
+	}
+	if p.Scale == "log" && *p.Min <= 0 {
+		return fmt.Errorf("the min of sweep parameter '%v' must be positive for a log scale", p.Name)
+	}
+	if p.Step != nil && *p.Step <= 0 {
+		return fmt.Errorf("the step of sweep parameter '%v' must be positive", p.Name)
+	}
+	if strategy == SweepStrategyGrid && p.Step == nil {
+		return fmt.Errorf("sweep parameter '%v' needs values, or a step for a grid", p.Name)
+	}
+	if strategy == SweepStrategyGrid && (*p.Max-*p.Min)/(*p.Step) >= sweepMaxRuns {
+		return fmt.Errorf("the step of sweep parameter '%v' gives more than %v values", p.Name, sweepMaxRuns)
+	}
+
+	return nil
+}
+
+// gridValues returns the values of the parameter in a grid
+func (p *SweepParameter) gridValues() []string {
+	if len(p.Values) > 0 {
+		return p.Values
+	}
+
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+	// The steps are counted, instead of added up, so rounding errors don't add up either.
+	// The steps are bounded, not the values, as integer values of small steps repeat.
+	for i := 0; i <= sweepMaxRuns; i++ {
+		value := *p.Min + float64(i)*(*p.Step)
+		if value > *p.Max+*p.Step*1e-9 {
+			break
+		}
+
+		formatted := formatSweepValue(value, p.Integer)
+		if !seen[formatted] {
+			seen[formatted] = true
+			values = append(values, formatted)
+		}
+	}
+
+	return values
+}
+
+// sample returns a random value of the parameter
+func (p *SweepParameter) sample(rng *rand.Rand) string {
+	if len(p.Values) > 0 {
+		return p.Values[rng.Intn(len(p.Values))]
+	}
+
+	var value float64
+	if p.Scale == "log" {
+		value = math.Exp(math.Log(*p.Min) + rng.Float64()*(math.Log(*p.Max)-math.Log(*p.Min)))
+	} else {
+		value = *p.Min + rng.Float64()*(*p.Max-*p.Min)
+	}
+
+	if p.Step != nil {
+		value = *p.Min + math.Round((value-*p.Min)/(*p.Step))*(*p.Step)
+		value = math.Min(value, *p.Max)
+	}
+
+	return formatSweepValue(value, p.Integer)
+}
+
+// validate returns an error if the spec is invalid for the strategy
+func (s *SweepSpec) validate(strategy SweepStrategy) error {
+	switch strategy {
+	case SweepStrategyGrid, SweepStrategyRandom, SweepStrategySuccessiveHalving:
+	default:
+		return fmt.Errorf("unknown sweep strategy '%v'", strategy)
+	}
+
+	if len(s.Parameters) == 0 {
+		return errors.New("sweeps need at least one parameter")
+	}
+
+	names := make(map[string]bool)
+	for _, parameter := range s.Parameters {
+		if err := parameter.validate(strategy); err != nil {
+			return err
+		}
+		if names[parameter.Name] {
+			return fmt.Errorf("sweep parameter '%v' is repeated", parameter.Name)
+		}
+		names[parameter.Name] = true
+	}
+
+	if s.MaxRuns < 0 || s.MaxRuns > sweepMaxRuns {
+		return fmt.Errorf("sweeps can have at most %v runs", sweepMaxRuns)
+	}
+	if strategy != SweepStrategyGrid && s.MaxRuns == 0 {
+		return errors.New("random and successive halving sweeps need max runs")
+	}
+
+	if strategy == SweepStrategySuccessiveHalving {
+		if s.ResourceParameter == "" {
+			return errors.New("successive halving sweeps need a resource parameter")
+		}
+		if names[s.ResourceParameter] {
+			return fmt.Errorf("the resource parameter '%v' can't be swept", s.ResourceParameter)
+		}
+		if s.MinResource <= 0 || s.MaxResource < s.MinResource {
+			return errors.New("successive halving sweeps need a positive min resource, and a max resource that is not less")
+		}
+		if s.ReductionFactor == 1 || s.ReductionFactor < 0 {
+			return errors.New("the reduction factor of successive halving sweeps must be at least 2")
+		}
+	}
+
+	return nil
+}
+
+// reductionFactor returns the reduction factor of successive halving, or the default
+func (s *SweepSpec) reductionFactor() int {
+	if s.ReductionFactor == 0 {
+		return sweepDefaultReductionFactor
+	}
+
+	return s.ReductionFactor
+}
+
+// maxRung returns the last rung of successive halving, where the resource reaches MaxResource
+func (s *SweepSpec) maxRung() int {
+	rung := 0
+	for resource := s.MinResource; resource*float64(s.reductionFactor()) <= s.MaxResource*(1+1e-9); resource *= float64(s.reductionFactor()) {
+		rung++
+	}
+
+	return rung
+}
+
+// rungResource returns the value of the resource parameter at a rung of successive halving
+func (s *SweepSpec) rungResource(rung int) string {
+	resource := s.MinResource * math.Pow(float64(s.reductionFactor()), float64(rung))
+	resource = math.Min(resource, s.MaxResource)
+
+	return formatSweepValue(resource, resource == math.Trunc(resource))
+}
+
+// trialParameters returns the parameters of a run: the fixed parameters, overridden by the sampled values.
+func (s *SweepSpec) trialParameters(values []Parameter) []Parameter {
+	result := make([]Parameter, 0, len(s.FixedParameters)+len(values))
+	overridden := make(map[string]bool)
+	for _, value := range values {
+		overridden[value.Name] = true
+	}
+
+	for _, parameter := range s.FixedParameters {
+		if !overridden[parameter.Name] {
+			result = append(result, parameter)
+		}
+	}
+
+	return append(result, values...)
+}
+
+// generateTrials returns the parameter values of the first runs of a sweep
+func (s *SweepSpec) generateTrials(strategy SweepStrategy) ([][]Parameter, error) {
+	if strategy == SweepStrategyGrid {
+		trials := [][]Parameter{{}}
+		for _, parameter := range s.Parameters {
+			values := parameter.gridValues()
+			next := make([][]Parameter, 0, len(trials)*len(values))
+			for _, trial := range trials {
+				for i := range values {
+					combination := make([]Parameter, len(trial), len(trial)+1)
+					copy(combination, trial)
+					next = append(next, append(combination, Parameter{Name: parameter.Name, Value: &values[i]}))
+				}
+			}
+
+			trials = next
+			maxRuns := s.MaxRuns
+			if maxRuns == 0 {
+				maxRuns = sweepMaxRuns
+			}
+			if len(trials) > maxRuns {
+				return nil, fmt.Errorf("the grid has more than %v combinations", maxRuns)
+			}
+		}
+
+		return trials, nil
+	}
+
+	rng := rand.New(rand.NewSource(s.Seed))
+	trials := make([][]Parameter, 0, s.MaxRuns)
+	for i := 0; i < s.MaxRuns; i++ {
+		trial := make([]Parameter, 0, len(s.Parameters))
+		for _, parameter := range s.Parameters {
+			value := parameter.sample(rng)
+			trial = append(trial, Parameter{Name: parameter.Name, Value: &value})
+		}
+		trials = append(trials, trial)
+	}
+
+	return trials, nil
+}
+
+// sweepRunPhase returns the phase of a run from the phase of its workflow execution
+func sweepRunPhase(phase wfv1.NodePhase) SweepPhase {
+	switch phase {
+	case wfv1.NodeSucceeded:
+		return SweepSucceeded
+	case wfv1.NodeFailed, wfv1.NodeError:
+		return SweepFailed
+	case "Terminated":
+		return SweepTerminated
+	}
+
+	return SweepRunning
+}
+
+// sweepObjectiveValue returns the value of the metric with the given name, or nil if there is none
+func sweepObjectiveValue(metrics Metrics, name string) *float64 {
+	for _, metric := range metrics {
+		if metric.Name == name {
+			value := metric.Value
+			return &value
+		}
+	}
+
+	return nil
+}
+
+// sweepRunIsBetter returns true if the objective value of a is better than the one of b
+func sweepRunIsBetter(a, b *SweepRun, goal SweepObjectiveGoal) bool {
+	if goal == SweepObjectiveMaximize {
+		return *a.ObjectiveValue > *b.ObjectiveValue
+	}
+
+	return *a.ObjectiveValue < *b.ObjectiveValue
+}
+
+// sortSweepRunsByObjective sorts the runs with an objective value from best to worst, and drops the others.
+// Ties keep the order of the trials.
+func sortSweepRunsByObjective(runs []*SweepRun, goal SweepObjectiveGoal) []*SweepRun {
+	result := make([]*SweepRun, 0, len(runs))
+	for _, run := range runs {
+		if run.ObjectiveValue != nil {
+			result = append(result, run)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if *result[i].ObjectiveValue != *result[j].ObjectiveValue {
+			return sweepRunIsBetter(result[i], result[j], goal)
+		}
+		return result[i].Trial < result[j].Trial
+	})
+
+	return result
+}
+
+// bestSweepRun returns the run with the best objective value in the highest rung that has any, or nil if there is none.
+// Lower rungs of successive halving ran with less resources, so they are not compared with higher ones.
+func bestSweepRun(runs []*SweepRun, goal SweepObjectiveGoal) *SweepRun {
+	highestRung := -1
+	for _, run := range runs {
+		if run.ObjectiveValue != nil && run.Rung > highestRung {
+			highestRung = run.Rung
+		}
+	}
+
+	candidates := make([]*SweepRun, 0)
+	for _, run := range runs {
+		if run.Rung == highestRung {
+			candidates = append(candidates, run)
+		}
+	}
+
+	sorted := sortSweepRunsByObjective(candidates, goal)
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	return sorted[0]
+}
+
+// promoteSweepRuns returns the runs of a completed rung of successive halving that run again in the next rung:
+// the best 1/reductionFactor of them, and at least one. Runs without an objective value are never promoted.
+func promoteSweepRuns(runs []*SweepRun, reductionFactor int, goal SweepObjectiveGoal) []*SweepRun {
+	count := len(runs) / reductionFactor
+	if count == 0 {
+		count = 1
+	}
+
+	sorted := sortSweepRunsByObjective(runs, goal)
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+
+	return sorted
+}
@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util/ptr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestSweepSpec_GenerateTrials_Grid makes sure grids have every combination of values and ranges
+func TestSweepSpec_GenerateTrials_Grid(t *testing.T) {
+	spec := &SweepSpec{
+		Parameters: []*SweepParameter{
+			{Name: "optimizer", Values: []string{"adam", "sgd"}},
+			{Name: "learning-rate", Min: ptr.Float64(0.1), Max: ptr.Float64(0.3), Step: ptr.Float64(0.1)},
+		},
+	}
+	assert.Nil(t, spec.validate(SweepStrategyGrid))
+
+	trials, err := spec.generateTrials(SweepStrategyGrid)
+	assert.Nil(t, err)
+	assert.Len(t, trials, 6)
+	assert.Equal(t, "adam", *trials[0][0].Value)
+	assert.Equal(t, "0.1", *trials[0][1].Value)
+	assert.Equal(t, "0.3", *trials[2][1].Value)
+	assert.Equal(t, "sgd", *trials[5][0].Value)
+
+	spec.MaxRuns = 5
+	_, err = spec.generateTrials(SweepStrategyGrid)
+	assert.NotNil(t, err)
+}
+
+// TestSweepSpec_GenerateTrials_Random makes sure samples are in range and reproducible with the seed
+func TestSweepSpec_GenerateTrials_Random(t *testing.T) {
+	spec := &SweepSpec{
+		Parameters: []*SweepParameter{
+			{Name: "batch-size", Min: ptr.Float64(16), Max: ptr.Float64(256), Integer: true},
+			{Name: "learning-rate", Min: ptr.Float64(1e-5), Max: ptr.Float64(1e-1), Scale: "log"},
+		},
+		MaxRuns: 20,
+		Seed:    42,
+	}
+	assert.Nil(t, spec.validate(SweepStrategyRandom))
+
+	trials, err := spec.generateTrials(SweepStrategyRandom)
+	assert.Nil(t, err)
+	assert.Len(t, trials, 20)
+
+	again, err := spec.generateTrials(SweepStrategyRandom)
+	assert.Nil(t, err)
+	assert.Equal(t, trials, again)
+
+	for _, trial := range trials {
+		assert.NotContains(t, *trial[0].Value, ".")
+	}
+}
+
+// TestSweepSpec_Validate makes sure invalid search spaces are rejected
+func TestSweepSpec_Validate(t *testing.T) {
+	tests := []struct {
+		strategy SweepStrategy
+		spec     SweepSpec
+	}{
+		{"bayesian", SweepSpec{Parameters: []*SweepParameter{{Name: "a", Values: []string{"1"}}}}},
+		{SweepStrategyGrid, SweepSpec{}},
+		{SweepStrategyGrid, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Min: ptr.Float64(0), Max: ptr.Float64(1)}}}},
+		{SweepStrategyGrid, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Values: []string{"1"}}, {Name: "a", Values: []string{"2"}}}}},
+		{SweepStrategyGrid, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Min: ptr.Float64(0), Max: ptr.Float64(1e9), Step: ptr.Float64(1e-3), Integer: true}}}},
+		{SweepStrategyRandom, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Values: []string{"1"}}}}},
+		{SweepStrategyRandom, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Min: ptr.Float64(0), Max: ptr.Float64(1), Scale: "log"}}, MaxRuns: 1}},
+		{SweepStrategySuccessiveHalving, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Values: []string{"1"}}}, MaxRuns: 9}},
+		{SweepStrategySuccessiveHalving, SweepSpec{Parameters: []*SweepParameter{{Name: "a", Values: []string{"1"}}}, MaxRuns: 9, ResourceParameter: "a", MinResource: 1, MaxResource: 9}},
+	}
+
+	for i, test := range tests {
+		assert.NotNil(t, test.spec.validate(test.strategy), i)
+	}
+}
+
+// TestSweepSpec_Rungs makes sure the resource grows by the reduction factor up to the max resource
+func TestSweepSpec_Rungs(t *testing.T) {
+	spec := &SweepSpec{MinResource: 1, MaxResource: 27}
+	assert.Equal(t, 3, spec.maxRung())
+	assert.Equal(t, "1", spec.rungResource(0))
+	assert.Equal(t, "9", spec.rungResource(2))
+	assert.Equal(t, "27", spec.rungResource(3))
+
+	spec = &SweepSpec{MinResource: 2, MaxResource: 10, ReductionFactor: 2}
+	assert.Equal(t, 2, spec.maxRung())
+	assert.Equal(t, "8", spec.rungResource(2))
+}
+
+// TestPromoteSweepRuns makes sure the best runs with an objective value are promoted, and at least one
+func TestPromoteSweepRuns(t *testing.T) {
+	runs := []*SweepRun{
+		{Trial: 0, ObjectiveValue: ptr.Float64(0.5)},
+		{Trial: 1, ObjectiveValue: ptr.Float64(0.2)},
+		{Trial: 2},
+		{Trial: 3, ObjectiveValue: ptr.Float64(0.9)},
+		{Trial: 4, ObjectiveValue: ptr.Float64(0.2)},
+		{Trial: 5, ObjectiveValue: ptr.Float64(0.7)},
+	}
+
+	promoted := promoteSweepRuns(runs, 3, SweepObjectiveMinimize)
+	assert.Len(t, promoted, 2)
+	assert.Equal(t, 1, promoted[0].Trial)
+	assert.Equal(t, 4, promoted[1].Trial)
+
+	promoted = promoteSweepRuns(runs, 10, SweepObjectiveMaximize)
+	assert.Len(t, promoted, 1)
+	assert.Equal(t, 3, promoted[0].Trial)
+
+	assert.Empty(t, promoteSweepRuns([]*SweepRun{{Trial: 0}}, 3, SweepObjectiveMinimize))
+}
+
+// TestBestSweepRun makes sure the best run is taken from the highest rung with objective values
+func TestBestSweepRun(t *testing.T) {
+	runs := []*SweepRun{
+		{ID: 1, Trial: 0, Rung: 0, ObjectiveValue: ptr.Float64(0.1)},
+		{ID: 2, Trial: 1, Rung: 0, ObjectiveValue: ptr.Float64(0.4)},
+		{ID: 3, Trial: 1, Rung: 1, ObjectiveValue: ptr.Float64(0.3)},
+		{ID: 4, Trial: 0, Rung: 2},
+	}
+
+	assert.Equal(t, uint64(3), bestSweepRun(runs, SweepObjectiveMinimize).ID)
+	assert.Nil(t, bestSweepRun(runs[3:], SweepObjectiveMinimize))
+
+	sweep := &Sweep{Runs: runs, BestRunID: &runs[1].ID}
+	assert.Equal(t, uint64(2), sweep.BestRun().ID)
+}
@@ -60,6 +60,9 @@ type ConfigMap struct {
 type LogEntry struct {
 	Timestamp time.Time
 	Content   string
+	// PodName and ContainerName are only set for the merged logs of a workflow execution
+	PodName       string
+	ContainerName string
 }
 
 // IsEmpty returns true if the content for the log entry is just an empty string
@@ -108,6 +111,7 @@ type WorkflowExecutionStatisticReport struct {
 	Completed          int32
 	Failed             int32
 	Terminated         int32
+	Queued             int32
 }
 
 // WorkspaceStatisticReport contains stats on the phases the workspaces in the system are in
@@ -0,0 +1,92 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+// Client is a struct used for accessing a container of Azure Blob Storage.
+type Client struct {
+	azblob.ContainerURL
+	credential *azblob.SharedKeyCredential
+}
+
+// Config is the information needed to connect to a container of Azure Blob Storage.
+// If Endpoint is empty, https://{AccountName}.blob.core.windows.net is used.
+type Config struct {
+	AccountName string
+	AccountKey  string
+	Container   string
+	Endpoint    string
+}
+
+// NewClient handles the details of initializing the connection to Azure Blob Storage with a shared key.
+func NewClient(config Config) (azureClient *Client, err error) {
+	credential, err := azblob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+	if err != nil {
+		return
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+	containerURL, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + config.Container)
+	if err != nil {
+		return
+	}
+
+	return &Client{
+		ContainerURL: azblob.NewContainerURL(*containerURL, azblob.NewPipeline(credential, azblob.PipelineOptions{})),
+		credential:   credential,
+	}, nil
+}
+
+// SignedURL creates a URL that can be used to GET the blob for the duration of expires, without further authentication.
+// The URL has a shared access signature, signed with the account key.
+func (c *Client) SignedURL(key string, expires time.Duration, contentDisposition string) (signedURL string, err error) {
+	blobURL := c.NewBlobURL(key)
+	parts := azblob.NewBlobURLParts(blobURL.URL())
+
+	// Only allow plain http if the endpoint uses it, like a local emulator
+	protocol := azblob.SASProtocolHTTPS
+	if parts.Scheme == "http" {
+		protocol = azblob.SASProtocolHTTPSandHTTP
+	}
+
+	sas, err := azblob.BlobSASSignatureValues{
+		Protocol:           protocol,
+		ExpiryTime:         time.Now().Add(expires).UTC(),
+		ContainerName:      parts.ContainerName,
+		BlobName:           parts.BlobName,
+		Permissions:        azblob.BlobSASPermissions{Read: true}.String(),
+		ContentDisposition: contentDisposition,
+	}.NewSASQueryParameters(c.credential)
+	if err != nil {
+		return
+	}
+	parts.SAS = sas
+
+	result := parts.URL()
+	return result.String(), nil
+}
+
+// IsNotFound returns true if err is the response of Azure Blob Storage to a blob or container that does not exist.
+func IsNotFound(err error) bool {
+	storageErr, ok := err.(azblob.StorageError)
+	if !ok {
+		return false
+	}
+
+	if storageErr.ServiceCode() == azblob.ServiceCodeBlobNotFound || storageErr.ServiceCode() == azblob.ServiceCodeContainerNotFound {
+		return true
+	}
+
+	// Responses to HEAD requests have no body with a service code
+	return storageErr.Response() != nil && storageErr.Response().StatusCode == http.StatusNotFound
+}
@@ -3,7 +3,9 @@ package util
 import (
 	"errors"
 	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
+	"strings"
 
 	"github.com/lib/pq"
 	"google.golang.org/grpc/codes"
@@ -13,6 +15,8 @@ import (
 type UserError struct {
 	Code    codes.Code
 	Message string
+	// FieldViolations are returned as a BadRequest detail, one for each field that is invalid
+	FieldViolations []*errdetails.BadRequest_FieldViolation
 }
 
 // Error returns error messages
@@ -22,7 +26,17 @@ func (e *UserError) Error() string {
 
 // GRPCStatus is used by gRPC to return the correct gRPC status codes
 func (e *UserError) GRPCStatus() *status.Status {
-	return status.New(e.Code, e.Message)
+	s := status.New(e.Code, e.Message)
+	if len(e.FieldViolations) == 0 {
+		return s
+	}
+
+	detailed, err := s.WithDetails(&errdetails.BadRequest{FieldViolations: e.FieldViolations})
+	if err != nil {
+		return s
+	}
+
+	return detailed
 }
 
 // NewUserError returns an instance of UserError with the appropriate code and message
@@ -30,6 +44,21 @@ func NewUserError(code codes.Code, message string) error {
 	return &UserError{Code: code, Message: message}
 }
 
+// NewFieldViolationsError returns an InvalidArgument UserError with a violation for each field that is invalid.
+// The message lists the violations, for clients that don't read the details.
+func NewFieldViolationsError(message string, violations []*errdetails.BadRequest_FieldViolation) error {
+	descriptions := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		descriptions = append(descriptions, fmt.Sprintf("%v: %v", violation.Field, violation.Description))
+	}
+
+	return &UserError{
+		Code:            codes.InvalidArgument,
+		Message:         fmt.Sprintf("%v %v", message, strings.Join(descriptions, "; ")),
+		FieldViolations: violations,
+	}
+}
+
 func pqError(err *pq.Error) (code codes.Code) {
 	switch err.Code {
 	case "23505":
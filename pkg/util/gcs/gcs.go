@@ -7,6 +7,7 @@ import (
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
 	"io"
+	"time"
 )
 
 // Client is a struct used for accessing Google Cloud Storage.
@@ -55,3 +56,19 @@ func (c *Client) GetObject(bucket, key string) (stream io.ReadCloser, err error)
 
 	return
 }
+
+// SignedURL creates a URL that can be used to GET the object for the duration of expires, without further authentication.
+// The URL is signed with the private key of the service account.
+func SignedURL(serviceAccountJSON, bucket, key string, expires time.Duration) (url string, err error) {
+	jwtConfig, err := google.JWTConfigFromJSON([]byte(serviceAccountJSON), storage.ScopeReadOnly)
+	if err != nil {
+		return
+	}
+
+	return storage.SignedURL(bucket, key, &storage.SignedURLOptions{
+		GoogleAccessID: jwtConfig.Email,
+		PrivateKey:     jwtConfig.PrivateKey,
+		Method:         "GET",
+		Expires:        time.Now().Add(expires),
+	})
+}
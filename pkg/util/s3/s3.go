@@ -10,8 +10,14 @@ type Client struct {
 	*minio.Client
 }
 
+type Core = minio.Core
+
 type GetObjectOptions = minio.GetObjectOptions
 
+type StatObjectOptions = minio.StatObjectOptions
+
+type PutObjectOptions = minio.PutObjectOptions
+
 type Config struct {
 	AccessKey string
 	SecretKey string
@@ -44,3 +50,9 @@ func (c *Client) GetObject(bucket, key string, opts GetObjectOptions) (stream io
 
 	return
 }
+
+// IsNotFound returns true if err is the response of S3 to an object or bucket that does not exist.
+func IsNotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NoSuchBucket"
+}
@@ -2,20 +2,17 @@ package v1
 
 import (
 	"bufio"
-	"cloud.google.com/go/storage"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
-	"github.com/onepanelio/core/pkg/util/gcs"
 	"github.com/onepanelio/core/pkg/util/label"
 	"github.com/onepanelio/core/pkg/util/ptr"
 	"github.com/onepanelio/core/pkg/util/request"
 	"github.com/onepanelio/core/pkg/util/types"
 	uid2 "github.com/onepanelio/core/pkg/util/uid"
-	"golang.org/x/net/context"
 	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
@@ -23,8 +20,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	yaml2 "sigs.k8s.io/yaml"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -37,15 +36,18 @@ import (
 	argojson "github.com/argoproj/pkg/json"
 	"github.com/onepanelio/core/pkg/util"
 	"github.com/onepanelio/core/pkg/util/env"
-	"github.com/onepanelio/core/pkg/util/s3"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// listFilesSortLimit is the maximum number of files in a directory that ListFiles sorts in memory
+const listFilesSortLimit = 100000
+
 var (
 	readEndOffset                   = env.GetEnv("ARTIFACT_RERPOSITORY_OBJECT_RANGE", "-102400")
 	workflowTemplateUIDLabelKey     = "onepanel.io/workflow-template-uid"
@@ -97,6 +99,8 @@ func typeWorkflow(wf *wfv1.Workflow) (workflow *WorkflowExecution) {
 type WorkflowExecutionFilter struct {
 	Labels []*Label
 	Phase  string // empty string means none
+	// Expression filters by metrics and parameters, like "metrics.accuracy > 0.9". See workflowExecutionQueryParser.
+	Expression string
 }
 
 // GetLabels returns the labels in the filter
@@ -119,13 +123,21 @@ func applyWorkflowExecutionFilter(sb sq.SelectBuilder, request *request.Request)
 		return sb, err
 	}
 
+	if filter.Expression != "" {
+		condition, err := parseWorkflowExecutionQuery(filter.Expression)
+		if err != nil {
+			return sb, util.NewUserError(codes.InvalidArgument, err.Error())
+		}
+		sb = sb.Where(condition)
+	}
+
 	switch filter.Phase {
 	case "":
 		return sb, nil
 	case "running":
 		sb = sb.Where(sq.Eq{
 			"we.finished_at": nil,
-			"we.phase":       []string{"Running", "Pending"},
+			"we.phase":       []string{"Running", "Pending", string(WorkflowExecutionPhaseSuspended)},
 		})
 	case "completed":
 		sb = sb.Where(sq.NotEq{
@@ -143,6 +155,10 @@ func applyWorkflowExecutionFilter(sb sq.SelectBuilder, request *request.Request)
 		sb = sb.Where(sq.Eq{
 			"we.phase": "Terminated",
 		})
+	case "queued":
+		sb = sb.Where(sq.Eq{
+			"we.phase": WorkflowExecutionPhaseQueued,
+		})
 	default:
 		return sb, fmt.Errorf("unknown workflow execution phase filter '%v'", filter.Phase)
 	}
@@ -395,6 +411,10 @@ func (c *Client) injectAutomatedFields(namespace string, wf *wfv1.Workflow, opts
 // If the database record does not exist, we still try to delete the argo workflow record.
 // No errors are returned if the records do not exist.
 func (c *Client) ArchiveWorkflowExecution(namespace, uid string) error {
+	if _, err := c.cancelQueuedWorkflowExecution(namespace, uid); err != nil {
+		return err
+	}
+
 	_, err := sb.Update("workflow_executions").
 		Set("is_archived", true).
 		Where(sq.Eq{
@@ -499,12 +519,19 @@ func (c *Client) createWorkflow(namespace string, workflowTemplateID uint64, wor
 	}
 	wf.Spec.Arguments.Parameters = newParameters
 
-	if err = injectWorkflowExecutionStatusCaller(wf, wfv1.NodeRunning); err != nil {
+	sysConfig, err := c.GetSystemConfig()
+	if err != nil {
 		return nil, err
 	}
 
-	if err = injectExitHandlerWorkflowExecutionStatistic(wf, &workflowTemplateID); err != nil {
-		return nil, err
+	if sysConfig.WorkflowStatusCallbacksEnabled() {
+		if err = injectWorkflowExecutionStatusCaller(wf, wfv1.NodeRunning); err != nil {
+			return nil, err
+		}
+
+		if err = injectExitHandlerWorkflowExecutionStatistic(wf, &workflowTemplateID); err != nil {
+			return nil, err
+		}
 	}
 
 	if err = c.injectAutomatedFields(namespace, wf, opts); err != nil {
@@ -516,39 +543,81 @@ func (c *Client) createWorkflow(namespace string, workflowTemplateID uint64, wor
 		return nil, err
 	}
 	wf.Spec.Templates = newTemplateOrder
-	createdArgoWorkflow, err := c.ArgoprojV1alpha1().Workflows(namespace).Create(wf)
-	if err != nil {
-		return nil, err
-	}
 
 	createdWorkflow = &WorkflowExecution{
-		Name:         createdArgoWorkflow.Name,
-		CreatedAt:    createdArgoWorkflow.CreationTimestamp.UTC(),
-		ArgoWorkflow: createdArgoWorkflow,
 		WorkflowTemplate: &WorkflowTemplate{
 			WorkflowTemplateVersionID: workflowTemplateVersionID,
 		},
 		Parameters: opts.Parameters,
 		Labels:     labels,
 	}
-
-	if err = createdWorkflow.GenerateUID(createdArgoWorkflow.Name); err != nil {
+	if err := c.submitWorkflowExecution(namespace, workflowTemplateID, wf, createdWorkflow); err != nil {
 		return nil, err
 	}
 
+	return createdWorkflow, nil
+}
+
+// submitWorkflowExecution creates the argo workflow wf and records workflowExecution for it in the database,
+// or queues it if the concurrency limits of the namespace are reached. wf is created as is, so it must already be prepared to run.
+// workflowExecution needs the version of its workflow template, and its parameters and labels. The rest is set from the created or queued workflow.
+func (c *Client) submitWorkflowExecution(namespace string, workflowTemplateID uint64, wf *wfv1.Workflow, workflowExecution *WorkflowExecution) error {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockWorkflowExecutionQueue(tx, namespace); err != nil {
+		return err
+	}
+
+	mustQueue, err := c.mustQueueWorkflowExecution(namespace, workflowTemplateID)
+	if err != nil {
+		return err
+	}
+	if mustQueue {
+		if err := queueWorkflowExecution(tx, namespace, workflowExecution, wf); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	createdArgoWorkflow, err := c.ArgoprojV1alpha1().Workflows(namespace).Create(wf)
+	if err != nil {
+		return err
+	}
+
+	workflowExecution.Name = createdArgoWorkflow.Name
+	workflowExecution.CreatedAt = createdArgoWorkflow.CreationTimestamp.UTC()
+	workflowExecution.Phase = wfv1.NodePending
+	workflowExecution.ArgoWorkflow = createdArgoWorkflow
+
+	if err = workflowExecution.GenerateUID(createdArgoWorkflow.Name); err != nil {
+		return err
+	}
+
 	//Create an entry for workflow_executions statistic
 	//CURL code will hit the API endpoint that will update the db row
-	if err := c.createWorkflowExecutionDB(namespace, createdWorkflow); err != nil {
-		return nil, err
+	if err := insertWorkflowExecution(tx, namespace, workflowExecution); err != nil {
+		return err
 	}
 
-	return
+	return tx.Commit()
 }
 
 func (c *Client) injectAccessForSidecars(namespace string, wf *wfv1.Workflow) ([]wfv1.Template, error) {
 	var newTemplateOrder []wfv1.Template
 	taskSysSendStatusName := "sys-send-status"
 	taskSysSendExitStats := "sys-send-exit-stats"
+	sysSendStatusFound := false
+	for _, t := range wf.Spec.Templates {
+		if t.Name == taskSysSendStatusName {
+			sysSendStatusFound = true
+			break
+		}
+	}
 	for tIdx, t := range wf.Spec.Templates {
 		//Inject services, virtual routes
 		for si, s := range t.Sidecars {
@@ -711,11 +780,15 @@ func (c *Client) injectAccessForSidecars(namespace string, wf *wfv1.Workflow) ([
 							wf.Spec.Templates[i2].DAG.Tasks[0].Dependencies = append(wf.Spec.Templates[i2].DAG.Tasks[0].Dependencies, virtualServiceAddTaskName)
 						}
 
+						var serviceAddDependencies []string
+						if sysSendStatusFound {
+							serviceAddDependencies = []string{taskSysSendStatusName}
+						}
 						wf.Spec.Templates[i2].DAG.Tasks = append(tasks, []wfv1.DAGTask{
 							{
 								Name:         serviceAddTaskName,
 								Template:     serviceTemplateNameAdd,
-								Dependencies: []string{taskSysSendStatusName},
+								Dependencies: serviceAddDependencies,
 							},
 							{
 								Name:         virtualServiceAddTaskName,
@@ -841,6 +914,14 @@ func (c *Client) ValidateWorkflowExecution(namespace string, manifest []byte) (e
 // If workflow.Name is set, it is used instead of a generated name.
 // If there is a parameter named "workflow-execution-name" in workflow.Parameters, it is set as the name.
 func (c *Client) CreateWorkflowExecution(namespace string, workflow *WorkflowExecution, workflowTemplate *WorkflowTemplate) (*WorkflowExecution, error) {
+	declaredParameters, err := ParseParametersFromManifest([]byte(workflowTemplate.Manifest))
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if err := ValidateParameterValues(declaredParameters, workflow.Parameters); err != nil {
+		return nil, err
+	}
+
 	opts := &WorkflowExecutionOptions{
 		Labels:     make(map[string]string),
 		Parameters: workflow.Parameters,
@@ -887,6 +968,7 @@ func (c *Client) CreateWorkflowExecution(namespace string, workflow *WorkflowExe
 	workflow.Name = createdWorkflow.Name
 	workflow.CreatedAt = createdWorkflow.CreatedAt.UTC()
 	workflow.UID = createdWorkflow.UID
+	workflow.Phase = createdWorkflow.Phase
 	workflow.WorkflowTemplate = workflowTemplate
 
 	return workflow, nil
@@ -914,15 +996,17 @@ func (c *Client) CloneWorkflowExecution(namespace, uid string) (*WorkflowExecuti
 	return c.CreateWorkflowExecution(namespace, workflowExecution, workflowTemplate)
 }
 
-// createWorkflowExecutionDB inserts a workflow execution into the database.
+// insertWorkflowExecution inserts a workflow execution into the database using runner.
 // Required fields
 // * name
 // * createdAt // we sync the argo created at with the db
 // * parameters, if any
 // * WorkflowTemplate.WorkflowTemplateVersionID
+// * retriedFromID, if it is a retry of another workflow execution
 //
+// The phase is Pending, unless workflowExecution.Phase is set.
 // After success, the passed in WorkflowExecution will have it's ID set to the new db record.
-func (c *Client) createWorkflowExecutionDB(namespace string, workflowExecution *WorkflowExecution) (err error) {
+func insertWorkflowExecution(runner sq.BaseRunner, namespace string, workflowExecution *WorkflowExecution) (err error) {
 	parametersJSON, err := json.Marshal(workflowExecution.Parameters)
 	if err != nil {
 		return err
@@ -932,21 +1016,31 @@ func (c *Client) createWorkflowExecutionDB(namespace string, workflowExecution *
 		return err
 	}
 
+	phase := workflowExecution.Phase
+	if phase == "" {
+		phase = wfv1.NodePending
+	}
+
+	values := sq.Eq{
+		"UID":                          workflowExecution.UID,
+		"workflow_template_version_id": workflowExecution.WorkflowTemplate.WorkflowTemplateVersionID,
+		"name":                         workflowExecution.Name,
+		"namespace":                    namespace,
+		"created_at":                   workflowExecution.CreatedAt.UTC(),
+		"phase":                        phase,
+		"parameters":                   string(parametersJSON),
+		"is_archived":                  false,
+		"labels":                       workflowExecution.Labels,
+		"metrics":                      workflowExecution.Metrics,
+	}
+	if workflowExecution.retriedFromID != 0 {
+		values["retried_from_id"] = workflowExecution.retriedFromID
+	}
+
 	err = sb.Insert("workflow_executions").
-		SetMap(sq.Eq{
-			"UID":                          workflowExecution.UID,
-			"workflow_template_version_id": workflowExecution.WorkflowTemplate.WorkflowTemplateVersionID,
-			"name":                         workflowExecution.Name,
-			"namespace":                    namespace,
-			"created_at":                   workflowExecution.CreatedAt.UTC(),
-			"phase":                        wfv1.NodePending,
-			"parameters":                   string(parametersJSON),
-			"is_archived":                  false,
-			"labels":                       workflowExecution.Labels,
-			"metrics":                      workflowExecution.Metrics,
-		}).
+		SetMap(values).
 		Suffix("RETURNING id").
-		RunWith(c.DB).
+		RunWith(runner).
 		QueryRow().
 		Scan(&workflowExecution.ID)
 
@@ -1042,14 +1136,23 @@ func (c *Client) GetWorkflowExecution(namespace, uid string) (workflow *Workflow
 		return nil, err
 	}
 
-	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
-	if err != nil {
-		log.WithFields(log.Fields{
-			"Namespace": namespace,
-			"UID":       uid,
-			"Error":     err.Error(),
-		}).Error("Workflow not found.")
-		return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
+	// Queued workflow executions don't have an argo workflow yet
+	var wf *wfv1.Workflow
+	if workflow.Phase == WorkflowExecutionPhaseQueued {
+		if wf, err = c.getQueuedArgoWorkflow(workflow.ID); err != nil {
+			return nil, err
+		}
+	}
+	if wf == nil {
+		wf, err = c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"UID":       uid,
+				"Error":     err.Error(),
+			}).Error("Workflow not found.")
+			return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
+		}
 	}
 
 	uidLabel := wf.ObjectMeta.Labels[workflowTemplateUIDLabelKey]
@@ -1105,6 +1208,15 @@ func (c *Client) ListWorkflowExecutions(namespace, workflowTemplateUID, workflow
 					nullSort = "NULLS LAST" // default in postgres, but let's be explicit
 				}
 				sb = sb.OrderBy(fmt.Sprintf("we.%v %v %v", columnName, order.Direction, nullSort))
+				continue
+			}
+
+			orderBys, ok, err := workflowExecutionJSONBOrderBy(order.Property, order.Direction)
+			if err != nil {
+				return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+			}
+			if ok {
+				sb = sb.OrderBy(orderBys...)
 			}
 		}
 	} else {
@@ -1270,6 +1382,209 @@ func (c *Client) WatchWorkflowExecution(namespace, uid string) (<-chan *Workflow
 	return workflowWatcher, nil
 }
 
+// workflowExecutionLabelSelector returns a kubernetes label selector for argo workflows that match the filter.
+// Only workflows created from a workflow template are selected.
+func workflowExecutionLabelSelector(filter *WorkflowExecutionFilter) string {
+	selectors := []string{workflowTemplateUIDLabelKey}
+	if filter != nil {
+		for _, l := range filter.Labels {
+			selectors = append(selectors, label.TagPrefix+l.Key+"="+l.Value)
+		}
+	}
+
+	return strings.Join(selectors, ",")
+}
+
+// workflowExecutionFromArgoWorkflow creates a WorkflowExecution from the status of an argo workflow.
+// Labels are the user labels of the workflow, without the tag prefix.
+func workflowExecutionFromArgoWorkflow(wf *wfv1.Workflow) (*WorkflowExecution, error) {
+	manifest, err := json.Marshal(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowExecution := &WorkflowExecution{
+		CreatedAt:    wf.CreationTimestamp.UTC(),
+		UID:          wf.Name,
+		Name:         wf.Name,
+		Namespace:    wf.Namespace,
+		Phase:        wf.Status.Phase,
+		Manifest:     string(manifest),
+		Labels:       label.RemovePrefix(label.TagPrefix, label.FilterByPrefix(label.TagPrefix, wf.Labels)),
+		ArgoWorkflow: wf,
+	}
+
+	if !wf.Status.StartedAt.IsZero() {
+		workflowExecution.StartedAt = ptr.Time(wf.Status.StartedAt.UTC())
+	}
+	if !wf.Status.FinishedAt.IsZero() {
+		workflowExecution.FinishedAt = ptr.Time(wf.Status.FinishedAt.UTC())
+	}
+
+	return workflowExecution, nil
+}
+
+// workflowExecutionWatchError returns the error a watch of workflow executions stops with because of status.
+// An expired resource version is OutOfRange, anything else is Aborted.
+func workflowExecutionWatchError(status *metav1.Status) error {
+	code := codes.Aborted
+	if status.Code == http.StatusGone || status.Reason == metav1.StatusReasonExpired || status.Reason == metav1.StatusReasonGone {
+		code = codes.OutOfRange
+	}
+
+	return util.NewUserError(code, status.Message)
+}
+
+// WatchWorkflowExecutions watches all of the workflow executions in a namespace that match the filter.
+// An event is sent when a workflow execution is created, when its phase changes, and when it is deleted.
+//
+// If resourceVersion is empty, the watch starts from the current state of the namespace. Otherwise, the watch
+// resumes from resourceVersion, so a client can reconnect using the ResourceVersion of the last event it received.
+// The channel is closed when stopCh is closed, or after an event with an Error when the watch stops:
+// the error is OutOfRange when the resourceVersion is too old to resume from, so the client has to start over
+// without it, and Aborted when the watch can not be restarted.
+func (c *Client) WatchWorkflowExecutions(namespace string, filter *WorkflowExecutionFilter, resourceVersion string, stopCh <-chan struct{}) (<-chan *WorkflowExecutionEvent, error) {
+	listOptions := metav1.ListOptions{
+		LabelSelector: workflowExecutionLabelSelector(filter),
+	}
+
+	// Phases are tracked so we only send MODIFIED events when the phase has changed.
+	phases := make(map[string]wfv1.NodePhase)
+	if resourceVersion == "" {
+		wfs, err := c.ArgoprojV1alpha1().Workflows(namespace).List(listOptions)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"Error":     err.Error(),
+			}).Error("List Workflows error.")
+			return nil, util.NewUserError(codes.Unknown, "Error with watching workflows.")
+		}
+
+		for _, wf := range wfs.Items {
+			phases[wf.Name] = wf.Status.Phase
+		}
+		resourceVersion = wfs.ResourceVersion
+	}
+
+	listOptions.ResourceVersion = resourceVersion
+	watcher, err := c.ArgoprojV1alpha1().Workflows(namespace).Watch(listOptions)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace":       namespace,
+			"ResourceVersion": resourceVersion,
+			"Error":           err.Error(),
+		}).Error("Watch Workflows error.")
+		return nil, util.NewUserError(codes.Unknown, "Error with watching workflows.")
+	}
+
+	eventWatcher := make(chan *WorkflowExecutionEvent)
+	go func() {
+		defer close(eventWatcher)
+		defer func() {
+			watcher.Stop()
+		}()
+
+		for {
+			var next watch.Event
+			var ok bool
+			select {
+			case next, ok = <-watcher.ResultChan():
+			case <-stopCh:
+				return
+			}
+
+			// The api server closes watches periodically. Resume from the last resource version we saw.
+			if !ok {
+				newWatcher, err := c.ArgoprojV1alpha1().Workflows(namespace).Watch(listOptions)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"Namespace":       namespace,
+						"ResourceVersion": listOptions.ResourceVersion,
+						"Error":           err.Error(),
+					}).Error("Watch Workflows error.")
+
+					status := metav1.Status{Message: err.Error()}
+					if apiStatus, isAPIStatus := err.(apierrors.APIStatus); isAPIStatus {
+						status = apiStatus.Status()
+					}
+					select {
+					case eventWatcher <- &WorkflowExecutionEvent{Error: workflowExecutionWatchError(&status)}:
+					case <-stopCh:
+					}
+					return
+				}
+				watcher.Stop()
+				watcher = newWatcher
+				continue
+			}
+
+			if status, isStatus := next.Object.(*metav1.Status); isStatus {
+				log.WithFields(log.Fields{
+					"Namespace":       namespace,
+					"ResourceVersion": listOptions.ResourceVersion,
+					"Message":         status.Message,
+				}).Error("Watch Workflows stopped.")
+
+				select {
+				case eventWatcher <- &WorkflowExecutionEvent{Error: workflowExecutionWatchError(status)}:
+				case <-stopCh:
+				}
+				return
+			}
+
+			wf, isWorkflow := next.Object.(*wfv1.Workflow)
+			if !isWorkflow || wf == nil {
+				continue
+			}
+
+			listOptions.ResourceVersion = wf.ResourceVersion
+
+			var eventType WorkflowExecutionEventType
+			switch next.Type {
+			case watch.Added:
+				eventType = WorkflowExecutionEventCreated
+			case watch.Modified:
+				if phase, seen := phases[wf.Name]; seen && phase == wf.Status.Phase {
+					continue
+				}
+				eventType = WorkflowExecutionEventPhaseChanged
+			case watch.Deleted:
+				eventType = WorkflowExecutionEventDeleted
+			default:
+				continue
+			}
+
+			if eventType == WorkflowExecutionEventDeleted {
+				delete(phases, wf.Name)
+			} else {
+				phases[wf.Name] = wf.Status.Phase
+			}
+
+			workflowExecution, err := workflowExecutionFromArgoWorkflow(wf)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"Namespace": namespace,
+					"UID":       wf.Name,
+					"Error":     err.Error(),
+				}).Error("Error with trying to JSON Marshal workflow.")
+				continue
+			}
+
+			select {
+			case eventWatcher <- &WorkflowExecutionEvent{
+				Type:              eventType,
+				WorkflowExecution: workflowExecution,
+				ResourceVersion:   wf.ResourceVersion,
+			}:
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	return eventWatcher, nil
+}
+
 func (c *Client) GetWorkflowExecutionLogs(namespace, uid, podName, containerName string) (<-chan []*LogEntry, error) {
 	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
 	if err != nil {
@@ -1285,9 +1600,8 @@ func (c *Client) GetWorkflowExecutionLogs(namespace, uid, podName, containerName
 
 	var (
 		stream    io.ReadCloser
-		s3Client  *s3.Client
-		gcsClient *gcs.Client
 		config    *NamespaceConfig
+		store     ArtifactStore
 		endOffset int
 	)
 
@@ -1304,57 +1618,29 @@ func (c *Client) GetWorkflowExecutionLogs(namespace, uid, podName, containerName
 			return nil, util.NewUserError(codes.NotFound, "Can't get configuration.")
 		}
 
-		switch {
-		case config.ArtifactRepository.S3 != nil:
-			{
-				s3Client, err = c.GetS3Client(namespace, config.ArtifactRepository.S3)
-				if err != nil {
-					log.WithFields(log.Fields{
-						"Namespace":     namespace,
-						"UID":           uid,
-						"PodName":       podName,
-						"ContainerName": containerName,
-						"Error":         err.Error(),
-					}).Error("Can't connect to S3 storage.")
-					return nil, util.NewUserError(codes.NotFound, "Can't connect to S3 storage.")
-				}
+		store, err = c.getArtifactStore(namespace, &config.ArtifactRepository)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace":     namespace,
+				"UID":           uid,
+				"PodName":       podName,
+				"ContainerName": containerName,
+				"Error":         err.Error(),
+			}).Error("Can't connect to artifact storage.")
+			return nil, util.NewUserError(codes.NotFound, "Can't connect to artifact storage.")
+		}
 
-				opts := s3.GetObjectOptions{}
-				endOffset, err = strconv.Atoi(readEndOffset)
-				if err != nil {
-					return nil, util.NewUserError(codes.InvalidArgument, "Invalid range.")
-				}
-				err = opts.SetRange(0, int64(endOffset))
-				if err != nil {
-					log.WithFields(log.Fields{
-						"Namespace":     namespace,
-						"UID":           uid,
-						"PodName":       podName,
-						"ContainerName": containerName,
-						"Error":         err.Error(),
-					}).Error("Can't set range.")
-					return nil, util.NewUserError(codes.NotFound, "Can't connect to S3 storage.")
-				}
+		endOffset, err = strconv.Atoi(readEndOffset)
+		if err != nil {
+			return nil, util.NewUserError(codes.InvalidArgument, "Invalid range.")
+		}
 
-				key := config.ArtifactRepository.S3.FormatKey(namespace, uid, podName) + "/" + containerName + ".log"
-				stream, err = s3Client.GetObject(config.ArtifactRepository.S3.Bucket, key, opts)
-			}
-		case config.ArtifactRepository.GCS != nil:
-			{
-				gcsClient, err = c.GetGCSClient(namespace, config.ArtifactRepository.GCS)
-				if err != nil {
-					log.WithFields(log.Fields{
-						"Namespace":     namespace,
-						"UID":           uid,
-						"PodName":       podName,
-						"ContainerName": containerName,
-						"Error":         err.Error(),
-					}).Error("Can't connect to GCS storage.")
-					return nil, util.NewUserError(codes.NotFound, "Can't connect to GCS storage.")
-				}
-				key := config.ArtifactRepository.GCS.FormatKey(namespace, uid, podName) + "/" + containerName + ".log"
-				stream, err = gcsClient.GetObject(config.ArtifactRepository.GCS.Bucket, key)
-			}
+		key := config.ArtifactRepository.FormatKey(namespace, uid, podName) + "/" + containerName + ".log"
+		if endOffset < 0 {
+			// Read the last -endOffset bytes
+			stream, err = store.Get(key, int64(endOffset), 0)
+		} else {
+			stream, err = store.Get(key, 0, int64(endOffset)+1)
 		}
 	} else {
 		stream, err = c.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
@@ -1442,14 +1728,7 @@ func (c *Client) GetWorkflowExecutionMetrics(namespace, uid, podName string) (me
 		return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
 	}
 
-	var (
-		stream    io.ReadCloser
-		s3Client  *s3.Client
-		gcsClient *gcs.Client
-		config    *NamespaceConfig
-	)
-
-	config, err = c.GetNamespaceConfig(namespace)
+	config, err := c.GetNamespaceConfig(namespace)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Namespace": namespace,
@@ -1460,59 +1739,29 @@ func (c *Client) GetWorkflowExecutionMetrics(namespace, uid, podName string) (me
 		return nil, util.NewUserError(codes.NotFound, "Can't get configuration.")
 	}
 
-	switch {
-	case config.ArtifactRepository.S3 != nil:
-		{
-			s3Client, err = c.GetS3Client(namespace, config.ArtifactRepository.S3)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"PodName":   podName,
-					"Error":     err.Error(),
-				}).Error("Can't connect to S3 storage.")
-				return nil, util.NewUserError(codes.NotFound, "Can't connect to S3 storage.")
-			}
-
-			opts := s3.GetObjectOptions{}
+	store, err := c.getArtifactStore(namespace, &config.ArtifactRepository)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"PodName":   podName,
+			"Error":     err.Error(),
+		}).Error("Can't connect to artifact storage.")
+		return nil, util.NewUserError(codes.NotFound, "Can't connect to artifact storage.")
+	}
 
-			key := config.ArtifactRepository.S3.FormatKey(namespace, uid, podName) + "/sys-metrics.json"
-			stream, err = s3Client.GetObject(config.ArtifactRepository.S3.Bucket, key, opts)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"PodName":   podName,
-					"Error":     err.Error(),
-				}).Error("Metrics do not exist.")
-				return nil, util.NewUserError(codes.NotFound, "Metrics do not exist.")
-			}
-		}
-	case config.ArtifactRepository.GCS != nil:
-		{
-			gcsClient, err = c.GetGCSClient(namespace, config.ArtifactRepository.GCS)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"PodName":   podName,
-					"Error":     err.Error(),
-				}).Error("Can't connect to GCS storage.")
-				return nil, util.NewUserError(codes.NotFound, "Can't connect to GCS storage.")
-			}
-			key := config.ArtifactRepository.GCS.FormatKey(namespace, uid, podName) + "/sys-metrics.json"
-			stream, err = gcsClient.GetObject(config.ArtifactRepository.GCS.Bucket, key)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"PodName":   podName,
-					"Error":     err.Error(),
-				}).Error("Metrics do not exist.")
-				return nil, util.NewUserError(codes.NotFound, "Metrics do not exist.")
-			}
-		}
+	key := config.ArtifactRepository.FormatKey(namespace, uid, podName) + "/sys-metrics.json"
+	stream, err := store.Get(key, 0, 0)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"PodName":   podName,
+			"Error":     err.Error(),
+		}).Error("Metrics do not exist.")
+		return nil, util.NewUserError(codes.NotFound, "Metrics do not exist.")
 	}
+	defer stream.Close()
 
 	content, err := ioutil.ReadAll(stream)
 	if err != nil {
@@ -1541,61 +1790,352 @@ func (c *Client) GetWorkflowExecutionMetrics(namespace, uid, podName string) (me
 	return
 }
 
-func (c *Client) RetryWorkflowExecution(namespace, uid string) (workflow *WorkflowExecution, err error) {
-	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
-	if err != nil {
-		return
+// getNodeFields returns the fields of a node that can be used in a node field selector
+func getNodeFields(node *wfv1.NodeStatus) fields.Set {
+	nodeFields := fields.Set{
+		"displayName":  node.DisplayName,
+		"templateName": node.TemplateName,
+	}
+	if node.Inputs != nil {
+		for _, param := range node.Inputs.Parameters {
+			if param.Value != nil {
+				nodeFields[fmt.Sprintf("inputs.parameters.%s.value", param.Name)] = *param.Value
+			}
+		}
 	}
 
-	wf, err = argoutil.RetryWorkflow(c, c.ArgoprojV1alpha1().Workflows(namespace), wf)
-
-	workflow = typeWorkflow(wf)
-
-	return
+	return nodeFields
 }
 
-func (c *Client) ResubmitWorkflowExecution(namespace, uid string) (workflow *WorkflowExecution, err error) {
-	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
-	if err != nil {
-		return
+// RetryWorkflowExecution creates a new workflow execution from a failed one.
+// Nodes that succeeded are carried over and failed nodes are run again. If a node field selector is provided,
+// only the failed nodes that match it are run again, the rest keep their failed status.
+// The new workflow execution is recorded in the database with a reference to the one it was retried from.
+func (c *Client) RetryWorkflowExecution(namespace, uid string, opts *RetryWorkflowExecutionOptions) (workflow *WorkflowExecution, err error) {
+	if opts == nil {
+		opts = &RetryWorkflowExecutionOptions{}
 	}
 
-	wf, err = argoutil.FormulateResubmitWorkflow(wf, false)
+	original, parameters, err := c.getWorkflowExecutionToSubmitAgain(namespace, uid)
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	wf, err = argoutil.SubmitWorkflow(c.ArgoprojV1alpha1().Workflows(namespace), c, namespace, wf, &argoutil.SubmitOpts{})
+	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
 	if err != nil {
-		return
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Workflow not found.")
+		return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
 	}
 
-	workflow = typeWorkflow(wf)
+	if wf.Status.Phase != wfv1.NodeFailed && wf.Status.Phase != wfv1.NodeError {
+		return nil, util.NewUserError(codes.FailedPrecondition, "Only failed workflow executions can be retried.")
+	}
+
+	newWf, err := argoutil.FormulateResubmitWorkflow(wf, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.NodeFieldSelector != "" {
+		selector, err := fields.ParseSelector(opts.NodeFieldSelector)
+		if err != nil {
+			return nil, util.NewUserError(codes.InvalidArgument, "Invalid node field selector.")
+		}
+
+		keptFailedNodes := false
+		reWorkflowName := regexp.MustCompile("^" + regexp.QuoteMeta(wf.Name))
+		for _, node := range wf.Status.Nodes {
+			if node.Type != wfv1.NodeTypePod || (node.Phase != wfv1.NodeFailed && node.Phase != wfv1.NodeError) {
+				continue
+			}
+			if selector.Matches(getNodeFields(&node)) {
+				continue
+			}
+
+			// Keep the status of failed nodes that were not selected, so they are not run again
+			newNodeID := newWf.NodeID(reWorkflowName.ReplaceAllString(node.Name, newWf.Name))
+			newNode, ok := newWf.Status.Nodes[newNodeID]
+			if !ok {
+				continue
+			}
+			newNode.Phase = node.Phase
+			newNode.Message = node.Message
+			newNode.StartedAt = node.StartedAt
+			newNode.FinishedAt = node.FinishedAt
+			newWf.Status.Nodes[newNodeID] = newNode
+			keptFailedNodes = true
+		}
+
+		// A DAG stops scheduling tasks once one has failed, so the selected nodes would not run next to the
+		// failed nodes that were kept. The retried workflow still ends as failed because of them.
+		if keptFailedNodes {
+			for i := range newWf.Spec.Templates {
+				if dag := newWf.Spec.Templates[i].DAG; dag != nil {
+					dag.FailFast = ptr.Bool(false)
+				}
+			}
+		}
+	}
+
+	for _, override := range opts.Parameters {
+		found := false
+		for i := range newWf.Spec.Arguments.Parameters {
+			if newWf.Spec.Arguments.Parameters[i].Name == override.Name {
+				newWf.Spec.Arguments.Parameters[i].Value = override.Value
+				found = true
+			}
+		}
+		if !found {
+			return nil, util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Parameter '%v' does not exist.", override.Name))
+		}
+
+		for i := range parameters {
+			if parameters[i].Name == override.Name {
+				parameters[i].Value = override.Value
+			}
+		}
+	}
+
+	if len(opts.Parameters) > 0 {
+		manifest := ""
+		err = sb.Select("manifest").
+			From("workflow_template_versions").
+			Where(sq.Eq{"id": original.WorkflowTemplate.WorkflowTemplateVersionID}).
+			RunWith(c.DB).
+			QueryRow().
+			Scan(&manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		declaredParameters, err := ParseParametersFromManifest([]byte(manifest))
+		if err != nil {
+			return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+		}
+		if err := ValidateParameterValues(declaredParameters, parameters); err != nil {
+			return nil, err
+		}
+	}
+
+	// The retry goes through the queue like any other execution. Only the reference to the original one is added.
+	workflow = &WorkflowExecution{
+		Namespace: namespace,
+		WorkflowTemplate: &WorkflowTemplate{
+			WorkflowTemplateVersionID: original.WorkflowTemplate.WorkflowTemplateVersionID,
+		},
+		Parameters:    parameters,
+		Labels:        original.Labels,
+		retriedFromID: original.ID,
+	}
+	if err := c.submitWorkflowExecution(namespace, original.WorkflowTemplate.ID, newWf, workflow); err != nil {
+		return nil, err
+	}
+
+	manifest, err := json.Marshal(workflow.ArgoWorkflow)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Manifest = string(manifest)
 
 	return
 }
 
-func (c *Client) ResumeWorkflowExecution(namespace, uid string) (workflow *WorkflowExecution, err error) {
-	err = argoutil.ResumeWorkflow(c.ArgoprojV1alpha1().Workflows(namespace), uid, "")
+// ResubmitWorkflowExecution creates a new workflow execution that runs the workflow of an existing one again from the start,
+// with the same parameters and labels. It goes through the queue like any other execution.
+func (c *Client) ResubmitWorkflowExecution(namespace, uid string) (workflow *WorkflowExecution, err error) {
+	original, parameters, err := c.getWorkflowExecutionToSubmitAgain(namespace, uid)
 	if err != nil {
-		return
+		return nil, err
+	}
+
+	wf, err := c.getArgoWorkflow(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err = argoutil.FormulateResubmitWorkflow(wf, false)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow = &WorkflowExecution{
+		Namespace: namespace,
+		WorkflowTemplate: &WorkflowTemplate{
+			WorkflowTemplateVersionID: original.WorkflowTemplate.WorkflowTemplateVersionID,
+		},
+		Parameters: parameters,
+		Labels:     original.Labels,
+	}
+	if err := c.submitWorkflowExecution(namespace, original.WorkflowTemplate.ID, wf, workflow); err != nil {
+		return nil, err
 	}
 
+	manifest, err := json.Marshal(workflow.ArgoWorkflow)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Manifest = string(manifest)
+
+	return
+}
+
+// getWorkflowExecutionToSubmitAgain returns what is needed to submit the workflow execution identified by (namespace, uid) again:
+// its id, the ids of its workflow template and version, its labels and its parameters
+func (c *Client) getWorkflowExecutionToSubmitAgain(namespace, uid string) (workflowExecution *WorkflowExecution, parameters []Parameter, err error) {
+	workflowExecution = &WorkflowExecution{
+		WorkflowTemplate: &WorkflowTemplate{},
+	}
+	err = sb.Select("we.id", "we.workflow_template_version_id", "wtv.workflow_template_id", "we.parameters", "we.labels").
+		From("workflow_executions we").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Where(sq.Eq{
+			"we.namespace":   namespace,
+			"we.uid":         uid,
+			"we.is_archived": false,
+		}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&workflowExecution.ID, &workflowExecution.WorkflowTemplate.WorkflowTemplateVersionID, &workflowExecution.WorkflowTemplate.ID, &workflowExecution.ParametersBytes, &workflowExecution.Labels)
+	if err == sql.ErrNoRows {
+		return nil, nil, util.NewUserError(codes.NotFound, "Workflow execution not found.")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parameters, err = workflowExecution.LoadParametersFromBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return
+}
+
+// getArgoWorkflow returns the argo workflow identified by (namespace, uid), or a user error if it does not exist
+func (c *Client) getArgoWorkflow(namespace, uid string) (*wfv1.Workflow, error) {
 	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Workflow not found.")
+		return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
+	}
+
+	return wf, nil
+}
+
+// ResumeWorkflowExecution resumes a suspended workflow execution and updates its phase in the database.
+// If nodeFieldSelector is provided, only the waiting suspend nodes that match it are resumed. See getNodeFields for the supported fields.
+func (c *Client) ResumeWorkflowExecution(namespace, uid, nodeFieldSelector string) (workflow *WorkflowExecution, err error) {
+	if _, err = c.getArgoWorkflow(namespace, uid); err != nil {
+		return
+	}
+
+	if nodeFieldSelector != "" {
+		if _, err := fields.ParseSelector(nodeFieldSelector); err != nil {
+			return nil, util.NewUserError(codes.InvalidArgument, "Invalid node field selector.")
+		}
+	}
+
+	err = argoutil.ResumeWorkflow(c.ArgoprojV1alpha1().Workflows(namespace), uid, nodeFieldSelector)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Unable to resume workflow.")
+		return nil, util.NewUserError(codes.FailedPrecondition, "Unable to resume workflow.")
+	}
+
+	wf, err := c.getArgoWorkflow(namespace, uid)
+	if err != nil {
+		return
+	}
+
+	if err = c.SyncWorkflowExecutionStatus(wf); err != nil {
+		return
+	}
 
 	workflow = typeWorkflow(wf)
+	workflow.UID = wf.Name
+	workflow.Namespace = namespace
+	workflow.Phase = getWorkflowExecutionPhase(wf)
 
 	return
 }
 
+// SuspendWorkflowExecution suspends a running workflow execution and marks it as suspended in the database.
 func (c *Client) SuspendWorkflowExecution(namespace, uid string) (err error) {
+	if _, err = c.getArgoWorkflow(namespace, uid); err != nil {
+		return
+	}
+
 	err = argoutil.SuspendWorkflow(c.ArgoprojV1alpha1().Workflows(namespace), uid)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Unable to suspend workflow.")
+		return util.NewUserError(codes.FailedPrecondition, "Unable to suspend workflow.")
+	}
+
+	_, err = sb.Update("workflow_executions").
+		Set("phase", WorkflowExecutionPhaseSuspended).
+		Where(sq.And{
+			sq.Eq{
+				"namespace": namespace,
+				"uid":       uid,
+			},
+			sq.NotEq{"phase": "Terminated"},
+		}).
+		RunWith(c.DB).
+		Exec()
+
+	return
+}
+
+// ListWorkflowExecutionSuspendNodes returns the suspend nodes of the workflow execution that are waiting to be resumed.
+func (c *Client) ListWorkflowExecutionSuspendNodes(namespace, uid string) (nodes []*WorkflowExecutionNode, err error) {
+	wf, err := c.getArgoWorkflow(namespace, uid)
+	if err != nil {
+		return
+	}
+
+	nodes = make([]*WorkflowExecutionNode, 0)
+	for _, status := range wf.Status.Nodes {
+		if !status.IsActiveSuspendNode() {
+			continue
+		}
+
+		node, err := workflowExecutionNodeFromStatus(0, &status)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeID < nodes[j].NodeID
+	})
 
 	return
 }
 
 // TerminateWorkflowExecution marks a workflows execution as terminated in DB and terminates the argo resource.
+// Queued workflow executions are removed from the queue.
 func (c *Client) TerminateWorkflowExecution(namespace, uid string) (err error) {
+	cancelled, err := c.cancelQueuedWorkflowExecution(namespace, uid)
+	if err != nil || cancelled {
+		return err
+	}
+
 	_, err = sb.Update("workflow_executions").
 		Set("phase", "Terminated").
 		Set("started_at", time.Time.UTC(time.Now())).
@@ -1616,56 +2156,16 @@ func (c *Client) TerminateWorkflowExecution(namespace, uid string) (err error) {
 }
 
 func (c *Client) GetArtifact(namespace, uid, key string) (data []byte, err error) {
-	config, err := c.GetNamespaceConfig(namespace)
+	store, err := c.GetArtifactStore(namespace)
 	if err != nil {
 		return
 	}
-	var (
-		stream io.ReadCloser
-	)
-	switch {
-	case config.ArtifactRepository.S3 != nil:
-		{
-			s3Client, err := c.GetS3Client(namespace, config.ArtifactRepository.S3)
-			if err != nil {
-				return nil, err
-			}
-
-			opts := s3.GetObjectOptions{}
-			stream, err = s3Client.GetObject(config.ArtifactRepository.S3.Bucket, key, opts)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"Key":       key,
-					"Error":     err.Error(),
-				}).Error("Artifact does not exist.")
-				return nil, err
-			}
-		}
-	case config.ArtifactRepository.GCS != nil:
-		{
-			gcsClient, err := c.GetGCSClient(namespace, config.ArtifactRepository.GCS)
 
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"Error":     err.Error(),
-				}).Error("Artifact does not exist.")
-				return nil, util.NewUserError(codes.NotFound, "Artifact does not exist.")
-			}
-			stream, err = gcsClient.GetObject(config.ArtifactRepository.GCS.Bucket, key)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Namespace": namespace,
-					"UID":       uid,
-					"Error":     err.Error(),
-				}).Error("Artifact does not exist.")
-				return nil, util.NewUserError(codes.NotFound, "Artifact does not exist.")
-			}
-		}
+	stream, err := store.Get(key, 0, 0)
+	if err != nil {
+		return nil, artifactStoreError(err, namespace, key, "Unable to read artifact.")
 	}
+	defer stream.Close()
 
 	data, err = ioutil.ReadAll(stream)
 	if err != nil {
@@ -1675,91 +2175,229 @@ func (c *Client) GetArtifact(namespace, uid, key string) (data []byte, err error
 	return
 }
 
-func (c *Client) ListFiles(namespace, key string) (files []*File, err error) {
-	config, err := c.GetNamespaceConfig(namespace)
+// StatArtifact returns the information of the artifact identified by key, without reading its content.
+func (c *Client) StatArtifact(namespace, key string) (file *File, err error) {
+	store, err := c.GetArtifactStore(namespace)
+	if err != nil {
+		return
+	}
+
+	file, err = store.Stat(key)
+	if err != nil {
+		return nil, artifactStoreError(err, namespace, key, "Unable to get artifact.")
+	}
+
+	return
+}
+
+// GetArtifactURL returns a pre-signed URL that can be used to download the artifact identified by key
+// without authenticating with the API. The URL expires after the artifactURLExpiry from the system config.
+func (c *Client) GetArtifactURL(namespace, key string) (artifactURL string, expiresAt time.Time, err error) {
+	store, err := c.GetArtifactStore(namespace)
+	if err != nil {
+		return
+	}
+
+	sysConfig, err := c.GetSystemConfig()
+	if err != nil {
+		return
+	}
+	expiry := sysConfig.ArtifactURLExpiry()
+	expiresAt = time.Now().Add(expiry).UTC()
+
+	artifactURL, err = store.Presign(key, expiry)
+	if err != nil {
+		return "", expiresAt, artifactStoreError(err, namespace, key, "Unable to create artifact URL.")
+	}
+
+	return
+}
+
+// GetArtifactUploadKey returns the key an uploaded file is stored under: {artifactUploadPrefix}/{path}/{name}.
+// If no path is provided, a unique one is generated so uploads with the same name don't overwrite each other.
+func (c *Client) GetArtifactUploadKey(path, name string) (key string, err error) {
+	sysConfig, err := c.GetSystemConfig()
+	if err != nil {
+		return
+	}
+
+	name = FilePathToName(name)
+	if name == "" || name == "." || name == ".." {
+		return "", util.NewUserError(codes.InvalidArgument, "Invalid file name.")
+	}
+
+	path = strings.Trim(filepath.ToSlash(filepath.Clean("/"+path)), "/")
+	if path == "" {
+		path = uuid.New().String()
+	}
+
+	return sysConfig.ArtifactUploadPrefix() + "/" + path + "/" + name, nil
+}
+
+// PutArtifact writes the content of reader into the artifact repository of the namespace under key
+// and returns the number of bytes written. size may be -1 if it is not known.
+// Large files are uploaded in parts, so they don't have to be held in memory.
+func (c *Client) PutArtifact(namespace, key string, reader io.Reader, size int64, contentType string) (written int64, err error) {
+	store, err := c.GetArtifactStore(namespace)
+	if err != nil {
+		return
+	}
+
+	written, err = store.Put(key, reader, size, contentType)
+	if err != nil {
+		return 0, artifactStoreError(err, namespace, key, "Unable to upload artifact.")
+	}
+
+	return
+}
+
+// GetArtifactRange returns a stream of the artifact identified by key, starting at offset and reading length bytes.
+// If length is 0, the artifact is read until the end. The caller is responsible for closing the stream.
+func (c *Client) GetArtifactRange(namespace, key string, offset, length int64) (stream io.ReadCloser, err error) {
+	store, err := c.GetArtifactStore(namespace)
 	if err != nil {
 		return
 	}
 
-	files = make([]*File, 0)
+	stream, err = store.Get(key, offset, length)
+	if err != nil {
+		return nil, artifactStoreError(err, namespace, key, "Unable to read artifact.")
+	}
+
+	return
+}
+
+// DeleteArtifact removes the artifact identified by key from the artifact repository of the namespace.
+func (c *Client) DeleteArtifact(namespace, key string) (err error) {
+	store, err := c.GetArtifactStore(namespace)
+	if err != nil {
+		return
+	}
+
+	if err = store.Delete(key); err != nil {
+		return artifactStoreError(err, namespace, key, "Unable to delete artifact.")
+	}
+
+	return
+}
+
+// ListFiles returns a page of the files and directories directly under the directory key of the artifact repository.
+// Pages in the default order, name ascending, are listed with the pagination of the artifact repository,
+// so large directories don't have to be loaded. Other orders need the whole directory, up to listFilesSortLimit files.
+func (c *Client) ListFiles(namespace, key string, opts *ListFilesOptions) (result *ListFilesResult, err error) {
+	store, err := c.GetArtifactStore(namespace)
+	if err != nil {
+		return
+	}
 
 	if len(key) > 0 {
 		if string(key[len(key)-1]) != "/" {
 			key += "/"
 		}
 	}
-	switch {
-	case config.ArtifactRepository.S3 != nil:
-		{
-			s3Client, err := c.GetS3Client(namespace, config.ArtifactRepository.S3)
-			if err != nil {
-				return nil, err
+
+	if opts == nil {
+		opts = &ListFilesOptions{}
+	}
+	property, desc, err := getFilesOrder(opts.Sort)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid sort, files can be sorted by name, size or lastModified.")
+	}
+	token, err := decodeListFilesToken(opts.ContinuationToken)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid continuation token.")
+	}
+	if _, ok := store.(ArtifactFolderSizer); opts.FolderSizes && !ok {
+		return nil, util.NewUserError(codes.Unimplemented, "Folder sizes are not supported by the artifact repository.")
+	}
+
+	result = &ListFilesResult{}
+	if opts.PageSize > 0 && property == "name" && !desc && token.Offset == 0 {
+		files, nextToken, err := store.List(key, ArtifactListOptions{
+			PageSize:          opts.PageSize,
+			ContinuationToken: token.Store,
+		})
+		if err != nil {
+			return nil, artifactStoreError(err, namespace, key, "Unable to list files.")
+		}
+		if opts.FolderSizes {
+			if err := setFolderSizes(store, files); err != nil {
+				return nil, artifactStoreError(err, namespace, key, "Unable to get folder sizes.")
 			}
+		}
+		SortFiles(files, property, desc)
 
-			doneCh := make(chan struct{})
-			defer close(doneCh)
-			for objInfo := range s3Client.ListObjects(config.ArtifactRepository.S3.Bucket, key, false, doneCh) {
-				if objInfo.Key == key {
-					continue
-				}
+		result.Files = files
+		result.NextContinuationToken = (&listFilesToken{Store: nextToken}).encode()
+		return result, nil
+	}
 
-				isDirectory := (objInfo.ETag == "" || strings.HasSuffix(objInfo.Key, "/")) && objInfo.Size == 0
+	files := make([]*File, 0)
+	nextToken := ""
+	for {
+		page, pageToken, err := store.List(key, ArtifactListOptions{ContinuationToken: nextToken})
+		if err != nil {
+			return nil, artifactStoreError(err, namespace, key, "Unable to list files.")
+		}
+		files = append(files, page...)
+		if len(files) > listFilesSortLimit {
+			return nil, util.NewUserError(codes.FailedPrecondition, "The directory has too many files to sort, sort by name instead.")
+		}
 
-				newFile := &File{
-					Path:         objInfo.Key,
-					Name:         FilePathToName(objInfo.Key),
-					Extension:    FilePathToExtension(objInfo.Key),
-					Size:         objInfo.Size,
-					LastModified: objInfo.LastModified,
-					ContentType:  objInfo.ContentType,
-					Directory:    isDirectory,
-				}
-				files = append(files, newFile)
-			}
+		if pageToken == "" {
+			break
 		}
-	case config.ArtifactRepository.GCS != nil:
-		{
-			ctx := context.Background()
-			gcsClient, err := c.GetGCSClient(namespace, config.ArtifactRepository.GCS)
-			if err != nil {
-				return nil, err
-			}
-			q := &storage.Query{
-				Delimiter: "",
-				Prefix:    key,
-				Versions:  false,
-			}
-			bucketFiles := gcsClient.Bucket(config.ArtifactRepository.GCS.Bucket).Objects(ctx, q)
+		nextToken = pageToken
+	}
 
-			for true {
-				file, err := bucketFiles.Next()
-				if err != nil {
-					if err.Error() == "no more items in iterator" {
-						break
-					}
-					return nil, err
-				}
-				if file.Name == key {
-					continue
-				}
-				isDirectory := (file.Etag == "" || strings.HasSuffix(file.Name, "/")) && file.Size == 0
-
-				newFile := &File{
-					Path:         file.Name,
-					Name:         FilePathToName(file.Name),
-					Extension:    FilePathToExtension(file.Name),
-					Size:         file.Size,
-					LastModified: file.Updated,
-					ContentType:  file.ContentType,
-					Directory:    isDirectory,
-				}
-				files = append(files, newFile)
-			}
+	if opts.FolderSizes {
+		if err := setFolderSizes(store, files); err != nil {
+			return nil, artifactStoreError(err, namespace, key, "Unable to get folder sizes.")
+		}
+	}
+	SortFiles(files, property, desc)
+
+	if opts.PageSize > 0 {
+		if token.Offset > len(files) {
+			token.Offset = len(files)
+		}
+		files = files[token.Offset:]
+
+		nextOffset := 0
+		if len(files) > opts.PageSize {
+			files = files[:opts.PageSize]
+			nextOffset = token.Offset + opts.PageSize
 		}
+		result.NextContinuationToken = (&listFilesToken{Offset: nextOffset}).encode()
 	}
+	result.Files = files
+
 	return
 }
 
+// setFolderSizes sets the size of the directories in files to the total size of their content.
+// The store has to implement ArtifactFolderSizer.
+func setFolderSizes(store ArtifactStore, files []*File) error {
+	sizer, ok := store.(ArtifactFolderSizer)
+	if !ok {
+		return util.NewUserError(codes.Unimplemented, "Folder sizes are not supported by the artifact repository.")
+	}
+
+	for _, file := range files {
+		if !file.Directory {
+			continue
+		}
+
+		size, err := sizer.FolderSize(file.Path)
+		if err != nil {
+			return err
+		}
+		file.Size = size
+	}
+
+	return nil
+}
+
 func filterOutCustomTypesFromManifest(manifest []byte) (result []byte, err error) {
 	data := make(map[string]interface{})
 	err = yaml.Unmarshal(manifest, &data)
@@ -1950,10 +2588,11 @@ func (c *Client) SetWorkflowTemplateLabels(namespace, uid, prefix string, keyVal
 func (c *Client) GetWorkflowExecutionStatisticsForNamespace(namespace string) (report *WorkflowExecutionStatisticReport, err error) {
 	statsSelect := `
 		MAX(we.created_at) last_executed,
-		COUNT(*) FILTER (WHERE finished_at IS NULL AND (phase = 'Running' OR phase = 'Pending')) running,
+		COUNT(*) FILTER (WHERE finished_at IS NULL AND phase IN ('Running', 'Pending', 'Suspended')) running,
 		COUNT(*) FILTER (WHERE finished_at IS NOT NULL AND phase = 'Succeeded') completed,
 		COUNT(*) FILTER (WHERE finished_at IS NOT NULL AND (phase = 'Failed' OR phase = 'Error')) failed,
 		COUNT(*) FILTER (WHERE phase = 'Terminated') terminated,
+		COUNT(*) FILTER (WHERE phase = 'Queued') queued,
 		COUNT(*) total`
 
 	query := sb.Select(statsSelect).
@@ -1993,10 +2632,11 @@ func (c *Client) GetWorkflowExecutionStatisticsForTemplates(workflowTemplates ..
 	statsSelect := `
 		workflow_template_id,
 		MAX(we.created_at) last_executed,
-		COUNT(*) FILTER (WHERE finished_at IS NULL AND (phase = 'Running' OR phase = 'Pending')) running,
+		COUNT(*) FILTER (WHERE finished_at IS NULL AND phase IN ('Running', 'Pending', 'Suspended')) running,
 		COUNT(*) FILTER (WHERE finished_at IS NOT NULL AND phase = 'Succeeded') completed,
 		COUNT(*) FILTER (WHERE finished_at IS NOT NULL AND (phase = 'Failed' OR phase = 'Error')) failed,
 		COUNT(*) FILTER (WHERE phase = 'Terminated') terminated,
+		COUNT(*) FILTER (WHERE phase = 'Queued') queued,
 		COUNT(*) total`
 
 	query, args, err := sb.Select(statsSelect).
@@ -2268,6 +2908,146 @@ func (c *Client) UpdateWorkflowExecutionStatus(namespace, uid string, status *Wo
 	return
 }
 
+// getWorkflowExecutionPhase returns the phase of the argo workflow, or WorkflowExecutionPhaseSuspended
+// if the workflow is running but suspended.
+func getWorkflowExecutionPhase(wf *wfv1.Workflow) wfv1.NodePhase {
+	if wf.Status.Phase == wfv1.NodeRunning && argoutil.IsWorkflowSuspended(wf) {
+		return WorkflowExecutionPhaseSuspended
+	}
+
+	return wf.Status.Phase
+}
+
+// SyncWorkflowExecutionStatus copies the phase, start and finish times of the argo workflow into its workflow_executions row
+// and stores the nodes of the workflow. Notifications are queued when the phase changes.
+// The phase of terminated executions is left untouched, as it is set by TerminateWorkflowExecution.
+func (c *Client) SyncWorkflowExecutionStatus(wf *wfv1.Workflow) (err error) {
+	if wf.Status.Phase == "" {
+		return nil
+	}
+
+	phase := getWorkflowExecutionPhase(wf)
+	fieldMap := sq.Eq{
+		"phase": phase,
+	}
+	if !wf.Status.StartedAt.IsZero() {
+		fieldMap["started_at"] = wf.Status.StartedAt.UTC()
+	}
+	if !wf.Status.FinishedAt.IsZero() {
+		fieldMap["finished_at"] = wf.Status.FinishedAt.UTC()
+	}
+
+	whereSynced := sq.And{
+		sq.Eq{
+			"namespace": wf.Namespace,
+			"uid":       wf.Name,
+		},
+		sq.NotEq{"phase": "Terminated"},
+	}
+
+	// Only the update that changes the phase returns the row, so the change is notified once,
+	// even if the informer, the repair and other servers sync the same workflow at the same time
+	workflowExecutionID := uint64(0)
+	err = sb.Update("workflow_executions").
+		SetMap(fieldMap).
+		Where(whereSynced).
+		Where(sq.Expr("phase IS DISTINCT FROM ?", phase)).
+		Suffix("RETURNING id").
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&workflowExecutionID)
+	if err == sql.ErrNoRows {
+		// The phase is the same, the start and finish times may not be
+		delete(fieldMap, "phase")
+		if len(fieldMap) > 0 {
+			_, err = sb.Update("workflow_executions").
+				SetMap(fieldMap).
+				Where(whereSynced).
+				RunWith(c.DB).
+				Exec()
+			if err != nil {
+				return
+			}
+		}
+
+		return c.SyncWorkflowExecutionNodes(wf)
+	}
+	if err != nil {
+		return
+	}
+
+	c.notifyWorkflowExecutionPhase(wf.Namespace, wf.Name, phase)
+
+	return c.SyncWorkflowExecutionNodes(wf)
+}
+
+// FailMissingWorkflowExecution marks an unfinished workflow execution as failed.
+// This is used when the argo workflow backing the execution no longer exists.
+func (c *Client) FailMissingWorkflowExecution(namespace, uid string) (err error) {
+	_, err = sb.Update("workflow_executions").
+		SetMap(sq.Eq{
+			"phase":       wfv1.NodeFailed,
+			"finished_at": time.Now().UTC(),
+		}).
+		Where(sq.And{
+			sq.Eq{
+				"namespace":   namespace,
+				"uid":         uid,
+				"finished_at": nil,
+			},
+			sq.NotEq{"phase": "Terminated"},
+		}).
+		RunWith(c.DB).
+		Exec()
+
+	return
+}
+
+// RepairWorkflowExecutions finds workflow executions that have not finished and syncs their status
+// with the argo workflows. Executions whose argo workflow no longer exists are marked as failed.
+// This catches status changes that were missed while the server was not running.
+func (c *Client) RepairWorkflowExecutions() (err error) {
+	query := sb.Select("namespace", "uid").
+		From("workflow_executions").
+		Where(sq.And{
+			sq.Eq{
+				"finished_at": nil,
+				"is_archived": false,
+			},
+			sq.NotEq{"phase": []string{"Terminated", string(WorkflowExecutionPhaseQueued)}},
+		})
+
+	var staleExecutions []*WorkflowExecution
+	if err = c.DB.Selectx(&staleExecutions, query); err != nil {
+		return
+	}
+
+	for _, we := range staleExecutions {
+		wf, getErr := c.ArgoprojV1alpha1().Workflows(we.Namespace).Get(we.UID, metav1.GetOptions{})
+		if getErr != nil {
+			if !strings.Contains(getErr.Error(), "not found") {
+				log.WithFields(log.Fields{
+					"Namespace": we.Namespace,
+					"UID":       we.UID,
+					"Error":     getErr.Error(),
+				}).Error("Unable to get workflow.")
+				continue
+			}
+
+			if err = c.FailMissingWorkflowExecution(we.Namespace, we.UID); err != nil {
+				return
+			}
+			continue
+		}
+
+		if err = c.SyncWorkflowExecutionStatus(wf); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
 // AddWorkflowExecutionMetrics merges the metrics provided with the ones present in the workflow execution identified by (namespace, uid)
 func (c *Client) AddWorkflowExecutionMetrics(namespace, uid string, metrics Metrics, override bool) (workflowExecution *WorkflowExecution, err error) {
 	workflowExecution, err = c.GetWorkflowExecution(namespace, uid)
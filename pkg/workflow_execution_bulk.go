@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/label"
+	"github.com/onepanelio/core/pkg/util/request"
+	"github.com/onepanelio/core/pkg/util/types"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+// selectBulkWorkflowExecutions returns the workflow executions selected by opts, oldest first
+func (c *Client) selectBulkWorkflowExecutions(namespace string, opts *WorkflowExecutionBulkOptions) (workflowExecutions []*WorkflowExecution, err error) {
+	query := workflowExecutionsSelectBuilder(namespace, opts.WorkflowTemplateUID, "", false).
+		OrderBy("we.created_at", "we.id").
+		Limit(workflowExecutionBulkLimit + 1)
+
+	if len(opts.UIDs) != 0 {
+		query = query.Where(sq.Eq{"we.uid": opts.UIDs})
+	}
+
+	query, err = applyWorkflowExecutionFilter(query, &request.Request{Filter: opts.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	workflowExecutions = make([]*WorkflowExecution, 0)
+	if err := c.DB.Selectx(&workflowExecutions, query); err != nil {
+		return nil, err
+	}
+
+	if len(workflowExecutions) > workflowExecutionBulkLimit {
+		return nil, util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Bulk operations are limited to %v workflow executions.", workflowExecutionBulkLimit))
+	}
+
+	return
+}
+
+// setWorkflowExecutionLabels replaces the labels of the workflow execution in the database and in its argo workflow, if there is one
+func (c *Client) setWorkflowExecutionLabels(namespace string, workflowExecution *WorkflowExecution, labels types.JSONLabels) error {
+	_, err := sb.Update("workflow_executions").
+		Set("labels", labels).
+		Where(sq.Eq{"id": workflowExecution.ID}).
+		RunWith(c.DB).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(workflowExecution.UID, metav1.GetOptions{})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+
+	if wf.Labels == nil {
+		wf.Labels = make(map[string]string)
+	}
+	label.DeleteWithPrefix(wf.Labels, label.TagPrefix)
+	label.MergeLabelsPrefix(wf.Labels, labels, label.TagPrefix)
+
+	_, err = c.ArgoprojV1alpha1().Workflows(namespace).Update(wf)
+
+	return err
+}
+
+// applyWorkflowExecutionBulkAction applies the action of opts to the workflow execution and sets the outcome in result
+func (c *Client) applyWorkflowExecutionBulkAction(namespace string, opts *WorkflowExecutionBulkOptions, workflowExecution *WorkflowExecution, result *WorkflowExecutionBulkResult) {
+	switch opts.Action {
+	case WorkflowExecutionBulkActionTerminate:
+		result.Error = c.TerminateWorkflowExecution(namespace, workflowExecution.UID)
+	case WorkflowExecutionBulkActionArchive:
+		result.Error = c.ArchiveWorkflowExecution(namespace, workflowExecution.UID)
+	case WorkflowExecutionBulkActionResubmit:
+		result.WorkflowExecution, result.Error = c.ResubmitWorkflowExecution(namespace, workflowExecution.UID)
+	case WorkflowExecutionBulkActionLabel:
+		labels := bulkLabels(workflowExecution.Labels, opts.AddLabels, opts.DeleteLabels)
+		if result.Error = c.setWorkflowExecutionLabels(namespace, workflowExecution, labels); result.Error == nil {
+			result.Labels = labels
+		}
+	}
+
+	if result.Error != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       workflowExecution.UID,
+			"Action":    opts.Action,
+			"Error":     result.Error.Error(),
+		}).Error("Unable to apply bulk action to workflow execution.")
+	}
+}
+
+// BulkWorkflowExecutions applies an action to the workflow executions selected by opts, and returns the outcome for each
+// of them. UIDs that don't match a workflow execution have a NotFound error.
+// The actions run in parallel, see WorkflowExecutionBulkOptions.Parallelism. A dry run only reports
+// which workflow executions the action applies to.
+func (c *Client) BulkWorkflowExecutions(namespace string, opts *WorkflowExecutionBulkOptions) ([]*WorkflowExecutionBulkResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	workflowExecutions, err := c.selectBulkWorkflowExecutions(namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*WorkflowExecutionBulkResult, 0)
+	selected := make(map[*WorkflowExecutionBulkResult]*WorkflowExecution)
+	found := make(map[string]bool)
+	for _, workflowExecution := range workflowExecutions {
+		result := &WorkflowExecutionBulkResult{
+			UID:   workflowExecution.UID,
+			Name:  workflowExecution.Name,
+			Error: checkWorkflowExecutionBulkAction(opts.Action, workflowExecution),
+		}
+		if opts.Action == WorkflowExecutionBulkActionLabel {
+			result.Labels = bulkLabels(workflowExecution.Labels, opts.AddLabels, opts.DeleteLabels)
+		}
+
+		results = append(results, result)
+		selected[result] = workflowExecution
+		found[workflowExecution.UID] = true
+	}
+
+	for _, uid := range opts.UIDs {
+		if !found[uid] {
+			results = append(results, &WorkflowExecutionBulkResult{
+				UID:   uid,
+				Error: util.NewUserError(codes.NotFound, "Workflow execution not found."),
+			})
+			found[uid] = true
+		}
+	}
+
+	if opts.DryRun {
+		return results, nil
+	}
+
+	runWorkflowExecutionBulk(results, opts.parallelism(), func(result *WorkflowExecutionBulkResult) {
+		c.applyWorkflowExecutionBulkAction(namespace, opts, selected[result], result)
+	})
+
+	return results, nil
+}
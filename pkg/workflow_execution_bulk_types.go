@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/types"
+	"google.golang.org/grpc/codes"
+	"sync"
+)
+
+// WorkflowExecutionBulkAction is an operation applied to many workflow executions at once
+type WorkflowExecutionBulkAction string
+
+const (
+	// WorkflowExecutionBulkActionTerminate terminates the workflow executions, see TerminateWorkflowExecution
+	WorkflowExecutionBulkActionTerminate WorkflowExecutionBulkAction = "terminate"
+	// WorkflowExecutionBulkActionArchive archives the workflow executions, see ArchiveWorkflowExecution
+	WorkflowExecutionBulkActionArchive WorkflowExecutionBulkAction = "archive"
+	// WorkflowExecutionBulkActionResubmit resubmits the workflow executions, see ResubmitWorkflowExecution
+	WorkflowExecutionBulkActionResubmit WorkflowExecutionBulkAction = "resubmit"
+	// WorkflowExecutionBulkActionLabel adds and deletes labels of the workflow executions
+	WorkflowExecutionBulkActionLabel WorkflowExecutionBulkAction = "label"
+)
+
+const (
+	// workflowExecutionBulkLimit is the maximum number of workflow executions a bulk operation selects
+	workflowExecutionBulkLimit = 500
+	// defaultWorkflowExecutionBulkParallelism is the number of workflow executions processed at the same time by default
+	defaultWorkflowExecutionBulkParallelism = 5
+	// maxWorkflowExecutionBulkParallelism bounds the requests a bulk operation makes to the argo API at the same time
+	maxWorkflowExecutionBulkParallelism = 20
+)
+
+// WorkflowExecutionBulkOptions select the workflow executions of a bulk operation and what it does with them.
+// Executions are selected by UIDs, or by WorkflowTemplateUID and Filter.
+type WorkflowExecutionBulkOptions struct {
+	Action              WorkflowExecutionBulkAction
+	UIDs                []string
+	WorkflowTemplateUID string
+	Filter              WorkflowExecutionFilter
+	// AddLabels and DeleteLabels are the label changes of WorkflowExecutionBulkActionLabel
+	AddLabels    map[string]string
+	DeleteLabels []string
+	// DryRun only reports the workflow executions the operation would apply to
+	DryRun bool
+	// Parallelism is the number of workflow executions processed at the same time
+	Parallelism int
+}
+
+// WorkflowExecutionBulkResult is the outcome of a bulk operation for one workflow execution
+type WorkflowExecutionBulkResult struct {
+	UID   string
+	Name  string
+	Error error
+	// WorkflowExecution is the new workflow execution of WorkflowExecutionBulkActionResubmit
+	WorkflowExecution *WorkflowExecution
+	// Labels are the labels after WorkflowExecutionBulkActionLabel
+	Labels types.JSONLabels
+}
+
+// validate returns a user error if the options don't select workflow executions or don't have a valid action
+func (o *WorkflowExecutionBulkOptions) validate() error {
+	switch o.Action {
+	case WorkflowExecutionBulkActionTerminate, WorkflowExecutionBulkActionArchive, WorkflowExecutionBulkActionResubmit:
+	case WorkflowExecutionBulkActionLabel:
+		if len(o.AddLabels) == 0 && len(o.DeleteLabels) == 0 {
+			return util.NewUserError(codes.InvalidArgument, "Labels to add or delete are required.")
+		}
+	default:
+		return util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Unknown action '%v'.", o.Action))
+	}
+
+	if len(o.UIDs) == 0 && o.WorkflowTemplateUID == "" && len(o.Filter.Labels) == 0 && o.Filter.Phase == "" && o.Filter.Expression == "" {
+		return util.NewUserError(codes.InvalidArgument, "Select workflow executions by uid, workflow template, labels, phase or filter.")
+	}
+
+	if len(o.UIDs) > workflowExecutionBulkLimit {
+		return util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Bulk operations are limited to %v workflow executions.", workflowExecutionBulkLimit))
+	}
+
+	return nil
+}
+
+// parallelism returns Parallelism within the default and maximum bounds
+func (o *WorkflowExecutionBulkOptions) parallelism() int {
+	if o.Parallelism <= 0 {
+		return defaultWorkflowExecutionBulkParallelism
+	}
+	if o.Parallelism > maxWorkflowExecutionBulkParallelism {
+		return maxWorkflowExecutionBulkParallelism
+	}
+
+	return o.Parallelism
+}
+
+// checkWorkflowExecutionBulkAction returns a user error if the action does not apply to the workflow execution
+func checkWorkflowExecutionBulkAction(action WorkflowExecutionBulkAction, workflowExecution *WorkflowExecution) error {
+	switch action {
+	case WorkflowExecutionBulkActionTerminate:
+		if workflowExecution.FinishedAt != nil {
+			return util.NewUserError(codes.FailedPrecondition, "Workflow execution has already finished.")
+		}
+	case WorkflowExecutionBulkActionResubmit:
+		if workflowExecution.Phase == WorkflowExecutionPhaseQueued {
+			return util.NewUserError(codes.FailedPrecondition, "Queued workflow executions can't be resubmitted.")
+		}
+	}
+
+	return nil
+}
+
+// bulkLabels returns labels with the labels of add set and the keys of remove deleted. labels is not modified.
+func bulkLabels(labels types.JSONLabels, add map[string]string, remove []string) types.JSONLabels {
+	result := types.JSONLabels{}
+	for key, value := range labels {
+		result[key] = value
+	}
+	for _, key := range remove {
+		delete(result, key)
+	}
+	for key, value := range add {
+		result[key] = value
+	}
+
+	return result
+}
+
+// runWorkflowExecutionBulk calls apply for each result, with at most parallelism calls at the same time.
+// Results whose Error is already set are skipped.
+func runWorkflowExecutionBulk(results []*WorkflowExecutionBulkResult, parallelism int, apply func(result *WorkflowExecutionBulkResult)) {
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, parallelism)
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(result *WorkflowExecutionBulkResult) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			apply(result)
+		}(result)
+	}
+
+	wg.Wait()
+}
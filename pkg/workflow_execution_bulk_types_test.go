@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/ptr"
+	"github.com/onepanelio/core/pkg/util/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWorkflowExecutionBulkOptions_Validate makes sure an action and a selection are required
+func TestWorkflowExecutionBulkOptions_Validate(t *testing.T) {
+	tests := []struct {
+		opts  WorkflowExecutionBulkOptions
+		valid bool
+	}{
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionTerminate, UIDs: []string{"a"}}, true},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionArchive, Filter: WorkflowExecutionFilter{Phase: "failed"}}, true},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionResubmit, WorkflowTemplateUID: "train"}, true},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionLabel, UIDs: []string{"a"}, DeleteLabels: []string{"keep"}}, true},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionLabel, UIDs: []string{"a"}}, false},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionArchive}, false},
+		{WorkflowExecutionBulkOptions{Action: "delete", UIDs: []string{"a"}}, false},
+		{WorkflowExecutionBulkOptions{Action: WorkflowExecutionBulkActionArchive, UIDs: make([]string, workflowExecutionBulkLimit+1)}, false},
+	}
+
+	for _, test := range tests {
+		err := test.opts.validate()
+		if test.valid {
+			assert.Nil(t, err)
+			continue
+		}
+
+		userErr, ok := err.(*util.UserError)
+		if assert.True(t, ok, fmt.Sprintf("%+v", test.opts)) {
+			assert.Equal(t, codes.InvalidArgument, userErr.Code)
+		}
+	}
+}
+
+// TestWorkflowExecutionBulkOptions_Parallelism makes sure the parallelism has a default and a maximum
+func TestWorkflowExecutionBulkOptions_Parallelism(t *testing.T) {
+	assert.Equal(t, defaultWorkflowExecutionBulkParallelism, (&WorkflowExecutionBulkOptions{}).parallelism())
+	assert.Equal(t, 3, (&WorkflowExecutionBulkOptions{Parallelism: 3}).parallelism())
+	assert.Equal(t, maxWorkflowExecutionBulkParallelism, (&WorkflowExecutionBulkOptions{Parallelism: 1000}).parallelism())
+}
+
+// TestCheckWorkflowExecutionBulkAction makes sure finished executions are not terminated and queued ones are not resubmitted
+func TestCheckWorkflowExecutionBulkAction(t *testing.T) {
+	finished := &WorkflowExecution{Phase: "Failed", FinishedAt: ptr.Time(time.Now())}
+	queued := &WorkflowExecution{Phase: WorkflowExecutionPhaseQueued}
+
+	assert.NotNil(t, checkWorkflowExecutionBulkAction(WorkflowExecutionBulkActionTerminate, finished))
+	assert.Nil(t, checkWorkflowExecutionBulkAction(WorkflowExecutionBulkActionTerminate, queued))
+	assert.Nil(t, checkWorkflowExecutionBulkAction(WorkflowExecutionBulkActionResubmit, finished))
+	assert.NotNil(t, checkWorkflowExecutionBulkAction(WorkflowExecutionBulkActionResubmit, queued))
+	assert.Nil(t, checkWorkflowExecutionBulkAction(WorkflowExecutionBulkActionArchive, queued))
+}
+
+// TestBulkLabels makes sure labels are added and deleted without modifying the original ones
+func TestBulkLabels(t *testing.T) {
+	labels := types.JSONLabels{"keep": "true", "sweep": "abc"}
+
+	result := bulkLabels(labels, map[string]string{"reviewed": "yes", "sweep": "def"}, []string{"keep", "missing"})
+	assert.Equal(t, types.JSONLabels{"reviewed": "yes", "sweep": "def"}, result)
+	assert.Equal(t, types.JSONLabels{"keep": "true", "sweep": "abc"}, labels)
+}
+
+// TestRunWorkflowExecutionBulk makes sure every result without an error is applied, never more than parallelism at a time
+func TestRunWorkflowExecutionBulk(t *testing.T) {
+	results := make([]*WorkflowExecutionBulkResult, 0)
+	for i := 0; i < 20; i++ {
+		results = append(results, &WorkflowExecutionBulkResult{UID: fmt.Sprintf("wf-%v", i)})
+	}
+	results[4].Error = fmt.Errorf("not found")
+
+	mutex := sync.Mutex{}
+	running := 0
+	maxRunning := 0
+	applied := make(map[string]bool)
+	runWorkflowExecutionBulk(results, 3, func(result *WorkflowExecutionBulkResult) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		applied[result.UID] = true
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+	})
+
+	assert.Len(t, applied, 19)
+	assert.False(t, applied["wf-4"])
+	assert.LessOrEqual(t, maxRunning, 3)
+}
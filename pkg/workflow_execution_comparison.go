@@ -0,0 +1,64 @@
+package v1
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"strconv"
+	"time"
+)
+
+// CompareWorkflowExecutions returns a side-by-side comparison of the workflow executions identified by (namespace, uids),
+// in the order of uids. Duplicate uids are only compared once.
+func (c *Client) CompareWorkflowExecutions(namespace string, uids []string) (comparison *WorkflowExecutionComparison, err error) {
+	uniqueUIDs := make([]string, 0, len(uids))
+	seen := make(map[string]bool)
+	for _, uid := range uids {
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		uniqueUIDs = append(uniqueUIDs, uid)
+	}
+
+	if len(uniqueUIDs) == 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "At least one workflow execution is required.")
+	}
+	if len(uniqueUIDs) > compareWorkflowExecutionsLimit {
+		return nil, util.NewUserError(codes.InvalidArgument, "At most "+strconv.Itoa(compareWorkflowExecutionsLimit)+" workflow executions can be compared.")
+	}
+
+	query := workflowExecutionsSelectBuilder(namespace, "", "", true).
+		Where(sq.Eq{"we.uid": uniqueUIDs})
+
+	workflowExecutions := make([]*WorkflowExecution, 0)
+	if err = c.DB.Selectx(&workflowExecutions, query); err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UIDs":      uniqueUIDs,
+			"Error":     err.Error(),
+		}).Error("Unable to select workflow executions.")
+		return nil, util.NewUserError(codes.Internal, "Error getting workflow executions.")
+	}
+
+	byUID := make(map[string]*WorkflowExecution)
+	for _, we := range workflowExecutions {
+		we.Namespace = namespace
+		if _, err := we.LoadParametersFromBytes(); err != nil {
+			return nil, err
+		}
+		byUID[we.UID] = we
+	}
+
+	ordered := make([]*WorkflowExecution, 0, len(uniqueUIDs))
+	for _, uid := range uniqueUIDs {
+		we, ok := byUID[uid]
+		if !ok {
+			return nil, util.NewUserError(codes.NotFound, "Workflow execution "+uid+" not found.")
+		}
+		ordered = append(ordered, we)
+	}
+
+	return compareWorkflowExecutions(ordered, time.Now().UTC()), nil
+}
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"sort"
+	"time"
+)
+
+// compareWorkflowExecutionsLimit is the maximum number of workflow executions that can be compared at once
+const compareWorkflowExecutionsLimit = 100
+
+// WorkflowExecutionComparisonRow has the values of a parameter or label of the compared workflow executions,
+// in the order of the executions. Values are nil for executions that don't have the parameter or label.
+type WorkflowExecutionComparisonRow struct {
+	Name    string
+	Values  []*string
+	Differs bool
+}
+
+// WorkflowExecutionMetricComparisonRow has the values of a metric of the compared workflow executions,
+// in the order of the executions. Values are nil for executions that don't have the metric.
+type WorkflowExecutionMetricComparisonRow struct {
+	Name    string
+	Values  []*float64
+	Differs bool
+}
+
+// WorkflowExecutionComparison is a side-by-side comparison of workflow executions.
+// Durations are nil for executions that did not start yet. Executions that did not finish are measured until now.
+type WorkflowExecutionComparison struct {
+	WorkflowExecutions []*WorkflowExecution
+	Durations          []*time.Duration
+	Parameters         []*WorkflowExecutionComparisonRow
+	Metrics            []*WorkflowExecutionMetricComparisonRow
+	Labels             []*WorkflowExecutionComparisonRow
+}
+
+// stringValuesDiffer returns true if any of the values is different from the others, including nil values
+func stringValuesDiffer(values []*string) bool {
+	for _, value := range values[1:] {
+		if (value == nil) != (values[0] == nil) {
+			return true
+		}
+		if value != nil && *value != *values[0] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// floatValuesDiffer returns true if any of the values is different from the others, including nil values
+func floatValuesDiffer(values []*float64) bool {
+	for _, value := range values[1:] {
+		if (value == nil) != (values[0] == nil) {
+			return true
+		}
+		if value != nil && *value != *values[0] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// workflowExecutionDuration returns how long the workflow execution ran, until now if it did not finish.
+func workflowExecutionDuration(we *WorkflowExecution, now time.Time) *time.Duration {
+	if we.StartedAt == nil || we.StartedAt.IsZero() {
+		return nil
+	}
+
+	end := now
+	if we.FinishedAt != nil && !we.FinishedAt.IsZero() {
+		end = *we.FinishedAt
+	}
+
+	duration := end.Sub(*we.StartedAt)
+	return &duration
+}
+
+// compareWorkflowExecutions aligns the parameters, metrics and labels of the workflow executions by name.
+// Parameters and metrics are in the order they first appear in, labels are sorted by key.
+// If an execution has more than one metric with the same name, the first one is used.
+// The parameters of the executions must be loaded.
+func compareWorkflowExecutions(workflowExecutions []*WorkflowExecution, now time.Time) *WorkflowExecutionComparison {
+	result := &WorkflowExecutionComparison{
+		WorkflowExecutions: workflowExecutions,
+		Durations:          make([]*time.Duration, len(workflowExecutions)),
+		Parameters:         make([]*WorkflowExecutionComparisonRow, 0),
+		Metrics:            make([]*WorkflowExecutionMetricComparisonRow, 0),
+		Labels:             make([]*WorkflowExecutionComparisonRow, 0),
+	}
+
+	parameters := make(map[string]*WorkflowExecutionComparisonRow)
+	metrics := make(map[string]*WorkflowExecutionMetricComparisonRow)
+	labels := make(map[string]*WorkflowExecutionComparisonRow)
+	for i, we := range workflowExecutions {
+		result.Durations[i] = workflowExecutionDuration(we, now)
+
+		for _, parameter := range we.Parameters {
+			row, ok := parameters[parameter.Name]
+			if !ok {
+				row = &WorkflowExecutionComparisonRow{
+					Name:   parameter.Name,
+					Values: make([]*string, len(workflowExecutions)),
+				}
+				parameters[parameter.Name] = row
+				result.Parameters = append(result.Parameters, row)
+			}
+			if parameter.Value != nil {
+				value := *parameter.Value
+				row.Values[i] = &value
+			} else {
+				empty := ""
+				row.Values[i] = &empty
+			}
+		}
+
+		for _, metric := range we.Metrics {
+			row, ok := metrics[metric.Name]
+			if !ok {
+				row = &WorkflowExecutionMetricComparisonRow{
+					Name:   metric.Name,
+					Values: make([]*float64, len(workflowExecutions)),
+				}
+				metrics[metric.Name] = row
+				result.Metrics = append(result.Metrics, row)
+			}
+			if row.Values[i] == nil {
+				value := metric.Value
+				row.Values[i] = &value
+			}
+		}
+
+		for key, value := range we.Labels {
+			row, ok := labels[key]
+			if !ok {
+				row = &WorkflowExecutionComparisonRow{
+					Name:   key,
+					Values: make([]*string, len(workflowExecutions)),
+				}
+				labels[key] = row
+				result.Labels = append(result.Labels, row)
+			}
+			labelValue := value
+			row.Values[i] = &labelValue
+		}
+	}
+
+	sort.Slice(result.Labels, func(i, j int) bool {
+		return result.Labels[i].Name < result.Labels[j].Name
+	})
+
+	for _, row := range result.Parameters {
+		row.Differs = stringValuesDiffer(row.Values)
+	}
+	for _, row := range result.Metrics {
+		row.Differs = floatValuesDiffer(row.Values)
+	}
+	for _, row := range result.Labels {
+		row.Differs = stringValuesDiffer(row.Values)
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util/ptr"
+	"github.com/onepanelio/core/pkg/util/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// TestCompareWorkflowExecutions makes sure parameters, metrics and labels are aligned by name and differences are flagged
+func TestCompareWorkflowExecutions(t *testing.T) {
+	startedAt := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(90 * time.Second)
+	now := startedAt.Add(time.Hour)
+
+	workflowExecutions := []*WorkflowExecution{
+		{
+			UID:        "run-1",
+			StartedAt:  &startedAt,
+			FinishedAt: &finishedAt,
+			Parameters: []Parameter{
+				{Name: "learning-rate", Value: ptr.String("0.01")},
+				{Name: "epochs", Value: ptr.String("10")},
+			},
+			Metrics: Metrics{
+				{Name: "accuracy", Value: 0.9},
+				{Name: "accuracy", Value: 0.1},
+			},
+			Labels: types.JSONLabels{"team": "vision", "tuning": "true"},
+		},
+		{
+			UID:       "run-2",
+			StartedAt: &startedAt,
+			Parameters: []Parameter{
+				{Name: "learning-rate", Value: ptr.String("0.001")},
+				{Name: "epochs", Value: ptr.String("10")},
+				{Name: "batch-size", Value: ptr.String("32")},
+			},
+			Metrics: Metrics{
+				{Name: "accuracy", Value: 0.9},
+				{Name: "loss", Value: 0.3},
+			},
+			Labels: types.JSONLabels{"team": "vision"},
+		},
+		{
+			UID: "run-3",
+		},
+	}
+
+	result := compareWorkflowExecutions(workflowExecutions, now)
+
+	assert.Equal(t, 90*time.Second, *result.Durations[0])
+	assert.Equal(t, time.Hour, *result.Durations[1])
+	assert.Nil(t, result.Durations[2])
+
+	assert.Len(t, result.Parameters, 3)
+	assert.Equal(t, "learning-rate", result.Parameters[0].Name)
+	assert.Equal(t, "0.001", *result.Parameters[0].Values[1])
+	assert.True(t, result.Parameters[0].Differs)
+	assert.Equal(t, "batch-size", result.Parameters[2].Name)
+	assert.Nil(t, result.Parameters[2].Values[0])
+	assert.Equal(t, "32", *result.Parameters[2].Values[1])
+
+	assert.Len(t, result.Metrics, 2)
+	assert.Equal(t, "accuracy", result.Metrics[0].Name)
+	assert.Equal(t, 0.9, *result.Metrics[0].Values[0])
+	assert.Nil(t, result.Metrics[0].Values[2])
+	assert.True(t, result.Metrics[0].Differs)
+
+	assert.Len(t, result.Labels, 2)
+	assert.Equal(t, "team", result.Labels[0].Name)
+	assert.Equal(t, "tuning", result.Labels[1].Name)
+	assert.Nil(t, result.Labels[1].Values[1])
+}
+
+// TestCompareWorkflowExecutions_Same makes sure equal values are not flagged
+func TestCompareWorkflowExecutions_Same(t *testing.T) {
+	workflowExecutions := []*WorkflowExecution{
+		{
+			Parameters: []Parameter{{Name: "epochs", Value: ptr.String("10")}},
+			Metrics:    Metrics{{Name: "accuracy", Value: 0.9}},
+		},
+		{
+			Parameters: []Parameter{{Name: "epochs", Value: ptr.String("10")}},
+			Metrics:    Metrics{{Name: "accuracy", Value: 0.9}},
+		},
+	}
+
+	result := compareWorkflowExecutions(workflowExecutions, time.Now())
+	assert.False(t, result.Parameters[0].Differs)
+	assert.False(t, result.Metrics[0].Differs)
+}
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util"
+	"google.golang.org/grpc/codes"
+	"strings"
+)
+
+// artifactKeysOverlap is the condition for an artifact written by p that c reads: the same key,
+// or a file inside a directory, in either order
+const artifactKeysOverlap = `p.bucket = c.bucket AND (
+	p.key = c.key OR
+	left(c.key, length(p.key) + 1) = p.key || '/' OR
+	left(p.key, length(c.key) + 1) = c.key || '/')`
+
+// syncWorkflowExecutionArtifacts replaces the artifacts read and written by the workflow execution with the ones
+// in the node status of the argo workflow
+func syncWorkflowExecutionArtifacts(runner sq.BaseRunner, workflowExecutionID uint64, wf *wfv1.Workflow) error {
+	_, err := sb.Delete("workflow_execution_artifacts").
+		Where(sq.Eq{"workflow_execution_id": workflowExecutionID}).
+		RunWith(runner).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	artifacts := workflowExecutionArtifactsFromNodes(workflowExecutionID, wf.Status.Nodes)
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	insert := sb.Insert("workflow_execution_artifacts").
+		Columns("workflow_execution_id", "node_id", "direction", "name", "bucket", "key")
+	for _, artifact := range artifacts {
+		insert = insert.Values(artifact.WorkflowExecutionID, artifact.NodeID, artifact.Direction, artifact.Name, artifact.Bucket, artifact.Key)
+	}
+
+	_, err = insert.RunWith(runner).Exec()
+
+	return err
+}
+
+// selectLineageLinks returns the links of the workflow executions with the ids in the namespace.
+// A workflow execution only reads artifacts from the ones created before it.
+func (c *Client) selectLineageLinks(namespace string, direction LineageDirection, ids []uint64) (links []*lineageLink, err error) {
+	where := sq.Eq{
+		"c.direction":   WorkflowExecutionArtifactInput,
+		"pwe.namespace": namespace,
+		"cwe.namespace": namespace,
+	}
+	if direction == LineageDownstream {
+		where["p.workflow_execution_id"] = ids
+	} else {
+		where["c.workflow_execution_id"] = ids
+	}
+
+	query := sb.Select(`p.workflow_execution_id "producer_id"`, `c.workflow_execution_id "consumer_id"`, "p.bucket", "p.key").
+		Distinct().
+		From("workflow_execution_artifacts c").
+		Join("workflow_execution_artifacts p ON p.direction = 'output' AND p.workflow_execution_id <> c.workflow_execution_id AND "+artifactKeysOverlap).
+		Join("workflow_executions pwe ON pwe.id = p.workflow_execution_id").
+		Join("workflow_executions cwe ON cwe.id = c.workflow_execution_id AND pwe.created_at < cwe.created_at").
+		Where(where).
+		OrderBy(`"producer_id"`, `"consumer_id"`, "p.key")
+
+	links = make([]*lineageLink, 0)
+	err = c.DB.Selectx(&links, query)
+
+	return
+}
+
+// selectLineageWorkflowExecutions returns the workflow executions with the ids, including archived ones
+func (c *Client) selectLineageWorkflowExecutions(ids []uint64) (workflowExecutions []*WorkflowExecution, err error) {
+	query := sb.Select(getWorkflowExecutionColumns("we")...).
+		Columns(`wtv.version "workflow_template.version"`, `wt.name "workflow_template.name"`, `wt.uid "workflow_template.uid"`).
+		From("workflow_executions we").
+		LeftJoin("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		LeftJoin("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{"we.id": ids}).
+		OrderBy("we.created_at", "we.id")
+
+	workflowExecutions = make([]*WorkflowExecution, 0)
+	err = c.DB.Selectx(&workflowExecutions, query)
+
+	return
+}
+
+// ListWorkflowExecutionArtifacts returns the artifacts read and written by the workflow execution identified by (namespace, uid)
+func (c *Client) ListWorkflowExecutionArtifacts(namespace, uid string) (artifacts []*WorkflowExecutionArtifact, err error) {
+	query := sb.Select("wea.id", "wea.workflow_execution_id", "wea.node_id", "wea.direction", "wea.name", "wea.bucket", "wea.key", "wea.created_at").
+		From("workflow_execution_artifacts wea").
+		Join("workflow_executions we ON we.id = wea.workflow_execution_id").
+		Where(sq.Eq{
+			"we.namespace": namespace,
+			"we.uid":       uid,
+		}).
+		OrderBy("wea.direction", "wea.node_id", "wea.name")
+
+	artifacts = make([]*WorkflowExecutionArtifact, 0)
+	err = c.DB.Selectx(&artifacts, query)
+
+	return
+}
+
+// GetWorkflowExecutionLineage returns the graph of workflow executions that the one identified by (namespace, uid)
+// is connected to by artifacts, following direction for at most depth workflow executions.
+// A depth of 0 follows up to maxWorkflowExecutionLineageDepth.
+func (c *Client) GetWorkflowExecutionLineage(namespace, uid string, direction LineageDirection, depth int) (*WorkflowExecutionLineage, error) {
+	if direction != LineageUpstream && direction != LineageDownstream {
+		return nil, util.NewUserError(codes.InvalidArgument, "Direction must be upstream or downstream.")
+	}
+	if depth <= 0 || depth > maxWorkflowExecutionLineageDepth {
+		depth = maxWorkflowExecutionLineageDepth
+	}
+
+	startID, err := c.getWorkflowExecutionID(namespace, uid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := c.ListWorkflowExecutionArtifacts(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	depths, links, err := traverseLineage(startID, direction, depth, func(ids []uint64) ([]*lineageLink, error) {
+		return c.selectLineageLinks(namespace, direction, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(depths))
+	for id := range depths {
+		ids = append(ids, id)
+	}
+	workflowExecutions, err := c.selectLineageWorkflowExecutions(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := &WorkflowExecutionLineage{
+		Artifacts: artifacts,
+		Nodes:     make([]*WorkflowExecutionLineageNode, 0),
+		Edges:     make([]*WorkflowExecutionLineageEdge, 0),
+	}
+	uids := make(map[uint64]string)
+	for _, workflowExecution := range workflowExecutions {
+		uids[workflowExecution.ID] = workflowExecution.UID
+		lineage.Nodes = append(lineage.Nodes, &WorkflowExecutionLineageNode{
+			WorkflowExecution: workflowExecution,
+			Depth:             depths[workflowExecution.ID],
+		})
+	}
+	for _, link := range links {
+		lineage.Edges = append(lineage.Edges, &WorkflowExecutionLineageEdge{
+			From:   uids[link.ProducerID],
+			To:     uids[link.ConsumerID],
+			Bucket: link.Bucket,
+			Key:    link.Key,
+		})
+	}
+
+	return lineage, nil
+}
+
+// ListArtifactLineage returns the workflow executions of the namespace that wrote or read the artifact with the key,
+// or a file inside it, oldest first. This answers questions like which runs used a dataset.
+func (c *Client) ListArtifactLineage(namespace, key string) (artifacts []*WorkflowExecutionArtifact, err error) {
+	key = strings.TrimRight(key, "/")
+	if key == "" {
+		return nil, util.NewUserError(codes.InvalidArgument, "Key is required.")
+	}
+
+	query := sb.Select("wea.id", "wea.workflow_execution_id", "wea.node_id", "wea.direction", "wea.name", "wea.bucket", "wea.key", "wea.created_at").
+		Columns(getWorkflowExecutionColumns("we", "workflow_execution")...).
+		From("workflow_execution_artifacts wea").
+		Join("workflow_executions we ON we.id = wea.workflow_execution_id").
+		Where(sq.Eq{"we.namespace": namespace}).
+		Where(sq.Or{
+			sq.Eq{"wea.key": key},
+			sq.Expr("left(wea.key, length(?::text) + 1) = ?::text || '/'", key, key),
+		}).
+		OrderBy("we.created_at", "wea.direction DESC", "wea.key")
+
+	artifacts = make([]*WorkflowExecutionArtifact, 0)
+	err = c.DB.Selectx(&artifacts, query)
+
+	return
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxWorkflowExecutionLineageDepth is the number of workflow executions a lineage query follows from the start at most
+const maxWorkflowExecutionLineageDepth = 10
+
+// WorkflowExecutionArtifactDirection tells if a workflow execution read or wrote an artifact
+type WorkflowExecutionArtifactDirection string
+
+const (
+	// WorkflowExecutionArtifactInput is an artifact read by a workflow execution
+	WorkflowExecutionArtifactInput WorkflowExecutionArtifactDirection = "input"
+	// WorkflowExecutionArtifactOutput is an artifact written by a workflow execution
+	WorkflowExecutionArtifactOutput WorkflowExecutionArtifactDirection = "output"
+)
+
+// LineageDirection is the direction a lineage query follows from a workflow execution
+type LineageDirection string
+
+const (
+	// LineageUpstream follows the workflow executions that wrote the artifacts a workflow execution read,
+	// like where a model came from
+	LineageUpstream LineageDirection = "upstream"
+	// LineageDownstream follows the workflow executions that read the artifacts a workflow execution wrote,
+	// like which runs used a dataset
+	LineageDownstream LineageDirection = "downstream"
+)
+
+// WorkflowExecutionArtifact is an artifact that a node of a workflow execution read or wrote, identified by its bucket and key
+type WorkflowExecutionArtifact struct {
+	ID                  uint64
+	WorkflowExecutionID uint64 `db:"workflow_execution_id"`
+	NodeID              string `db:"node_id"`
+	Direction           WorkflowExecutionArtifactDirection
+	Name                string
+	Bucket              string
+	Key                 string
+	CreatedAt           time.Time          `db:"created_at"`
+	WorkflowExecution   *WorkflowExecution `db:"workflow_execution"`
+}
+
+// WorkflowExecutionLineageEdge links the workflow execution that wrote an artifact to one that read it
+type WorkflowExecutionLineageEdge struct {
+	From   string
+	To     string
+	Bucket string
+	Key    string
+}
+
+// WorkflowExecutionLineageNode is a workflow execution of a lineage graph.
+// Depth is the number of edges between it and the workflow execution the query started from.
+type WorkflowExecutionLineageNode struct {
+	WorkflowExecution *WorkflowExecution
+	Depth             int
+}
+
+// WorkflowExecutionLineage is the graph of workflow executions connected by the artifacts they read and wrote
+type WorkflowExecutionLineage struct {
+	// Artifacts are the artifacts the workflow execution the query started from read and wrote
+	Artifacts []*WorkflowExecutionArtifact
+	Nodes     []*WorkflowExecutionLineageNode
+	Edges     []*WorkflowExecutionLineageEdge
+}
+
+// lineageLink is an artifact written by the producer workflow execution and read by the consumer
+type lineageLink struct {
+	ProducerID uint64 `db:"producer_id"`
+	ConsumerID uint64 `db:"consumer_id"`
+	Bucket     string
+	Key        string
+}
+
+// artifactLocation returns the bucket and key of an artifact stored in S3 or GCS.
+// Keys have no trailing slash, so a directory and its files share a prefix. ok is false for other artifacts.
+func artifactLocation(artifact *wfv1.Artifact) (bucket, key string, ok bool) {
+	switch {
+	case artifact.S3 != nil:
+		bucket, key = artifact.S3.Bucket, artifact.S3.Key
+	case artifact.GCS != nil:
+		bucket, key = artifact.GCS.Bucket, artifact.GCS.Key
+	default:
+		return "", "", false
+	}
+
+	key = strings.TrimRight(key, "/")
+
+	return bucket, key, key != ""
+}
+
+// workflowExecutionArtifactsFromNodes returns the artifacts read and written by the pods of the workflow, ordered by node.
+// Steps and DAG nodes only pass artifacts on to their pods, so they are ignored.
+func workflowExecutionArtifactsFromNodes(workflowExecutionID uint64, nodes wfv1.Nodes) []*WorkflowExecutionArtifact {
+	nodeIDs := make([]string, 0, len(nodes))
+	for nodeID := range nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	result := make([]*WorkflowExecutionArtifact, 0)
+	add := func(nodeID string, direction WorkflowExecutionArtifactDirection, artifacts []wfv1.Artifact) {
+		for i := range artifacts {
+			bucket, key, ok := artifactLocation(&artifacts[i])
+			if !ok {
+				continue
+			}
+
+			result = append(result, &WorkflowExecutionArtifact{
+				WorkflowExecutionID: workflowExecutionID,
+				NodeID:              nodeID,
+				Direction:           direction,
+				Name:                artifacts[i].Name,
+				Bucket:              bucket,
+				Key:                 key,
+			})
+		}
+	}
+
+	for _, nodeID := range nodeIDs {
+		node := nodes[nodeID]
+		if node.Type != wfv1.NodeTypePod {
+			continue
+		}
+
+		if node.Inputs != nil {
+			add(nodeID, WorkflowExecutionArtifactInput, node.Inputs.Artifacts)
+		}
+		if node.Outputs != nil {
+			add(nodeID, WorkflowExecutionArtifactOutput, node.Outputs.Artifacts)
+		}
+	}
+
+	return result
+}
+
+// traverseLineage follows the links from the workflow execution with startID, breadth first, for at most maxDepth steps.
+// next returns the links of the workflow executions with the ids: for LineageUpstream their producers,
+// for LineageDownstream their consumers.
+// It returns the depth of each workflow execution reached, including the start with depth 0, and the links followed.
+func traverseLineage(startID uint64, direction LineageDirection, maxDepth int, next func(ids []uint64) ([]*lineageLink, error)) (map[uint64]int, []*lineageLink, error) {
+	depths := map[uint64]int{startID: 0}
+	links := make([]*lineageLink, 0)
+	frontier := []uint64{startID}
+	for depth := 1; depth <= maxDepth && len(frontier) != 0; depth++ {
+		found, err := next(frontier)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		frontier = make([]uint64, 0)
+		for _, link := range found {
+			links = append(links, link)
+
+			id := link.ProducerID
+			if direction == LineageDownstream {
+				id = link.ConsumerID
+			}
+			if _, ok := depths[id]; ok {
+				continue
+			}
+
+			depths[id] = depth
+			frontier = append(frontier, id)
+		}
+	}
+
+	return depths, links, nil
+}
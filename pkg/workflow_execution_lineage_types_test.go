@@ -0,0 +1,110 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestArtifactLocation makes sure S3 and GCS keys are returned without a trailing slash, and other artifacts are skipped
+func TestArtifactLocation(t *testing.T) {
+	bucket, key, ok := artifactLocation(&wfv1.Artifact{
+		ArtifactLocation: wfv1.ArtifactLocation{
+			S3: &wfv1.S3Artifact{S3Bucket: wfv1.S3Bucket{Bucket: "data"}, Key: "datasets/coco/"},
+		},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "data", bucket)
+	assert.Equal(t, "datasets/coco", key)
+
+	bucket, key, ok = artifactLocation(&wfv1.Artifact{
+		ArtifactLocation: wfv1.ArtifactLocation{
+			GCS: &wfv1.GCSArtifact{GCSBucket: wfv1.GCSBucket{Bucket: "models"}, Key: "tf/model.tar"},
+		},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "models", bucket)
+	assert.Equal(t, "tf/model.tar", key)
+
+	_, _, ok = artifactLocation(&wfv1.Artifact{
+		ArtifactLocation: wfv1.ArtifactLocation{HTTP: &wfv1.HTTPArtifact{URL: "https://example.com/data.zip"}},
+	})
+	assert.False(t, ok)
+
+	_, _, ok = artifactLocation(&wfv1.Artifact{
+		ArtifactLocation: wfv1.ArtifactLocation{S3: &wfv1.S3Artifact{Key: "/"}},
+	})
+	assert.False(t, ok)
+}
+
+// TestWorkflowExecutionArtifactsFromNodes makes sure only the artifacts of pods are returned
+func TestWorkflowExecutionArtifactsFromNodes(t *testing.T) {
+	s3 := func(name, key string) wfv1.Artifact {
+		return wfv1.Artifact{
+			Name:             name,
+			ArtifactLocation: wfv1.ArtifactLocation{S3: &wfv1.S3Artifact{S3Bucket: wfv1.S3Bucket{Bucket: "b"}, Key: key}},
+		}
+	}
+
+	nodes := wfv1.Nodes{
+		"wf": {
+			Type:   wfv1.NodeTypeDAG,
+			Inputs: &wfv1.Inputs{Artifacts: []wfv1.Artifact{s3("data", "datasets/coco")}},
+		},
+		"wf-2": {
+			Type:    wfv1.NodeTypePod,
+			Outputs: &wfv1.Outputs{Artifacts: []wfv1.Artifact{s3("model", "artifacts/ns/wf/wf-2/model.tgz")}},
+		},
+		"wf-1": {
+			Type:    wfv1.NodeTypePod,
+			Inputs:  &wfv1.Inputs{Artifacts: []wfv1.Artifact{s3("data", "datasets/coco"), {Name: "params", ArtifactLocation: wfv1.ArtifactLocation{Raw: &wfv1.RawArtifact{Data: "x"}}}}},
+			Outputs: &wfv1.Outputs{Artifacts: []wfv1.Artifact{s3("logs", "artifacts/ns/wf/wf-1/main.log")}},
+		},
+	}
+
+	artifacts := workflowExecutionArtifactsFromNodes(3, nodes)
+	assert.Equal(t, []*WorkflowExecutionArtifact{
+		{WorkflowExecutionID: 3, NodeID: "wf-1", Direction: WorkflowExecutionArtifactInput, Name: "data", Bucket: "b", Key: "datasets/coco"},
+		{WorkflowExecutionID: 3, NodeID: "wf-1", Direction: WorkflowExecutionArtifactOutput, Name: "logs", Bucket: "b", Key: "artifacts/ns/wf/wf-1/main.log"},
+		{WorkflowExecutionID: 3, NodeID: "wf-2", Direction: WorkflowExecutionArtifactOutput, Name: "model", Bucket: "b", Key: "artifacts/ns/wf/wf-2/model.tgz"},
+	}, artifacts)
+}
+
+// TestTraverseLineage makes sure the graph is followed breadth first up to the depth, visiting each execution once
+func TestTraverseLineage(t *testing.T) {
+	// 1 -> 2 -> 4 -> 5 and 1 -> 3 -> 4, producer -> consumer
+	graph := []*lineageLink{
+		{ProducerID: 1, ConsumerID: 2},
+		{ProducerID: 1, ConsumerID: 3},
+		{ProducerID: 2, ConsumerID: 4},
+		{ProducerID: 3, ConsumerID: 4},
+		{ProducerID: 4, ConsumerID: 5},
+	}
+	next := func(direction LineageDirection) func(ids []uint64) ([]*lineageLink, error) {
+		return func(ids []uint64) ([]*lineageLink, error) {
+			result := make([]*lineageLink, 0)
+			for _, id := range ids {
+				for _, link := range graph {
+					if (direction == LineageDownstream && link.ProducerID == id) || (direction == LineageUpstream && link.ConsumerID == id) {
+						result = append(result, link)
+					}
+				}
+			}
+			return result, nil
+		}
+	}
+
+	depths, links, err := traverseLineage(1, LineageDownstream, 10, next(LineageDownstream))
+	assert.Nil(t, err)
+	assert.Equal(t, map[uint64]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 3}, depths)
+	assert.Len(t, links, 5)
+
+	depths, links, err = traverseLineage(1, LineageDownstream, 2, next(LineageDownstream))
+	assert.Nil(t, err)
+	assert.Equal(t, map[uint64]int{1: 0, 2: 1, 3: 1, 4: 2}, depths)
+	assert.Len(t, links, 4)
+
+	depths, _, err = traverseLineage(5, LineageUpstream, 10, next(LineageUpstream))
+	assert.Nil(t, err)
+	assert.Equal(t, map[uint64]int{5: 0, 4: 1, 2: 2, 3: 2, 1: 3}, depths)
+}
@@ -0,0 +1,181 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo/workflow/common"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+)
+
+// getWorkflowExecutionLogSources opens the logs of all the pod nodes of the workflow.
+// Pods that still exist are read from kubernetes, including all of their containers except for the argo wait container.
+// Pods that were removed are read from the main container logs archived in the artifact repository.
+// Pods without any logs are skipped.
+func (c *Client) getWorkflowExecutionLogSources(wf *wfv1.Workflow, opts *WorkflowExecutionLogsOptions) (sources []*logSource, err error) {
+	nodes := make([]wfv1.NodeStatus, 0)
+	for _, node := range wf.Status.Nodes {
+		if node.Type == wfv1.NodeTypePod {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if !nodes[i].StartedAt.Equal(&nodes[j].StartedAt) {
+			return nodes[i].StartedAt.Before(&nodes[j].StartedAt)
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	var store ArtifactStore
+	var config *NamespaceConfig
+	sources = make([]*logSource, 0)
+	for _, node := range nodes {
+		podName := node.ID
+		pod, podErr := c.CoreV1().Pods(wf.Namespace).Get(podName, metav1.GetOptions{})
+		if podErr == nil {
+			for _, container := range pod.Spec.Containers {
+				if container.Name == common.WaitContainerName {
+					continue
+				}
+
+				podLogOptions := &corev1.PodLogOptions{
+					Container:  container.Name,
+					Timestamps: true,
+				}
+				if opts.Since != nil {
+					sinceTime := metav1.NewTime(*opts.Since)
+					podLogOptions.SinceTime = &sinceTime
+				}
+				stream, err := c.CoreV1().Pods(wf.Namespace).GetLogs(podName, podLogOptions).Stream()
+				if err != nil {
+					// Containers that did not start yet have no logs
+					continue
+				}
+
+				sources = append(sources, newLogSource(podName, container.Name, stream))
+			}
+			continue
+		}
+
+		if !node.Completed() {
+			continue
+		}
+
+		if store == nil {
+			config, err = c.GetNamespaceConfig(wf.Namespace)
+			if err != nil {
+				return
+			}
+			store, err = c.getArtifactStore(wf.Namespace, &config.ArtifactRepository)
+			if err != nil {
+				return
+			}
+		}
+
+		key := config.ArtifactRepository.FormatKey(wf.Namespace, wf.Name, podName) + "/" + common.MainContainerName + ".log"
+		stream, err := store.Get(key, 0, 0)
+		if err != nil {
+			continue
+		}
+		sources = append(sources, newLogSource(podName, common.MainContainerName, stream))
+	}
+
+	return
+}
+
+// getWorkflowExecutionLogWorkflow returns the argo workflow of the workflow execution identified by (namespace, uid).
+// If the argo workflow was removed, a workflow with the nodes stored in the database is returned instead,
+// so the logs archived in the artifact repository can still be read.
+func (c *Client) getWorkflowExecutionLogWorkflow(namespace, uid string) (*wfv1.Workflow, error) {
+	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
+	if err == nil {
+		return wf, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Unable to get workflow.")
+		return nil, util.NewUserError(codes.Unknown, "Unable to get workflow.")
+	}
+
+	nodes, err := c.selectWorkflowExecutionNodes(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, util.NewUserError(codes.NotFound, "Workflow not found.")
+	}
+
+	// The name of a workflow is its uid
+	wf = &wfv1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uid,
+			Namespace: namespace,
+		},
+		Status: wfv1.WorkflowStatus{
+			Nodes: make(map[string]wfv1.NodeStatus),
+		},
+	}
+	for _, node := range nodes {
+		wf.Status.Nodes[node.NodeID] = node.nodeStatus()
+	}
+
+	return wf, nil
+}
+
+// GetMergedWorkflowExecutionLogs returns the logs of all the pods and containers of the workflow execution,
+// merged in the order of their timestamps. Each entry has the pod and container it was logged by.
+// Only the entries that pass the options are returned. The logs are not followed, the channel is closed
+// once all logs are sent, or when stopCh is closed.
+func (c *Client) GetMergedWorkflowExecutionLogs(namespace, uid string, opts *WorkflowExecutionLogsOptions, stopCh <-chan struct{}) (<-chan []*LogEntry, error) {
+	if opts == nil {
+		opts = &WorkflowExecutionLogsOptions{}
+	}
+	matches, err := opts.logEntryMatcher()
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid filter regular expression.")
+	}
+	if opts.Since != nil && opts.Until != nil && opts.Until.Before(*opts.Since) {
+		return nil, util.NewUserError(codes.InvalidArgument, "Until must be after since.")
+	}
+
+	wf, err := c.getWorkflowExecutionLogWorkflow(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := c.getWorkflowExecutionLogSources(wf, opts)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Unable to read logs.")
+		for _, source := range sources {
+			source.close()
+		}
+		return nil, util.NewUserError(codes.Unknown, "Unable to read logs.")
+	}
+
+	logWatcher := make(chan []*LogEntry)
+	go func() {
+		defer close(logWatcher)
+
+		mergeLogSources(sources, opts, matches, func(chunk []*LogEntry) bool {
+			select {
+			case logWatcher <- chunk:
+				return true
+			case <-stopCh:
+				return false
+			}
+		})
+	}()
+
+	return logWatcher, nil
+}
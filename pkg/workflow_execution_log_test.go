@@ -0,0 +1,42 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+// TestClient_GetWorkflowExecutionLogWorkflow_Removed makes sure the logs of a workflow execution whose argo workflow
+// was removed are read from the nodes stored in the database
+func TestClient_GetWorkflowExecutionLogWorkflow_Removed(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	wf := setArgoWorkflowPhaseForTest(t, c, namespace, we.UID, wfv1.NodeSucceeded, true)
+	wf.Status.Nodes = map[string]wfv1.NodeStatus{
+		"test-1": {
+			ID:         "test-1",
+			Name:       "test.main",
+			Type:       wfv1.NodeTypePod,
+			Phase:      wfv1.NodeSucceeded,
+			StartedAt:  wf.Status.StartedAt,
+			FinishedAt: wf.Status.FinishedAt,
+		},
+	}
+	assert.Nil(t, c.SyncWorkflowExecutionNodes(wf))
+	assert.Nil(t, c.ArgoprojV1alpha1().Workflows(namespace).Delete(we.UID, &metav1.DeleteOptions{}))
+
+	stored, err := c.getWorkflowExecutionLogWorkflow(namespace, we.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, we.UID, stored.Name)
+	if assert.Contains(t, stored.Status.Nodes, "test-1") {
+		assert.True(t, stored.Status.Nodes["test-1"].Completed())
+	}
+
+	_, err = c.getWorkflowExecutionLogWorkflow(namespace, "missing")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// logChunkSize is the maximum number of log entries sent at once by GetMergedWorkflowExecutionLogs
+const logChunkSize = 100
+
+// WorkflowExecutionLogsOptions are the options to get the merged logs of all the pods and containers of a workflow execution.
+type WorkflowExecutionLogsOptions struct {
+	// Filter keeps only the entries that contain it. If Regex is true, it is a regular expression instead.
+	Filter string
+	Regex  bool
+	// Since and Until keep only the entries in the time window, if they are not nil
+	Since *time.Time
+	Until *time.Time
+	// TailLines keeps only the last TailLines entries, if it is greater than 0
+	TailLines int
+}
+
+// logEntryMatcher returns a function that returns true if the content of an entry passes the filter of the options
+func (o *WorkflowExecutionLogsOptions) logEntryMatcher() (func(content string) bool, error) {
+	if o.Filter == "" {
+		return func(content string) bool {
+			return true
+		}, nil
+	}
+
+	if !o.Regex {
+		return func(content string) bool {
+			return strings.Contains(content, o.Filter)
+		}, nil
+	}
+
+	re, err := regexp.Compile(o.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return re.MatchString, nil
+}
+
+// logSource is a stream of the log entries of a single container, in the order they were written.
+type logSource struct {
+	podName       string
+	containerName string
+	scanner       *bufio.Scanner
+	closer        io.Closer
+	// lastTimestamp is used for entries without a timestamp, so they stay next to the entries before them
+	lastTimestamp time.Time
+	current       *LogEntry
+}
+
+// newLogSource creates a logSource that reads lines from stream. The stream is closed when the source is closed.
+func newLogSource(podName, containerName string, stream io.ReadCloser) *logSource {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &logSource{
+		podName:       podName,
+		containerName: containerName,
+		scanner:       scanner,
+		closer:        stream,
+	}
+}
+
+// next reads the next entry into current. It returns false when there are no more entries.
+func (s *logSource) next() bool {
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		entry := LogEntryFromLine(&line)
+		if entry == nil {
+			continue
+		}
+		if entry.Timestamp.IsZero() {
+			entry.Timestamp = s.lastTimestamp
+		} else {
+			s.lastTimestamp = entry.Timestamp
+		}
+		entry.PodName = s.podName
+		entry.ContainerName = s.containerName
+
+		s.current = entry
+		return true
+	}
+
+	s.current = nil
+	return false
+}
+
+// close closes the underlying stream
+func (s *logSource) close() error {
+	return s.closer.Close()
+}
+
+// logSourceHeap orders log sources by the timestamp of their current entry, ties keep the order of the sources.
+type logSourceHeap struct {
+	sources []*logSource
+	index   map[*logSource]int
+}
+
+func (h *logSourceHeap) Len() int { return len(h.sources) }
+
+func (h *logSourceHeap) Less(i, j int) bool {
+	a, b := h.sources[i], h.sources[j]
+	if !a.current.Timestamp.Equal(b.current.Timestamp) {
+		return a.current.Timestamp.Before(b.current.Timestamp)
+	}
+
+	return h.index[a] < h.index[b]
+}
+
+func (h *logSourceHeap) Swap(i, j int) { h.sources[i], h.sources[j] = h.sources[j], h.sources[i] }
+
+func (h *logSourceHeap) Push(x interface{}) { h.sources = append(h.sources, x.(*logSource)) }
+
+func (h *logSourceHeap) Pop() interface{} {
+	last := h.sources[len(h.sources)-1]
+	h.sources = h.sources[:len(h.sources)-1]
+	return last
+}
+
+// mergeLogSources merges the entries of the sources in order of their timestamps and sends the ones that pass the
+// options to send, in chunks of up to logChunkSize entries. All sources are closed when it returns.
+// Entries without a timestamp use the timestamp of the entry before them. If there is none, the time window does not apply to them.
+// If send returns false, merging stops.
+func mergeLogSources(sources []*logSource, opts *WorkflowExecutionLogsOptions, matches func(content string) bool, send func([]*LogEntry) bool) {
+	defer func() {
+		for _, source := range sources {
+			source.close()
+		}
+	}()
+
+	h := &logSourceHeap{
+		sources: make([]*logSource, 0, len(sources)),
+		index:   make(map[*logSource]int),
+	}
+	for i, source := range sources {
+		h.index[source] = i
+		if source.next() {
+			h.sources = append(h.sources, source)
+		}
+	}
+	heap.Init(h)
+
+	// With TailLines, a ring buffer keeps the last entries until all sources are read
+	var tail []*LogEntry
+	tailStart := 0
+	chunk := make([]*LogEntry, 0, logChunkSize)
+
+	for h.Len() > 0 {
+		source := h.sources[0]
+		entry := source.current
+
+		inWindow := true
+		if !entry.Timestamp.IsZero() {
+			if opts.Since != nil && entry.Timestamp.Before(*opts.Since) {
+				inWindow = false
+			}
+			if opts.Until != nil && entry.Timestamp.After(*opts.Until) {
+				inWindow = false
+			}
+		}
+
+		if inWindow && matches(entry.Content) {
+			if opts.TailLines > 0 {
+				if len(tail) < opts.TailLines {
+					tail = append(tail, entry)
+				} else {
+					tail[tailStart] = entry
+					tailStart = (tailStart + 1) % opts.TailLines
+				}
+			} else {
+				chunk = append(chunk, entry)
+				if len(chunk) == logChunkSize {
+					if !send(chunk) {
+						return
+					}
+					chunk = make([]*LogEntry, 0, logChunkSize)
+				}
+			}
+		}
+
+		if source.next() {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	if opts.TailLines > 0 {
+		chunk = append(tail[tailStart:], tail[:tailStart]...)
+		for len(chunk) > logChunkSize {
+			if !send(chunk[:logChunkSize]) {
+				return
+			}
+			chunk = chunk[logChunkSize:]
+		}
+	}
+
+	if len(chunk) > 0 {
+		send(chunk)
+	}
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testLogSources() []*logSource {
+	return []*logSource{
+		newLogSource("pod-a", "main", ioutil.NopCloser(strings.NewReader(
+			"2020-12-01T10:00:00Z starting\n"+
+				"2020-12-01T10:00:02Z step 1\n"+
+				"continued\n"+
+				"2020-12-01T10:00:05Z error: out of memory\n"))),
+		newLogSource("pod-b", "main", ioutil.NopCloser(strings.NewReader(
+			"2020-12-01T10:00:01Z loading\n"+
+				"2020-12-01T10:00:03Z step 2\n"))),
+		newLogSource("pod-b", "sidecar", ioutil.NopCloser(strings.NewReader(""))),
+	}
+}
+
+func mergeTestLogSources(t *testing.T, opts *WorkflowExecutionLogsOptions) []string {
+	matches, err := opts.logEntryMatcher()
+	assert.Nil(t, err)
+
+	result := make([]string, 0)
+	mergeLogSources(testLogSources(), opts, matches, func(chunk []*LogEntry) bool {
+		for _, entry := range chunk {
+			result = append(result, entry.PodName+"/"+entry.ContainerName+" "+entry.Content)
+		}
+		return true
+	})
+
+	return result
+}
+
+// TestMergeLogSources makes sure entries of all sources are ordered by timestamp and keep their pod and container
+func TestMergeLogSources(t *testing.T) {
+	result := mergeTestLogSources(t, &WorkflowExecutionLogsOptions{})
+	assert.Equal(t, []string{
+		"pod-a/main starting",
+		"pod-b/main loading",
+		"pod-a/main step 1",
+		"pod-a/main continued",
+		"pod-b/main step 2",
+		"pod-a/main error: out of memory",
+	}, result)
+}
+
+// TestMergeLogSources_Filter makes sure substring and regex filters are applied
+func TestMergeLogSources_Filter(t *testing.T) {
+	result := mergeTestLogSources(t, &WorkflowExecutionLogsOptions{Filter: "step"})
+	assert.Equal(t, []string{"pod-a/main step 1", "pod-b/main step 2"}, result)
+
+	result = mergeTestLogSources(t, &WorkflowExecutionLogsOptions{Filter: "^(error|loading)", Regex: true})
+	assert.Equal(t, []string{"pod-b/main loading", "pod-a/main error: out of memory"}, result)
+
+	_, err := (&WorkflowExecutionLogsOptions{Filter: "(", Regex: true}).logEntryMatcher()
+	assert.NotNil(t, err)
+}
+
+// TestMergeLogSources_Window makes sure since and until are inclusive and entries without a timestamp use the previous one
+func TestMergeLogSources_Window(t *testing.T) {
+	since := time.Date(2020, 12, 1, 10, 0, 2, 0, time.UTC)
+	until := time.Date(2020, 12, 1, 10, 0, 3, 0, time.UTC)
+
+	result := mergeTestLogSources(t, &WorkflowExecutionLogsOptions{Since: &since, Until: &until})
+	assert.Equal(t, []string{"pod-a/main step 1", "pod-a/main continued", "pod-b/main step 2"}, result)
+}
+
+// TestMergeLogSources_Tail makes sure only the last entries are returned, in order
+func TestMergeLogSources_Tail(t *testing.T) {
+	result := mergeTestLogSources(t, &WorkflowExecutionLogsOptions{TailLines: 2})
+	assert.Equal(t, []string{"pod-b/main step 2", "pod-a/main error: out of memory"}, result)
+
+	result = mergeTestLogSources(t, &WorkflowExecutionLogsOptions{TailLines: 100})
+	assert.Len(t, result, 6)
+}
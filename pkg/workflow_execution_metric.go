@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"strconv"
+	"time"
+)
+
+// getWorkflowExecutionID returns the database id of the workflow execution identified by (namespace, uid).
+// Archived workflow executions are only found if includeArchived is true, for reads.
+func (c *Client) getWorkflowExecutionID(namespace, uid string, includeArchived bool) (id uint64, err error) {
+	whereConditions := sq.Eq{
+		"namespace": namespace,
+		"uid":       uid,
+	}
+	if !includeArchived {
+		whereConditions["is_archived"] = false
+	}
+
+	err = sb.Select("id").
+		From("workflow_executions").
+		Where(whereConditions).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, util.NewUserError(codes.NotFound, "Workflow execution not found.")
+	}
+
+	return
+}
+
+// AddWorkflowExecutionMetricPoints stores a batch of metric points of the workflow execution identified by (namespace, uid),
+// like the ones logged by training code at every step. A point replaces any existing point with the same name and step.
+// Points without a timestamp get the current time.
+// The value of the highest stored step of each metric in the batch is also merged into the metrics of the workflow execution.
+func (c *Client) AddWorkflowExecutionMetricPoints(namespace, uid string, points []*MetricPoint) (metrics Metrics, err error) {
+	if len(points) == 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "At least one metric point is required.")
+	}
+	if len(points) > metricPointsBatchLimit {
+		return nil, util.NewUserError(codes.InvalidArgument, "At most "+strconv.Itoa(metricPointsBatchLimit)+" metric points can be added at once.")
+	}
+
+	now := time.Now().UTC()
+	for _, point := range points {
+		if point.Name == "" {
+			return nil, util.NewUserError(codes.InvalidArgument, "Metric point names can't be empty.")
+		}
+		if !isFinite(point.Value) {
+			return nil, util.NewUserError(codes.InvalidArgument, "Metric point values must be finite numbers.")
+		}
+		if point.Timestamp.IsZero() {
+			point.Timestamp = now
+		}
+	}
+	points = uniqueMetricPoints(points)
+
+	workflowExecutionID, err := c.getWorkflowExecutionID(namespace, uid, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(points); start += metricPointsInsertSize {
+		end := start + metricPointsInsertSize
+		if end > len(points) {
+			end = len(points)
+		}
+
+		insert := sb.Insert("workflow_execution_metric_points").
+			Columns("workflow_execution_id", "name", "step", "value", "timestamp")
+		for _, point := range points[start:end] {
+			insert = insert.Values(workflowExecutionID, point.Name, point.Step, point.Value, point.Timestamp.UTC())
+		}
+
+		_, err = insert.Suffix(`ON CONFLICT (workflow_execution_id, name, step) DO UPDATE SET
+			value = EXCLUDED.value,
+			timestamp = EXCLUDED.timestamp`).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"UID":       uid,
+				"Error":     err.Error(),
+			}).Error("Unable to insert metric points.")
+			return nil, util.NewUserError(codes.Internal, "Error adding metric points.")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// The latest values are read from all of the stored points, as the batch may have older steps
+	names := make([]string, 0)
+	for _, point := range latestMetrics(points) {
+		names = append(names, point.Name)
+	}
+	query := sb.Select("DISTINCT ON (name) name", "step", "value", "timestamp").
+		From("workflow_execution_metric_points").
+		Where(sq.Eq{
+			"workflow_execution_id": workflowExecutionID,
+			"name":                  names,
+		}).
+		OrderBy("name", "step DESC")
+
+	storedPoints := make([]*MetricPoint, 0)
+	if err = c.DB.Selectx(&storedPoints, query); err != nil {
+		return nil, err
+	}
+
+	workflowExecution, err := c.AddWorkflowExecutionMetrics(namespace, uid, latestMetrics(storedPoints), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflowExecution.Metrics, nil
+}
+
+// ListWorkflowExecutionMetricSeries returns the series of metric points of the workflow execution identified by (namespace, uid),
+// ordered by metric name. Series with more points than opts.MaxPoints are downsampled.
+func (c *Client) ListWorkflowExecutionMetricSeries(namespace, uid string, opts *ListMetricSeriesOptions) (series []*MetricSeries, err error) {
+	if opts == nil {
+		opts = &ListMetricSeriesOptions{}
+	}
+	if opts.MaxPoints < 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "Max points can't be negative.")
+	}
+	maxPoints := opts.MaxPoints
+	if maxPoints == 0 {
+		maxPoints = defaultMetricSeriesMaxPoints
+	}
+
+	workflowExecutionID, err := c.getWorkflowExecutionID(namespace, uid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	whereConditions := sq.Eq{
+		"workflow_execution_id": workflowExecutionID,
+	}
+	if len(opts.Names) > 0 {
+		whereConditions["name"] = opts.Names
+	}
+
+	query := sb.Select("name", "step", "value", "value min", "value max", "timestamp").
+		From("workflow_execution_metric_points").
+		Where(whereConditions).
+		OrderBy("name", "step")
+
+	points := make([]*MetricPoint, 0)
+	if err = c.DB.Selectx(&points, query); err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Unable to select metric points.")
+		return nil, util.NewUserError(codes.Internal, "Error getting metric points.")
+	}
+
+	series = groupMetricSeries(points)
+	for _, item := range series {
+		item.Points = downsampleMetricPoints(item.Points, maxPoints)
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestClient_AddWorkflowExecutionMetricPoints_Backfill makes sure a batch of older steps doesn't replace the latest value
+func TestClient_AddWorkflowExecutionMetricPoints_Backfill(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	metrics, err := c.AddWorkflowExecutionMetricPoints(namespace, we.UID, []*MetricPoint{
+		{Name: "loss", Step: 10, Value: 0.1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.1, metrics[0].Value)
+
+	metrics, err = c.AddWorkflowExecutionMetricPoints(namespace, we.UID, []*MetricPoint{
+		{Name: "loss", Step: 1, Value: 0.9},
+		{Name: "loss", Step: 2, Value: 0.8},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, 0.1, metrics[0].Value)
+
+	// Archived workflow executions can still be read
+	assert.Nil(t, c.ArchiveWorkflowExecution(namespace, we.UID))
+	series, err := c.ListWorkflowExecutionMetricSeries(namespace, we.UID, nil)
+	assert.Nil(t, err)
+	assert.Len(t, series, 1)
+	assert.Len(t, series[0].Points, 3)
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// metricPointsBatchLimit is the maximum number of metric points that can be added at once
+	metricPointsBatchLimit = 10000
+	// metricPointsInsertSize is the number of metric points inserted per statement, to stay under the postgres parameter limit
+	metricPointsInsertSize = 1000
+	// defaultMetricSeriesMaxPoints is the number of points a metric series is downsampled to if no maximum is given
+	defaultMetricSeriesMaxPoints = 500
+)
+
+// MetricPoint is the value of a metric at a step of a workflow execution, like the loss of a training epoch.
+// Downsampled points summarize multiple points: Value is their mean, Min and Max are their extremes,
+// and Step and Timestamp are the ones of the last point.
+type MetricPoint struct {
+	Name      string
+	Step      int64
+	Value     float64
+	Min       float64
+	Max       float64
+	Timestamp time.Time
+}
+
+// MetricSeries is the points of a single metric, ordered by step.
+type MetricSeries struct {
+	Name   string
+	Points []*MetricPoint
+}
+
+// ListMetricSeriesOptions are the options to get the metric series of a workflow execution.
+type ListMetricSeriesOptions struct {
+	// Names limits the series to the given metrics. If empty, all metrics are returned.
+	Names []string
+	// MaxPoints is the maximum number of points of each series. Longer series are downsampled.
+	MaxPoints int
+}
+
+// uniqueMetricPoints returns the points with only the last one of each (name, step), in their original order.
+// Postgres can't update the same row twice in one upsert.
+func uniqueMetricPoints(points []*MetricPoint) []*MetricPoint {
+	type key struct {
+		name string
+		step int64
+	}
+
+	last := make(map[key]int)
+	for i, point := range points {
+		last[key{point.Name, point.Step}] = i
+	}
+
+	result := make([]*MetricPoint, 0, len(last))
+	for i, point := range points {
+		if last[key{point.Name, point.Step}] == i {
+			result = append(result, point)
+		}
+	}
+
+	return result
+}
+
+// latestMetrics returns a Metric for each metric name, with the value of the point with the highest step.
+func latestMetrics(points []*MetricPoint) Metrics {
+	latest := make(map[string]*MetricPoint)
+	names := make([]string, 0)
+	for _, point := range points {
+		current, ok := latest[point.Name]
+		if !ok {
+			names = append(names, point.Name)
+		}
+		if !ok || point.Step >= current.Step {
+			latest[point.Name] = point
+		}
+	}
+
+	result := make(Metrics, 0, len(names))
+	for _, name := range names {
+		result = append(result, &Metric{
+			Name:  name,
+			Value: latest[name].Value,
+		})
+	}
+
+	return result
+}
+
+// isFinite returns false if value is NaN or infinite
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// groupMetricSeries groups points ordered by name and step into series
+func groupMetricSeries(points []*MetricPoint) []*MetricSeries {
+	result := make([]*MetricSeries, 0)
+	var series *MetricSeries
+	for _, point := range points {
+		if series == nil || series.Name != point.Name {
+			series = &MetricSeries{
+				Name:   point.Name,
+				Points: make([]*MetricPoint, 0),
+			}
+			result = append(result, series)
+		}
+		series.Points = append(series.Points, point)
+	}
+
+	return result
+}
+
+// downsampleMetricPoints reduces points ordered by step to at most maxPoints.
+// The points are split into maxPoints buckets of consecutive points, each summarized by a single point.
+// If there are no more than maxPoints points, they are returned as they are.
+func downsampleMetricPoints(points []*MetricPoint, maxPoints int) []*MetricPoint {
+	if maxPoints <= 0 || len(points) <= maxPoints {
+		return points
+	}
+
+	result := make([]*MetricPoint, 0, maxPoints)
+	for bucket := 0; bucket < maxPoints; bucket++ {
+		start := bucket * len(points) / maxPoints
+		end := (bucket + 1) * len(points) / maxPoints
+		if start == end {
+			continue
+		}
+
+		last := points[end-1]
+		summary := &MetricPoint{
+			Name:      last.Name,
+			Step:      last.Step,
+			Min:       points[start].Min,
+			Max:       points[start].Max,
+			Timestamp: last.Timestamp,
+		}
+		sum := 0.0
+		for _, point := range points[start:end] {
+			sum += point.Value
+			summary.Min = math.Min(summary.Min, point.Min)
+			summary.Max = math.Max(summary.Max, point.Max)
+		}
+		summary.Value = sum / float64(end-start)
+
+		result = append(result, summary)
+	}
+
+	return result
+}
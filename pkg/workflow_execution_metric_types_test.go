@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testMetricPoints(name string, values ...float64) []*MetricPoint {
+	points := make([]*MetricPoint, 0)
+	for i, value := range values {
+		points = append(points, &MetricPoint{
+			Name:  name,
+			Step:  int64(i + 1),
+			Value: value,
+			Min:   value,
+			Max:   value,
+		})
+	}
+
+	return points
+}
+
+// TestUniqueMetricPoints makes sure the last point of each name and step is kept
+func TestUniqueMetricPoints(t *testing.T) {
+	points := []*MetricPoint{
+		{Name: "loss", Step: 1, Value: 1},
+		{Name: "accuracy", Step: 1, Value: 0.5},
+		{Name: "loss", Step: 1, Value: 2},
+		{Name: "loss", Step: 2, Value: 3},
+	}
+
+	result := uniqueMetricPoints(points)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "accuracy", result[0].Name)
+	assert.Equal(t, 2.0, result[1].Value)
+	assert.Equal(t, 3.0, result[2].Value)
+}
+
+// TestLatestMetrics makes sure the value of the highest step of each metric is used
+func TestLatestMetrics(t *testing.T) {
+	points := []*MetricPoint{
+		{Name: "loss", Step: 2, Value: 0.5},
+		{Name: "accuracy", Step: 1, Value: 0.7},
+		{Name: "loss", Step: 1, Value: 0.9},
+	}
+
+	result := latestMetrics(points)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "loss", result[0].Name)
+	assert.Equal(t, 0.5, result[0].Value)
+	assert.Equal(t, "accuracy", result[1].Name)
+	assert.Equal(t, 0.7, result[1].Value)
+}
+
+// TestGroupMetricSeries makes sure points ordered by name are split into series
+func TestGroupMetricSeries(t *testing.T) {
+	points := append(testMetricPoints("accuracy", 0.1, 0.2), testMetricPoints("loss", 3, 2, 1)...)
+
+	result := groupMetricSeries(points)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "accuracy", result[0].Name)
+	assert.Len(t, result[0].Points, 2)
+	assert.Equal(t, "loss", result[1].Name)
+	assert.Len(t, result[1].Points, 3)
+
+	assert.Len(t, groupMetricSeries(nil), 0)
+}
+
+// TestDownsampleMetricPoints makes sure buckets are summarized by their mean, min, max and last step
+func TestDownsampleMetricPoints(t *testing.T) {
+	points := testMetricPoints("loss", 4, 2, 6, 8, 1, 3, 5)
+
+	assert.Equal(t, points, downsampleMetricPoints(points, 7))
+	assert.Equal(t, points, downsampleMetricPoints(points, 0))
+
+	result := downsampleMetricPoints(points, 3)
+	assert.Len(t, result, 3)
+
+	// Buckets are points [0, 2), [2, 4) and [4, 7)
+	assert.Equal(t, int64(2), result[0].Step)
+	assert.Equal(t, 3.0, result[0].Value)
+	assert.Equal(t, 2.0, result[0].Min)
+	assert.Equal(t, 4.0, result[0].Max)
+
+	assert.Equal(t, int64(4), result[1].Step)
+	assert.Equal(t, 7.0, result[1].Value)
+
+	assert.Equal(t, int64(7), result[2].Step)
+	assert.Equal(t, 3.0, result[2].Value)
+	assert.Equal(t, 1.0, result[2].Min)
+	assert.Equal(t, 5.0, result[2].Max)
+
+	// Downsampling again keeps the extremes of the original points
+	result = downsampleMetricPoints(result, 1)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 1.0, result[0].Min)
+	assert.Equal(t, 8.0, result[0].Max)
+}
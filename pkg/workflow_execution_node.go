@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"time"
+)
+
+// SyncWorkflowExecutionNodes stores the nodes in the status of the argo workflow in the workflow_execution_nodes table.
+// Nodes that are no longer part of the workflow, like the ones removed by a retry, are deleted.
+// The artifacts the nodes read and wrote are stored for the lineage of the workflow execution.
+func (c *Client) SyncWorkflowExecutionNodes(wf *wfv1.Workflow) (err error) {
+	if len(wf.Status.Nodes) == 0 {
+		return nil
+	}
+
+	workflowExecutionID := uint64(0)
+	err = sb.Select("id").
+		From("workflow_executions").
+		Where(sq.Eq{
+			"namespace": wf.Namespace,
+			"uid":       wf.Name,
+		}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&workflowExecutionID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	nodeIDs := make([]string, 0)
+	insert := sb.Insert("workflow_execution_nodes").
+		Columns("workflow_execution_id", "node_id", "name", "display_name", "type", "template_name", "phase",
+			"message", "boundary_id", "pod_name", "inputs", "outputs", "children", "exit_code", "started_at", "finished_at")
+	for _, status := range wf.Status.Nodes {
+		node, err := workflowExecutionNodeFromStatus(workflowExecutionID, &status)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(node.WorkflowExecutionID, node.NodeID, node.Name, node.DisplayName, node.Type, node.TemplateName, node.Phase,
+			node.Message, node.BoundaryID, node.PodName, string(node.Inputs), string(node.Outputs), string(node.ChildrenBytes), node.ExitCode, node.StartedAt, node.FinishedAt)
+		nodeIDs = append(nodeIDs, node.NodeID)
+	}
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = insert.Suffix(`ON CONFLICT (workflow_execution_id, node_id) DO UPDATE SET
+		name = EXCLUDED.name,
+		display_name = EXCLUDED.display_name,
+		type = EXCLUDED.type,
+		template_name = EXCLUDED.template_name,
+		phase = EXCLUDED.phase,
+		message = EXCLUDED.message,
+		boundary_id = EXCLUDED.boundary_id,
+		pod_name = EXCLUDED.pod_name,
+		inputs = EXCLUDED.inputs,
+		outputs = EXCLUDED.outputs,
+		children = EXCLUDED.children,
+		exit_code = EXCLUDED.exit_code,
+		started_at = EXCLUDED.started_at,
+		finished_at = EXCLUDED.finished_at,
+		modified_at = ?`, time.Now().UTC()).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	_, err = sb.Delete("workflow_execution_nodes").
+		Where(sq.And{
+			sq.Eq{"workflow_execution_id": workflowExecutionID},
+			sq.NotEq{"node_id": nodeIDs},
+		}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	if err := syncWorkflowExecutionArtifacts(tx, workflowExecutionID, wf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// selectWorkflowExecutionNodes returns the stored nodes of the workflow execution identified by (namespace, uid)
+func (c *Client) selectWorkflowExecutionNodes(namespace, uid string) (nodes []*WorkflowExecutionNode, err error) {
+	query := sb.Select(getWorkflowExecutionNodeColumns("wen")...).
+		From("workflow_execution_nodes wen").
+		Join("workflow_executions we ON we.id = wen.workflow_execution_id").
+		Where(sq.Eq{
+			"we.namespace": namespace,
+			"we.uid":       uid,
+		}).
+		OrderBy("wen.started_at", "wen.id")
+
+	nodes = make([]*WorkflowExecutionNode, 0)
+	err = c.DB.Selectx(&nodes, query)
+
+	return
+}
+
+// ListWorkflowExecutionNodes returns the nodes of the workflow execution identified by (namespace, uid)
+// and the edges between them.
+// If no nodes were stored yet, they are synced from the argo workflow, if it still exists.
+func (c *Client) ListWorkflowExecutionNodes(namespace, uid string) (nodes []*WorkflowExecutionNode, edges []*WorkflowExecutionNodeEdge, err error) {
+	nodes, err = c.selectWorkflowExecutionNodes(namespace, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(nodes) == 0 {
+		wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"UID":       uid,
+				"Error":     err.Error(),
+			}).Error("Unable to get workflow.")
+			return nil, nil, util.NewUserError(codes.Unknown, "Unable to get workflow.")
+		}
+
+		if err == nil {
+			if err := c.SyncWorkflowExecutionNodes(wf); err != nil {
+				return nil, nil, err
+			}
+
+			nodes, err = c.selectWorkflowExecutionNodes(namespace, uid)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	edges, err = WorkflowExecutionNodeEdges(nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return
+}
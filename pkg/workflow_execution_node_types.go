@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"encoding/json"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util/sql"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var reNodeExitCode = regexp.MustCompile(`exit code (\d+)`)
+
+// WorkflowExecutionNode is a record of a single node (step) of a workflow execution.
+// It is kept after the argo workflow is removed.
+type WorkflowExecutionNode struct {
+	ID                  uint64
+	WorkflowExecutionID uint64 `db:"workflow_execution_id"`
+	NodeID              string `db:"node_id"`
+	Name                string
+	DisplayName         string `db:"display_name"`
+	Type                string
+	TemplateName        string `db:"template_name"`
+	Phase               wfv1.NodePhase
+	Message             string
+	BoundaryID          string     `db:"boundary_id"`
+	PodName             string     `db:"pod_name"`
+	Inputs              []byte     // json encoded wfv1.Inputs
+	Outputs             []byte     // json encoded wfv1.Outputs
+	ChildrenBytes       []byte     `db:"children"` // to load from database
+	ExitCode            *int       `db:"exit_code"`
+	StartedAt           *time.Time `db:"started_at"`
+	FinishedAt          *time.Time `db:"finished_at"`
+	CreatedAt           time.Time  `db:"created_at"`
+	ModifiedAt          *time.Time `db:"modified_at"`
+}
+
+// WorkflowExecutionNodeEdge is a directed edge between two nodes of a workflow execution graph.
+type WorkflowExecutionNodeEdge struct {
+	From string
+	To   string
+}
+
+// getNodeExitCode returns the exit code of a pod node.
+// Argo only reports the exit code in the message of failed nodes, so succeeded pods are assumed to exit with 0.
+func getNodeExitCode(node *wfv1.NodeStatus) *int {
+	if node.Type != wfv1.NodeTypePod {
+		return nil
+	}
+
+	if node.Phase == wfv1.NodeSucceeded {
+		exitCode := 0
+		return &exitCode
+	}
+
+	matches := reNodeExitCode.FindStringSubmatch(node.Message)
+	if len(matches) != 2 {
+		return nil
+	}
+
+	exitCode, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil
+	}
+
+	return &exitCode
+}
+
+// workflowExecutionNodeFromStatus creates a WorkflowExecutionNode from the argo node status.
+func workflowExecutionNodeFromStatus(workflowExecutionID uint64, node *wfv1.NodeStatus) (*WorkflowExecutionNode, error) {
+	inputs := []byte("{}")
+	if node.Inputs != nil {
+		inputsBytes, err := json.Marshal(node.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		inputs = inputsBytes
+	}
+
+	outputs := []byte("{}")
+	if node.Outputs != nil {
+		outputsBytes, err := json.Marshal(node.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		outputs = outputsBytes
+	}
+
+	children := node.Children
+	if children == nil {
+		children = make([]string, 0)
+	}
+	childrenBytes, err := json.Marshal(children)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WorkflowExecutionNode{
+		WorkflowExecutionID: workflowExecutionID,
+		NodeID:              node.ID,
+		Name:                node.Name,
+		DisplayName:         node.DisplayName,
+		Type:                string(node.Type),
+		TemplateName:        node.TemplateName,
+		Phase:               node.Phase,
+		Message:             node.Message,
+		BoundaryID:          node.BoundaryID,
+		Inputs:              inputs,
+		Outputs:             outputs,
+		ChildrenBytes:       childrenBytes,
+		ExitCode:            getNodeExitCode(node),
+	}
+
+	// For pod nodes, argo names the pod after the node id
+	if node.Type == wfv1.NodeTypePod {
+		result.PodName = node.ID
+	}
+	if !node.StartedAt.IsZero() {
+		startedAt := node.StartedAt.UTC()
+		result.StartedAt = &startedAt
+	}
+	if !node.FinishedAt.IsZero() {
+		finishedAt := node.FinishedAt.UTC()
+		result.FinishedAt = &finishedAt
+	}
+
+	return result, nil
+}
+
+// nodeStatus returns the argo node status of the node, without its inputs, outputs and children
+func (n *WorkflowExecutionNode) nodeStatus() wfv1.NodeStatus {
+	status := wfv1.NodeStatus{
+		ID:           n.NodeID,
+		Name:         n.Name,
+		DisplayName:  n.DisplayName,
+		Type:         wfv1.NodeType(n.Type),
+		TemplateName: n.TemplateName,
+		Phase:        n.Phase,
+		Message:      n.Message,
+		BoundaryID:   n.BoundaryID,
+	}
+	if n.StartedAt != nil {
+		status.StartedAt = metav1.NewTime(*n.StartedAt)
+	}
+	if n.FinishedAt != nil {
+		status.FinishedAt = metav1.NewTime(*n.FinishedAt)
+	}
+
+	return status
+}
+
+// GetChildren returns the node ids of the children of the node
+func (n *WorkflowExecutionNode) GetChildren() ([]string, error) {
+	children := make([]string, 0)
+	if len(n.ChildrenBytes) == 0 {
+		return children, nil
+	}
+
+	if err := json.Unmarshal(n.ChildrenBytes, &children); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// WorkflowExecutionNodeEdges returns the edges of the graph made up of the nodes.
+// An edge goes from a node to each of its children. Children that are not part of nodes are ignored.
+func WorkflowExecutionNodeEdges(nodes []*WorkflowExecutionNode) (edges []*WorkflowExecutionNodeEdge, err error) {
+	nodeIDs := make(map[string]bool)
+	for _, node := range nodes {
+		nodeIDs[node.NodeID] = true
+	}
+
+	edges = make([]*WorkflowExecutionNodeEdge, 0)
+	for _, node := range nodes {
+		children, err := node.GetChildren()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			if !nodeIDs[child] {
+				continue
+			}
+
+			edges = append(edges, &WorkflowExecutionNodeEdge{
+				From: node.NodeID,
+				To:   child,
+			})
+		}
+	}
+
+	return
+}
+
+// getWorkflowExecutionNodeColumns returns all of the columns for workflowExecutionNode modified by alias, destination.
+// see formatColumnSelect
+func getWorkflowExecutionNodeColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"workflow_execution_id",
+		"node_id",
+		"name",
+		"display_name",
+		"type",
+		"template_name",
+		"phase",
+		"message",
+		"boundary_id",
+		"pod_name",
+		"inputs",
+		"outputs",
+		"children",
+		"exit_code",
+		"started_at",
+		"finished_at",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+// TestGetNodeExitCode makes sure the exit code is only returned for pod nodes when it is known
+func TestGetNodeExitCode(t *testing.T) {
+	exitCode := getNodeExitCode(&wfv1.NodeStatus{
+		Type:  wfv1.NodeTypePod,
+		Phase: wfv1.NodeSucceeded,
+	})
+	assert.NotNil(t, exitCode)
+	assert.Equal(t, 0, *exitCode)
+
+	exitCode = getNodeExitCode(&wfv1.NodeStatus{
+		Type:    wfv1.NodeTypePod,
+		Phase:   wfv1.NodeFailed,
+		Message: "failed with exit code 137",
+	})
+	assert.NotNil(t, exitCode)
+	assert.Equal(t, 137, *exitCode)
+
+	exitCode = getNodeExitCode(&wfv1.NodeStatus{
+		Type:    wfv1.NodeTypePod,
+		Phase:   wfv1.NodeError,
+		Message: "pod deleted",
+	})
+	assert.Nil(t, exitCode)
+
+	exitCode = getNodeExitCode(&wfv1.NodeStatus{
+		Type:  wfv1.NodeTypeDAG,
+		Phase: wfv1.NodeSucceeded,
+	})
+	assert.Nil(t, exitCode)
+}
+
+// TestWorkflowExecutionNodeEdges makes sure edges are created from the children of the nodes
+func TestWorkflowExecutionNodeEdges(t *testing.T) {
+	nodes := []*WorkflowExecutionNode{
+		{NodeID: "a", ChildrenBytes: []byte(`["b", "c"]`)},
+		{NodeID: "b", ChildrenBytes: []byte(`["d"]`)},
+		{NodeID: "c", ChildrenBytes: []byte(`["missing"]`)},
+		{NodeID: "d"},
+	}
+
+	edges, err := WorkflowExecutionNodeEdges(nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, []*WorkflowExecutionNodeEdge{
+		{From: "a", To: "b"},
+		{From: "a", To: "c"},
+		{From: "b", To: "d"},
+	}, edges)
+}
+
+// TestWorkflowExecutionNode_NodeStatus makes sure a stored node gives back the status it was stored from
+func TestWorkflowExecutionNode_NodeStatus(t *testing.T) {
+	startedAt := metav1.NewTime(time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC))
+	status := wfv1.NodeStatus{
+		ID:           "train-1234",
+		Name:         "train.main",
+		DisplayName:  "main",
+		Type:         wfv1.NodeTypePod,
+		TemplateName: "main",
+		Phase:        wfv1.NodeRunning,
+		BoundaryID:   "train",
+		StartedAt:    startedAt,
+	}
+
+	node, err := workflowExecutionNodeFromStatus(1, &status)
+	assert.Nil(t, err)
+	assert.Equal(t, status, node.nodeStatus())
+	assert.False(t, node.nodeStatus().Completed())
+}
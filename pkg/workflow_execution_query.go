@@ -0,0 +1,380 @@
+package v1
+
+import (
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// workflowExecutionQueryMaxLength is the maximum length of a workflow execution filter expression
+	workflowExecutionQueryMaxLength = 2000
+	// workflowExecutionQueryMaxDepth is the maximum nesting of a workflow execution filter expression
+	workflowExecutionQueryMaxDepth = 32
+)
+
+// reQueryFieldName matches the names of metrics and parameters that can be used without quotes, and in sort properties
+var reQueryFieldName = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+
+// queryOperators are the supported comparison operators, mapped to their sql version.
+// Longer operators are first so they are matched before their prefixes.
+var queryOperators = []struct {
+	token string
+	sql   string
+}{
+	{"==", "="},
+	{"!=", "<>"},
+	{"<>", "<>"},
+	{"<=", "<="},
+	{">=", ">="},
+	{"=", "="},
+	{"<", "<"},
+	{">", ">"},
+}
+
+// notExpr negates a sql expression
+type notExpr struct {
+	expr sq.Sqlizer
+}
+
+// ToSql returns the sql of the negated expression
+func (n notExpr) ToSql() (string, []interface{}, error) {
+	sql, args, err := n.expr.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "NOT (" + sql + ")", args, nil
+}
+
+// jsonbArrayElements returns the sql for the elements of a JSONB array column. Columns that are not arrays, like
+// the '{}' metrics of old workflow executions, have no elements.
+func jsonbArrayElements(column string) string {
+	return fmt.Sprintf("jsonb_array_elements(CASE WHEN jsonb_typeof(%v) = 'array' THEN %v ELSE '[]'::jsonb END)", column, column)
+}
+
+// workflowExecutionMetricSQL returns the sql for the value of a metric of the workflow execution, or NULL if it does not have it.
+// nameSQL is the sql of the name of the metric, like a placeholder.
+func workflowExecutionMetricSQL(nameSQL string) string {
+	return fmt.Sprintf(`(SELECT (m->>'Value')::double precision FROM %v m WHERE m->>'Name' = %v LIMIT 1)`, jsonbArrayElements("we.metrics"), nameSQL)
+}
+
+// workflowExecutionParameterSQL returns the sql for the value of a parameter of the workflow execution, or NULL if it does not have it.
+// If numeric is true, the value is a number, or NULL if the parameter is not a number.
+// nameSQL is the sql of the name of the parameter, like a placeholder.
+func workflowExecutionParameterSQL(nameSQL string, numeric bool) string {
+	if !numeric {
+		return fmt.Sprintf(`(SELECT p->>'value' FROM %v p WHERE p->>'name' = %v LIMIT 1)`, jsonbArrayElements("we.parameters"), nameSQL)
+	}
+
+	// The pattern avoids "?", which is a placeholder for squirrel
+	return fmt.Sprintf(`(SELECT CASE WHEN p->>'value' ~ '^\s*-{0,1}(\d+\.{0,1}\d*|\.\d+)([eE][-+]{0,1}\d+){0,1}\s*$' THEN (p->>'value')::double precision END FROM %v p WHERE p->>'name' = %v LIMIT 1)`, jsonbArrayElements("we.parameters"), nameSQL)
+}
+
+// workflowExecutionQueryParser parses filter expressions of workflow executions into sql. The grammar is:
+//
+//	expression = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expression ")" | comparison
+//	comparison = field operator value
+//	field      = ( "metrics" | "parameters" ) "." ( name | string )
+//	operator   = "=" | "==" | "!=" | "<>" | "<" | "<=" | ">" | ">="
+//	value      = number | string
+//
+// Names are letters, digits, "_", "-" and ".". Strings are in single or double quotes, with "\" escaping the next character.
+// Metrics can only be compared with numbers. Parameters are compared as numbers with numbers, and as text with strings.
+// Workflow executions without the metric or parameter, or where the parameter is not a number, don't match any comparison.
+// Keywords are case-insensitive.
+type workflowExecutionQueryParser struct {
+	input string
+	pos   int
+	depth int
+}
+
+// errorf returns a parse error at the current position
+func (p *workflowExecutionQueryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid filter at position %v: %v", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *workflowExecutionQueryParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *workflowExecutionQueryParser) eof() bool {
+	p.skipSpace()
+	return p.pos >= len(p.input)
+}
+
+// readWord reads the next run of name characters without consuming it if consume is false
+func (p *workflowExecutionQueryParser) readWord(consume bool) string {
+	p.skipSpace()
+	end := p.pos
+	for end < len(p.input) && reQueryFieldName.MatchString(p.input[end:end+1]) {
+		end++
+	}
+
+	word := p.input[p.pos:end]
+	if consume {
+		p.pos = end
+	}
+
+	return word
+}
+
+// keyword consumes the keyword if it is next
+func (p *workflowExecutionQueryParser) keyword(keyword string) bool {
+	word := p.readWord(false)
+	if !strings.EqualFold(word, keyword) {
+		return false
+	}
+
+	p.pos += len(word)
+	return true
+}
+
+// consume consumes token if it is next
+func (p *workflowExecutionQueryParser) consume(token string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.input[p.pos:], token) {
+		return false
+	}
+
+	p.pos += len(token)
+	return true
+}
+
+func (p *workflowExecutionQueryParser) parseExpression() (sq.Sqlizer, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > workflowExecutionQueryMaxDepth {
+		return nil, p.errorf("expression is nested too deeply")
+	}
+
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := sq.Or{first}
+	for p.keyword("or") {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 1 {
+		return first, nil
+	}
+
+	return terms, nil
+}
+
+func (p *workflowExecutionQueryParser) parseAnd() (sq.Sqlizer, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := sq.And{first}
+	for p.keyword("and") {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 1 {
+		return first, nil
+	}
+
+	return terms, nil
+}
+
+func (p *workflowExecutionQueryParser) parseUnary() (sq.Sqlizer, error) {
+	if p.keyword("not") {
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > workflowExecutionQueryMaxDepth {
+			return nil, p.errorf("expression is nested too deeply")
+		}
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{expr}, nil
+	}
+
+	if p.consume("(") {
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseString parses a quoted string
+func (p *workflowExecutionQueryParser) parseString() (string, error) {
+	quote := p.input[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		p.pos++
+
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.input) {
+				return "", p.errorf("unterminated string")
+			}
+			sb.WriteByte(p.input[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *workflowExecutionQueryParser) isQuote() bool {
+	p.skipSpace()
+	return p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"')
+}
+
+func (p *workflowExecutionQueryParser) parseComparison() (sq.Sqlizer, error) {
+	field := strings.ToLower(p.readWord(true))
+	if field == "" {
+		return nil, p.errorf("expected a field")
+	}
+
+	parts := strings.SplitN(field, ".", 2)
+	if len(parts) != 2 || (parts[0] != "metrics" && parts[0] != "parameters") {
+		return nil, p.errorf("unknown field '%v', expected metrics.<name> or parameters.<name>", field)
+	}
+	field = parts[0]
+
+	// Names are part of the word, unless they are quoted
+	name := p.input[p.pos-len(parts[1]) : p.pos]
+	if name == "" && p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		var err error
+		if name, err = p.parseString(); err != nil {
+			return nil, err
+		}
+	}
+	if name == "" {
+		return nil, p.errorf("expected the name of a %v", strings.TrimSuffix(field, "s"))
+	}
+
+	operator := ""
+	p.skipSpace()
+	for _, op := range queryOperators {
+		if p.consume(op.token) {
+			operator = op.sql
+			break
+		}
+	}
+	if operator == "" {
+		return nil, p.errorf("expected a comparison operator")
+	}
+
+	if p.isQuote() {
+		value, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if field == "metrics" {
+			return nil, p.errorf("metrics can only be compared with numbers")
+		}
+
+		return sq.Expr(workflowExecutionParameterSQL("?", false)+" "+operator+" ?", name, value), nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && strings.ContainsRune("0123456789.eE+-", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("expected a number or a string")
+	}
+
+	if field == "metrics" {
+		return sq.Expr(workflowExecutionMetricSQL("?")+" "+operator+" ?", name, value), nil
+	}
+
+	return sq.Expr(workflowExecutionParameterSQL("?", true)+" "+operator+" ?", name, value), nil
+}
+
+// parseWorkflowExecutionQuery parses a filter expression of workflow executions, like "metrics.accuracy > 0.9",
+// into a sql condition. See workflowExecutionQueryParser for the grammar.
+func parseWorkflowExecutionQuery(expression string) (sq.Sqlizer, error) {
+	if len(expression) > workflowExecutionQueryMaxLength {
+		return nil, fmt.Errorf("invalid filter: it can be at most %v characters", workflowExecutionQueryMaxLength)
+	}
+
+	p := &workflowExecutionQueryParser{input: expression}
+	if p.eof() {
+		return nil, fmt.Errorf("invalid filter: it is empty")
+	}
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected '%v'", p.input[p.pos:])
+	}
+
+	return expr, nil
+}
+
+// workflowExecutionJSONBOrderBy returns the order by clauses to sort workflow executions by a metric or parameter,
+// for properties like "metrics.val_loss". ok is false if the property is not a metric or parameter.
+// Executions without the metric or parameter are last. Parameters are sorted as numbers first, then as text.
+func workflowExecutionJSONBOrderBy(property, direction string) (orderBys []string, ok bool, err error) {
+	parts := strings.SplitN(property, ".", 2)
+	if len(parts) != 2 || (parts[0] != "metrics" && parts[0] != "parameters") {
+		return nil, false, nil
+	}
+
+	name := parts[1]
+	if !reQueryFieldName.MatchString(name) {
+		return nil, true, fmt.Errorf("invalid sort property '%v'", property)
+	}
+	if direction != "asc" && direction != "desc" {
+		return nil, true, fmt.Errorf("unknown sort '%v'", direction)
+	}
+
+	// The name only has safe characters, so it can be used as a literal. Order by clauses can't have arguments.
+	nameSQL := "'" + name + "'"
+	if parts[0] == "metrics" {
+		return []string{workflowExecutionMetricSQL(nameSQL) + " " + direction + " NULLS LAST"}, true, nil
+	}
+
+	return []string{
+		workflowExecutionParameterSQL(nameSQL, true) + " " + direction + " NULLS LAST",
+		workflowExecutionParameterSQL(nameSQL, false) + " " + direction + " NULLS LAST",
+	}, true, nil
+}
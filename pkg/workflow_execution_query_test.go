@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// TestParseWorkflowExecutionQuery makes sure comparisons compile to sql with the names and values as arguments
+func TestParseWorkflowExecutionQuery(t *testing.T) {
+	condition, err := parseWorkflowExecutionQuery("metrics.accuracy > 0.9")
+	assert.Nil(t, err)
+	sql, args, err := condition.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, workflowExecutionMetricSQL("?")+" > ?", sql)
+	assert.Equal(t, []interface{}{"accuracy", 0.9}, args)
+
+	condition, err = parseWorkflowExecutionQuery(`parameters."learning rate" >= -1e-3`)
+	assert.Nil(t, err)
+	sql, args, err = condition.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, workflowExecutionParameterSQL("?", true)+" >= ?", sql)
+	assert.Equal(t, []interface{}{"learning rate", -0.001}, args)
+
+	condition, err = parseWorkflowExecutionQuery(`Parameters.optimizer != 'it\'s'`)
+	assert.Nil(t, err)
+	sql, args, err = condition.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, workflowExecutionParameterSQL("?", false)+" <> ?", sql)
+	assert.Equal(t, []interface{}{"optimizer", "it's"}, args)
+}
+
+// TestParseWorkflowExecutionQuery_Logic makes sure and binds tighter than or, and parentheses and not are applied
+func TestParseWorkflowExecutionQuery_Logic(t *testing.T) {
+	condition, err := parseWorkflowExecutionQuery("metrics.a = 1 or metrics.b = 2 AND not (metrics.c<3 or metrics.d>4)")
+	assert.Nil(t, err)
+
+	sql, args, err := condition.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"a", 1.0, "b", 2.0, "c", 3.0, "d", 4.0}, args)
+
+	a := workflowExecutionMetricSQL("?") + " = ?"
+	b := workflowExecutionMetricSQL("?") + " = ?"
+	c := workflowExecutionMetricSQL("?") + " < ?"
+	d := workflowExecutionMetricSQL("?") + " > ?"
+	assert.Equal(t, "("+a+" OR ("+b+" AND NOT (("+c+" OR "+d+"))))", sql)
+}
+
+// TestParseWorkflowExecutionQuery_Invalid makes sure invalid expressions are rejected
+func TestParseWorkflowExecutionQuery_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"labels.a = 1",
+		"metrics = 1",
+		"metrics. = 1",
+		"metrics.a",
+		"metrics.a ~ 1",
+		"metrics.a = 'high'",
+		"metrics.a = abc",
+		"metrics.a = 1 and",
+		"(metrics.a = 1",
+		"metrics.a = 1)",
+		"parameters.a = 'unterminated",
+		"metrics.a = 1; DROP TABLE workflow_executions",
+		strings.Repeat("(", 40) + "metrics.a = 1" + strings.Repeat(")", 40),
+		strings.Repeat("not ", 40) + "metrics.a = 1",
+	}
+
+	for _, test := range tests {
+		_, err := parseWorkflowExecutionQuery(test)
+		assert.NotNil(t, err, test)
+	}
+}
+
+// TestWorkflowExecutionJSONBOrderBy makes sure metrics and parameters can be sorted by, with safe names only
+func TestWorkflowExecutionJSONBOrderBy(t *testing.T) {
+	orderBys, ok, err := workflowExecutionJSONBOrderBy("metrics.val_loss", "asc")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{workflowExecutionMetricSQL("'val_loss'") + " asc NULLS LAST"}, orderBys)
+
+	orderBys, ok, err = workflowExecutionJSONBOrderBy("parameters.epochs", "desc")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Len(t, orderBys, 2)
+
+	_, ok, err = workflowExecutionJSONBOrderBy("createdAt", "desc")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = workflowExecutionJSONBOrderBy("metrics.a' OR 1=1 --", "desc")
+	assert.NotNil(t, err)
+	assert.True(t, ok)
+}
+
+// TestWorkflowExecutionParameterSQL makes sure the name is the only "?", others would be replaced with placeholders by squirrel
+func TestWorkflowExecutionParameterSQL(t *testing.T) {
+	assert.Equal(t, 1, strings.Count(workflowExecutionParameterSQL("?", true), "?"))
+	assert.Equal(t, 1, strings.Count(workflowExecutionParameterSQL("?", false), "?"))
+	assert.Equal(t, 1, strings.Count(workflowExecutionMetricSQL("?"), "?"))
+}
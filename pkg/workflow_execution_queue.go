@@ -0,0 +1,433 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	sq "github.com/Masterminds/squirrel"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"time"
+)
+
+// lockWorkflowExecutionQueue takes the advisory lock of the queue of the namespace for the transaction tx.
+// It makes the concurrency checks and the creation of workflow executions atomic, also across servers,
+// so limits are not exceeded by executions created at the same time. The lock is released when tx ends.
+func lockWorkflowExecutionQueue(tx sq.BaseRunner, namespace string) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", namespace)
+
+	return err
+}
+
+// getWorkflowExecutionConcurrency counts the running workflow executions of the namespace.
+// Executions of system workflow templates, like the ones of workspaces, are not counted.
+func (c *Client) getWorkflowExecutionConcurrency(namespace string) (*workflowExecutionConcurrency, error) {
+	query := sb.Select(`wt.uid "uid"`, `COUNT(*) "count"`).
+		From("workflow_executions we").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{
+			"we.namespace":   namespace,
+			"we.is_archived": false,
+			"we.phase":       workflowExecutionRunningPhases,
+			"wt.is_system":   false,
+		}).
+		GroupBy("wt.uid")
+
+	counts := make([]*struct {
+		UID   string
+		Count int
+	}, 0)
+	if err := c.DB.Selectx(&counts, query); err != nil {
+		return nil, err
+	}
+
+	result := &workflowExecutionConcurrency{
+		RunningByWorkflowTemplate: make(map[string]int),
+	}
+	for _, count := range counts {
+		result.Running += count.Count
+		result.RunningByWorkflowTemplate[count.UID] = count.Count
+	}
+
+	return result, nil
+}
+
+// mustQueueWorkflowExecution returns true if a new execution of the workflow template has to wait in the queue.
+// That is the case if it would go over a limit, or if executions that are held by the same limit are already queued,
+// so they start first.
+func (c *Client) mustQueueWorkflowExecution(namespace string, workflowTemplateID uint64) (bool, error) {
+	workflowTemplate := &WorkflowTemplate{}
+	err := c.DB.Getx(workflowTemplate, sb.Select("uid", "is_system").
+		From("workflow_templates").
+		Where(sq.Eq{"id": workflowTemplateID}))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if workflowTemplate.IsSystem {
+		return false, nil
+	}
+
+	config, err := c.GetNamespaceConcurrencyConfig(namespace)
+	if err != nil {
+		return false, err
+	}
+	if !config.HasLimits() {
+		return false, nil
+	}
+
+	concurrency, err := c.getWorkflowExecutionConcurrency(namespace)
+	if err != nil {
+		return false, err
+	}
+	if concurrency.isFull(config, workflowTemplate.UID) {
+		return true, nil
+	}
+
+	whereQueued := sq.And{
+		sq.Eq{
+			"we.namespace":   namespace,
+			"we.phase":       WorkflowExecutionPhaseQueued,
+			"we.is_archived": false,
+		},
+	}
+	if config.MaxRunning == 0 {
+		whereQueued = append(whereQueued, sq.Eq{"wt.uid": workflowTemplate.UID})
+	}
+
+	queued := 0
+	err = sb.Select("COUNT(*)").
+		From("workflow_executions we").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(whereQueued).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&queued)
+	if err != nil {
+		return false, err
+	}
+
+	return queued > 0, nil
+}
+
+// queueWorkflowExecution inserts the workflow execution in the Queued phase, and stores its argo workflow
+// at the end of the queue of the namespace, using tx. The argo workflow gets its final name, as the name is the uid of the execution.
+func queueWorkflowExecution(tx sq.BaseRunner, namespace string, workflowExecution *WorkflowExecution, wf *wfv1.Workflow) error {
+	if wf.ObjectMeta.Name == "" {
+		wf.ObjectMeta.Name = wf.ObjectMeta.GenerateName + utilrand.String(5)
+		wf.ObjectMeta.GenerateName = ""
+	}
+	wf.ObjectMeta.Namespace = namespace
+
+	manifest, err := json.Marshal(wf)
+	if err != nil {
+		return err
+	}
+
+	workflowExecution.Name = wf.ObjectMeta.Name
+	workflowExecution.CreatedAt = time.Now().UTC()
+	workflowExecution.Phase = WorkflowExecutionPhaseQueued
+	workflowExecution.ArgoWorkflow = wf
+	if err := workflowExecution.GenerateUID(wf.ObjectMeta.Name); err != nil {
+		return err
+	}
+
+	if err := insertWorkflowExecution(tx, namespace, workflowExecution); err != nil {
+		return err
+	}
+
+	_, err = sb.Insert("workflow_execution_queue").
+		SetMap(sq.Eq{
+			"workflow_execution_id": workflowExecution.ID,
+			"namespace":             namespace,
+			"position":              sq.Expr("(SELECT COALESCE(MAX(position), 0) + 1 FROM workflow_execution_queue WHERE namespace = ?)", namespace),
+			"manifest":              string(manifest),
+		}).
+		RunWith(tx).
+		Exec()
+
+	return err
+}
+
+// queuedWorkflowExecutionsSelectBuilder returns a select for the queue of the namespace in order, without the manifests
+func queuedWorkflowExecutionsSelectBuilder(namespace string) sq.SelectBuilder {
+	return sb.Select("q.id", "q.workflow_execution_id", "q.namespace", "q.position", "q.created_at").
+		Columns(
+			`we.uid "workflow_execution.uid"`,
+			`we.name "workflow_execution.name"`,
+			`we.phase "workflow_execution.phase"`,
+			`we.created_at "workflow_execution.created_at"`,
+			`we.labels "workflow_execution.labels"`,
+			`wt.uid "workflow_execution.workflow_template.uid"`,
+			`wt.name "workflow_execution.workflow_template.name"`,
+			`wtv.version "workflow_execution.workflow_template.version"`,
+		).
+		From("workflow_execution_queue q").
+		Join("workflow_executions we ON we.id = q.workflow_execution_id").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.Eq{
+			"q.namespace":    namespace,
+			"we.phase":       WorkflowExecutionPhaseQueued,
+			"we.is_archived": false,
+		}).
+		OrderBy("q.position", "q.id")
+}
+
+// ListQueuedWorkflowExecutions returns the queue of the namespace, in the order the workflow executions will be dispatched,
+// as far as their concurrency limits allow.
+func (c *Client) ListQueuedWorkflowExecutions(namespace string) (queue []*QueuedWorkflowExecution, err error) {
+	queue = make([]*QueuedWorkflowExecution, 0)
+	err = c.DB.Selectx(&queue, queuedWorkflowExecutionsSelectBuilder(namespace))
+
+	return
+}
+
+// getQueuedArgoWorkflow returns the argo workflow of a queued workflow execution, or nil if it is not queued
+func (c *Client) getQueuedArgoWorkflow(workflowExecutionID uint64) (*wfv1.Workflow, error) {
+	manifest := ""
+	err := sb.Select("manifest").
+		From("workflow_execution_queue").
+		Where(sq.Eq{"workflow_execution_id": workflowExecutionID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&manifest)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wf := &wfv1.Workflow{}
+	if err := json.Unmarshal([]byte(manifest), wf); err != nil {
+		return nil, err
+	}
+
+	return wf, nil
+}
+
+// MoveQueuedWorkflowExecution moves a queued workflow execution to the 1-based position in the queue of its namespace,
+// and returns the new queue. Positions after the end move it to the end.
+func (c *Client) MoveQueuedWorkflowExecution(namespace, uid string, position int) ([]*QueuedWorkflowExecution, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockWorkflowExecutionQueue(tx, namespace); err != nil {
+		return nil, err
+	}
+
+	queue, err := c.ListQueuedWorkflowExecutions(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var moved *QueuedWorkflowExecution
+	for _, queued := range queue {
+		if queued.WorkflowExecution.UID == uid {
+			moved = queued
+		}
+	}
+	if moved == nil {
+		return nil, util.NewUserError(codes.NotFound, "Queued workflow execution not found.")
+	}
+
+	queue = moveQueuedWorkflowExecution(queue, moved.WorkflowExecutionID, position)
+
+	for _, queued := range queue {
+		_, err := sb.Update("workflow_execution_queue").
+			Set("position", queued.Position).
+			Where(sq.Eq{"id": queued.ID}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// cancelQueuedWorkflowExecution removes the workflow execution from the queue and marks it as terminated.
+// It returns false if the workflow execution is not queued.
+func (c *Client) cancelQueuedWorkflowExecution(namespace, uid string) (bool, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := lockWorkflowExecutionQueue(tx, namespace); err != nil {
+		return false, err
+	}
+
+	result, err := sb.Delete("workflow_execution_queue").
+		Where(sq.Expr("workflow_execution_id = (SELECT id FROM workflow_executions WHERE namespace = ? AND uid = ? AND phase = ?)",
+			namespace, uid, WorkflowExecutionPhaseQueued)).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if deleted == 0 {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	_, err = sb.Update("workflow_executions").
+		SetMap(sq.Eq{
+			"phase":       "Terminated",
+			"started_at":  now,
+			"finished_at": now,
+		}).
+		Where(sq.Eq{
+			"namespace": namespace,
+			"uid":       uid,
+		}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// CancelQueuedWorkflowExecution removes a workflow execution from the queue before it starts, and marks it as terminated
+func (c *Client) CancelQueuedWorkflowExecution(namespace, uid string) error {
+	cancelled, err := c.cancelQueuedWorkflowExecution(namespace, uid)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return util.NewUserError(codes.FailedPrecondition, "Workflow execution is not queued.")
+	}
+
+	return nil
+}
+
+// dispatchQueuedWorkflowExecution creates the argo workflow of a queued workflow execution, and removes it from the queue using tx.
+// If argo rejects it, the execution fails, so it does not hold the queue.
+// The argo workflow may already exist if tx failed after it was created, that counts as dispatched.
+func (c *Client) dispatchQueuedWorkflowExecution(tx sq.BaseRunner, queued *QueuedWorkflowExecution) error {
+	wf := &wfv1.Workflow{}
+	err := json.Unmarshal(queued.Manifest, wf)
+	if err == nil {
+		_, err = c.ArgoprojV1alpha1().Workflows(queued.Namespace).Create(wf)
+		if errors.IsAlreadyExists(err) {
+			err = nil
+		}
+	}
+
+	fieldMap := sq.Eq{"phase": wfv1.NodePending}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": queued.Namespace,
+			"UID":       queued.WorkflowExecution.UID,
+			"Error":     err.Error(),
+		}).Error("Unable to dispatch queued workflow execution.")
+
+		fieldMap = sq.Eq{
+			"phase":       wfv1.NodeFailed,
+			"finished_at": time.Now().UTC(),
+		}
+	}
+
+	_, err = sb.Delete("workflow_execution_queue").
+		Where(sq.Eq{"id": queued.ID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	// The status of the argo workflow may have been synced already
+	_, err = sb.Update("workflow_executions").
+		SetMap(fieldMap).
+		Where(sq.Eq{
+			"id":    queued.WorkflowExecutionID,
+			"phase": WorkflowExecutionPhaseQueued,
+		}).
+		RunWith(tx).
+		Exec()
+
+	return err
+}
+
+// dispatchNamespaceQueue starts the queued workflow executions of the namespace that fit in its concurrency limits
+func (c *Client) dispatchNamespaceQueue(namespace string) error {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockWorkflowExecutionQueue(tx, namespace); err != nil {
+		return err
+	}
+
+	config, err := c.GetNamespaceConcurrencyConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := c.getWorkflowExecutionConcurrency(namespace)
+	if err != nil {
+		return err
+	}
+
+	queue := make([]*QueuedWorkflowExecution, 0)
+	if err := c.DB.Selectx(&queue, queuedWorkflowExecutionsSelectBuilder(namespace).Columns("q.manifest")); err != nil {
+		return err
+	}
+
+	for _, queued := range planWorkflowExecutionDispatch(config, concurrency, queue) {
+		if err := c.dispatchQueuedWorkflowExecution(tx, queued); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DispatchQueuedWorkflowExecutions starts queued workflow executions of all namespaces as their concurrency limits allow.
+// Errors of a namespace are logged, so they don't stop the other namespaces.
+func (c *Client) DispatchQueuedWorkflowExecutions() error {
+	namespaces := make([]string, 0)
+	query := sb.Select("namespace").
+		Distinct().
+		From("workflow_execution_queue")
+	if err := c.DB.Selectx(&namespaces, query); err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		if err := c.dispatchNamespaceQueue(namespace); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"Error":     err.Error(),
+			}).Error("Unable to dispatch workflow execution queue.")
+		}
+	}
+
+	return nil
+}
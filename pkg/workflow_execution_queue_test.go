@@ -0,0 +1,116 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+// concurrencyTestClient returns a test client whose onepanel namespace runs one workflow execution at a time
+func concurrencyTestClient() *Client {
+	configMap := mockSystemConfigMap.DeepCopy()
+	configMap.Data["concurrency"] = "maxRunning: 1"
+
+	return NewTestClient(database, configMap, mockSystemSecret)
+}
+
+// TestClient_QueueWorkflowExecution makes sure executions past the limit are queued, and can be moved, cancelled and dispatched
+func TestClient_QueueWorkflowExecution(t *testing.T) {
+	c := concurrencyTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	first := createWorkflowExecutionForTest(t, c, namespace, "first")
+	assert.Equal(t, wfv1.NodePending, first.Phase)
+
+	second := createWorkflowExecutionForTest(t, c, namespace, "second")
+	assert.Equal(t, WorkflowExecutionPhaseQueued, second.Phase)
+	assert.NotEmpty(t, second.UID)
+
+	third := createWorkflowExecutionForTest(t, c, namespace, "third")
+	assert.Equal(t, WorkflowExecutionPhaseQueued, third.Phase)
+
+	_, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(second.Name, metav1.GetOptions{})
+	assert.NotNil(t, err)
+
+	queue, err := c.MoveQueuedWorkflowExecution(namespace, third.UID, 1)
+	assert.Nil(t, err)
+	if assert.Len(t, queue, 2) {
+		assert.Equal(t, third.UID, queue[0].WorkflowExecution.UID)
+		assert.Equal(t, second.UID, queue[1].WorkflowExecution.UID)
+	}
+
+	assert.Nil(t, c.CancelQueuedWorkflowExecution(namespace, second.UID))
+	cancelled, err := c.GetWorkflowExecution(namespace, second.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodePhase("Terminated"), cancelled.Phase)
+
+	// The first execution still runs, so nothing is dispatched
+	assert.Nil(t, c.DispatchQueuedWorkflowExecutions())
+	queue, err = c.ListQueuedWorkflowExecutions(namespace)
+	assert.Nil(t, err)
+	assert.Len(t, queue, 1)
+
+	_, err = database.Exec(`UPDATE workflow_executions SET phase = 'Succeeded', finished_at = NOW() WHERE uid = $1`, first.UID)
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.DispatchQueuedWorkflowExecutions())
+	queue, err = c.ListQueuedWorkflowExecutions(namespace)
+	assert.Nil(t, err)
+	assert.Len(t, queue, 0)
+
+	dispatched, err := c.GetWorkflowExecution(namespace, third.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodePending, dispatched.Phase)
+
+	_, err = c.ArgoprojV1alpha1().Workflows(namespace).Get(third.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+}
+
+// TestClient_GetNamespaceConfig_InvalidConcurrency makes sure a malformed concurrency key only fails the concurrency config
+func TestClient_GetNamespaceConfig_InvalidConcurrency(t *testing.T) {
+	configMap := mockSystemConfigMap.DeepCopy()
+	configMap.Data["concurrency"] = "maxRunning: [1"
+	c := NewTestClient(database, configMap, &v1.Secret{
+		ObjectMeta: mockSystemSecret.ObjectMeta,
+		Data: map[string][]byte{
+			"artifactRepositoryS3AccessKey": []byte("access"),
+			"artifactRepositoryS3SecretKey": []byte("secret"),
+		},
+	})
+
+	_, err := c.GetNamespaceConfig("onepanel")
+	assert.Nil(t, err)
+
+	_, err = c.GetNamespaceConcurrencyConfig("onepanel")
+	assert.NotNil(t, err)
+}
+
+// TestClient_RetryWorkflowExecution_Queued makes sure a retry is recorded with a reference to the original execution,
+// and waits in the queue like any other execution
+func TestClient_RetryWorkflowExecution_Queued(t *testing.T) {
+	c := concurrencyTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	first := createWorkflowExecutionForTest(t, c, namespace, "first")
+	setArgoWorkflowPhaseForTest(t, c, namespace, first.UID, wfv1.NodeFailed, true)
+	createWorkflowExecutionForTest(t, c, namespace, "second")
+
+	retried, err := c.RetryWorkflowExecution(namespace, first.UID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, WorkflowExecutionPhaseQueued, retried.Phase)
+	assert.NotZero(t, retried.ID)
+
+	var retriedFromID uint64
+	assert.Nil(t, database.Get(&retriedFromID, `SELECT retried_from_id FROM workflow_executions WHERE id = $1`, retried.ID))
+	assert.Equal(t, first.ID, retriedFromID)
+
+	queue, err := c.ListQueuedWorkflowExecutions(namespace)
+	assert.Nil(t, err)
+	if assert.Len(t, queue, 1) {
+		assert.Equal(t, retried.UID, queue[0].WorkflowExecution.UID)
+	}
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"time"
+)
+
+// WorkflowExecutionPhaseQueued is the phase of a workflow execution that waits for a concurrency limit of its namespace
+// or workflow template, see ConcurrencyConfig. Its argo workflow is created when it is dispatched.
+const WorkflowExecutionPhaseQueued wfv1.NodePhase = "Queued"
+
+// workflowExecutionRunningPhases are the phases of workflow executions that count towards the concurrency limits
+var workflowExecutionRunningPhases = []string{
+	string(wfv1.NodePending),
+	string(wfv1.NodeRunning),
+	string(WorkflowExecutionPhaseSuspended),
+}
+
+// QueuedWorkflowExecution is a workflow execution waiting in the queue of its namespace
+type QueuedWorkflowExecution struct {
+	ID                  uint64
+	WorkflowExecutionID uint64 `db:"workflow_execution_id"`
+	Namespace           string
+	// Position is the 1-based position in the queue of the namespace. Lower positions are dispatched first.
+	Position          int
+	Manifest          []byte
+	CreatedAt         time.Time          `db:"created_at"`
+	WorkflowExecution *WorkflowExecution `db:"workflow_execution"`
+}
+
+// workflowExecutionConcurrency counts the running workflow executions of a namespace, in total and by workflow template uid
+type workflowExecutionConcurrency struct {
+	Running                   int
+	RunningByWorkflowTemplate map[string]int
+}
+
+// isFull returns true if another execution of the workflow template can't start without going over a limit of config
+func (w *workflowExecutionConcurrency) isFull(config *ConcurrencyConfig, workflowTemplateUID string) bool {
+	if config.MaxRunning > 0 && w.Running >= config.MaxRunning {
+		return true
+	}
+
+	limit := config.WorkflowTemplateLimit(workflowTemplateUID)
+
+	return limit > 0 && w.RunningByWorkflowTemplate[workflowTemplateUID] >= limit
+}
+
+// add counts a started execution of the workflow template
+func (w *workflowExecutionConcurrency) add(workflowTemplateUID string) {
+	w.Running++
+	w.RunningByWorkflowTemplate[workflowTemplateUID]++
+}
+
+// planWorkflowExecutionDispatch returns the queued executions that can start, in order.
+// The queue is ordered by position. An execution that is held by the limit of its workflow template does not hold back
+// the ones after it, but the limit of the namespace stops the dispatch.
+// concurrency is updated with the executions that are returned.
+func planWorkflowExecutionDispatch(config *ConcurrencyConfig, concurrency *workflowExecutionConcurrency, queue []*QueuedWorkflowExecution) []*QueuedWorkflowExecution {
+	result := make([]*QueuedWorkflowExecution, 0)
+	for _, queued := range queue {
+		if config.MaxRunning > 0 && concurrency.Running >= config.MaxRunning {
+			break
+		}
+
+		workflowTemplateUID := queued.WorkflowExecution.WorkflowTemplate.UID
+		if concurrency.isFull(config, workflowTemplateUID) {
+			continue
+		}
+
+		concurrency.add(workflowTemplateUID)
+		result = append(result, queued)
+	}
+
+	return result
+}
+
+// moveQueuedWorkflowExecution moves the execution with the id to the 1-based position in queue, and returns the new order.
+// Positions out of range move it to the start or end of the queue.
+func moveQueuedWorkflowExecution(queue []*QueuedWorkflowExecution, workflowExecutionID uint64, position int) []*QueuedWorkflowExecution {
+	var moved *QueuedWorkflowExecution
+	result := make([]*QueuedWorkflowExecution, 0, len(queue))
+	for _, queued := range queue {
+		if queued.WorkflowExecutionID == workflowExecutionID {
+			moved = queued
+			continue
+		}
+		result = append(result, queued)
+	}
+
+	if moved == nil {
+		return queue
+	}
+
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(result) {
+		index = len(result)
+	}
+
+	result = append(result, nil)
+	copy(result[index+1:], result[index:])
+	result[index] = moved
+
+	for i, queued := range result {
+		queued.Position = i + 1
+	}
+
+	return result
+}
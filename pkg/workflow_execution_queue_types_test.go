@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testQueue(workflowTemplateUIDs ...string) []*QueuedWorkflowExecution {
+	queue := make([]*QueuedWorkflowExecution, 0)
+	for i, workflowTemplateUID := range workflowTemplateUIDs {
+		queue = append(queue, &QueuedWorkflowExecution{
+			ID:                  uint64(i + 1),
+			WorkflowExecutionID: uint64(i + 1),
+			Position:            i + 1,
+			WorkflowExecution: &WorkflowExecution{
+				WorkflowTemplate: &WorkflowTemplate{UID: workflowTemplateUID},
+			},
+		})
+	}
+
+	return queue
+}
+
+func queueIDs(queue []*QueuedWorkflowExecution) []uint64 {
+	result := make([]uint64, 0)
+	for _, queued := range queue {
+		result = append(result, queued.WorkflowExecutionID)
+	}
+
+	return result
+}
+
+// TestPlanWorkflowExecutionDispatch_Namespace makes sure the namespace limit dispatches the queue in order
+func TestPlanWorkflowExecutionDispatch_Namespace(t *testing.T) {
+	config := &ConcurrencyConfig{MaxRunning: 3}
+	concurrency := &workflowExecutionConcurrency{
+		Running:                   1,
+		RunningByWorkflowTemplate: map[string]int{"a": 1},
+	}
+
+	dispatched := planWorkflowExecutionDispatch(config, concurrency, testQueue("a", "b", "a", "b"))
+	assert.Equal(t, []uint64{1, 2}, queueIDs(dispatched))
+	assert.Equal(t, 3, concurrency.Running)
+	assert.Equal(t, 2, concurrency.RunningByWorkflowTemplate["a"])
+}
+
+// TestPlanWorkflowExecutionDispatch_WorkflowTemplate makes sure executions held by a template limit don't hold the others
+func TestPlanWorkflowExecutionDispatch_WorkflowTemplate(t *testing.T) {
+	config := &ConcurrencyConfig{
+		MaxRunning:        10,
+		WorkflowTemplates: map[string]int{"a": 1},
+	}
+	concurrency := &workflowExecutionConcurrency{
+		RunningByWorkflowTemplate: make(map[string]int),
+	}
+
+	dispatched := planWorkflowExecutionDispatch(config, concurrency, testQueue("a", "a", "b", "a", "b"))
+	assert.Equal(t, []uint64{1, 3, 5}, queueIDs(dispatched))
+}
+
+// TestConcurrencyConfig_HasLimits makes sure zero limits are the same as no limits
+func TestConcurrencyConfig_HasLimits(t *testing.T) {
+	assert.False(t, (&ConcurrencyConfig{}).HasLimits())
+	assert.False(t, (&ConcurrencyConfig{WorkflowTemplates: map[string]int{"a": 0}}).HasLimits())
+	assert.True(t, (&ConcurrencyConfig{WorkflowTemplates: map[string]int{"a": 2}}).HasLimits())
+	assert.True(t, (&ConcurrencyConfig{MaxRunning: 1}).HasLimits())
+}
+
+// TestMoveQueuedWorkflowExecution makes sure executions are moved and positions are renumbered
+func TestMoveQueuedWorkflowExecution(t *testing.T) {
+	queue := moveQueuedWorkflowExecution(testQueue("a", "a", "a", "a"), 3, 1)
+	assert.Equal(t, []uint64{3, 1, 2, 4}, queueIDs(queue))
+	for i, queued := range queue {
+		assert.Equal(t, i+1, queued.Position)
+	}
+
+	queue = moveQueuedWorkflowExecution(testQueue("a", "a", "a", "a"), 1, 100)
+	assert.Equal(t, []uint64{2, 3, 4, 1}, queueIDs(queue))
+
+	queue = moveQueuedWorkflowExecution(testQueue("a", "a", "a"), 2, -5)
+	assert.Equal(t, []uint64{2, 1, 3}, queueIDs(queue))
+
+	queue = moveQueuedWorkflowExecution(testQueue("a", "a"), 9, 1)
+	assert.Equal(t, []uint64{1, 2}, queueIDs(queue))
+}
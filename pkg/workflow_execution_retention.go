@@ -0,0 +1,184 @@
+package v1
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// listRetentionCandidates returns the finished workflow executions of the namespace, including archived ones,
+// grouped by workflow template and newest first. Executions of system workflow templates, like workspaces, are not included.
+func (c *Client) listRetentionCandidates(namespace string) (executions []*WorkflowExecutionRetentionItem, err error) {
+	query := sb.Select("we.id", "we.uid", "we.name", "we.phase", "we.labels", "we.is_archived", "we.created_at", "we.finished_at", "we.artifacts_deleted_at").
+		Columns(`wt.uid "workflow_template_uid"`).
+		From("workflow_executions we").
+		Join("workflow_template_versions wtv ON wtv.id = we.workflow_template_version_id").
+		Join("workflow_templates wt ON wt.id = wtv.workflow_template_id").
+		Where(sq.And{
+			sq.Eq{
+				"we.namespace": namespace,
+				"wt.is_system": false,
+			},
+			sq.NotEq{"we.finished_at": nil},
+		}).
+		OrderBy("wt.uid", "we.created_at DESC", "we.id DESC")
+
+	executions = make([]*WorkflowExecutionRetentionItem, 0)
+	err = c.DB.Selectx(&executions, query)
+
+	return
+}
+
+// GetWorkflowExecutionRetentionReport returns the workflow executions the retention policies of the namespace
+// would collect now, and why. Nothing is deleted.
+func (c *Client) GetWorkflowExecutionRetentionReport(namespace string) (items []*WorkflowExecutionRetentionItem, err error) {
+	config, err := c.GetNamespaceRetentionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.planNamespaceRetention(namespace, config, time.Now().UTC())
+}
+
+// planNamespaceRetention applies the policies of config to the workflow executions of the namespace
+func (c *Client) planNamespaceRetention(namespace string, config *RetentionConfig, now time.Time) ([]*WorkflowExecutionRetentionItem, error) {
+	items := make([]*WorkflowExecutionRetentionItem, 0)
+	if !config.HasPolicies() {
+		return items, nil
+	}
+
+	executions, err := c.listRetentionCandidates(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(executions); {
+		end := start
+		for end < len(executions) && executions[end].WorkflowTemplateUID == executions[start].WorkflowTemplateUID {
+			end++
+		}
+
+		policy := config.Policy(executions[start].WorkflowTemplateUID)
+		items = append(items, planRetention(policy, executions[start:end], now)...)
+		start = end
+	}
+
+	return items, nil
+}
+
+// deleteRetainedWorkflowExecution deletes the argo workflow, the artifacts and the record of a workflow execution.
+// The record is only deleted once the rest is gone, so failures are retried by the next run.
+func (c *Client) deleteRetainedWorkflowExecution(namespace string, store ArtifactStore, repository *ArtifactRepositoryProvider, item *WorkflowExecutionRetentionItem) error {
+	err := c.ArgoprojV1alpha1().Workflows(namespace).Delete(item.Name, nil)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return err
+	}
+
+	if item.ArtifactsDeletedAt == nil {
+		if err := c.deleteRetainedArtifacts(namespace, store, repository, item); err != nil {
+			return err
+		}
+	}
+
+	_, err = sb.Delete("workflow_executions").
+		Where(sq.Eq{"id": item.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// deleteRetainedArtifacts deletes the artifacts of a workflow execution, and records that they are gone.
+// Nothing is deleted if the namespace has no artifact repository. If its key format has no directory per workflow,
+// the artifacts can't be told apart from the ones of other workflows, so they are only recorded as handled,
+// which keeps them out of the next reports.
+func (c *Client) deleteRetainedArtifacts(namespace string, store ArtifactStore, repository *ArtifactRepositoryProvider, item *WorkflowExecutionRetentionItem) error {
+	if store == nil {
+		return nil
+	}
+
+	prefix := repository.WorkflowKeyPrefix(namespace, item.Name)
+	if prefix != "" {
+		if _, err := deleteArtifactDirectory(store, prefix); err != nil {
+			return err
+		}
+	}
+
+	_, err := sb.Update("workflow_executions").
+		Set("artifacts_deleted_at", time.Now().UTC()).
+		Where(sq.Eq{"id": item.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// enforceNamespaceRetention collects the workflow executions of the namespace according to its retention policies
+func (c *Client) enforceNamespaceRetention(namespace string) error {
+	config, err := c.GetNamespaceRetentionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	items, err := c.planNamespaceRetention(namespace, config, time.Now().UTC())
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	var store ArtifactStore
+	var repository *ArtifactRepositoryProvider
+	if namespaceConfig, err := c.GetNamespaceConfig(namespace); err == nil {
+		repository = &namespaceConfig.ArtifactRepository
+		if store, err = c.getArtifactStore(namespace, repository); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		switch item.Action {
+		case RetentionActionDelete:
+			// Without a store, the artifacts would be left behind with nothing pointing to them
+			if store == nil && item.ArtifactsDeletedAt == nil && !config.Policy(item.WorkflowTemplateUID).DeleteWithoutArtifactRepository {
+				continue
+			}
+			err = c.deleteRetainedWorkflowExecution(namespace, store, repository, item)
+		case RetentionActionDeleteArtifacts:
+			err = c.deleteRetainedArtifacts(namespace, store, repository, item)
+		}
+
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"UID":       item.UID,
+				"Action":    item.Action,
+				"Error":     err.Error(),
+			}).Error("Unable to apply retention policy.")
+		}
+	}
+
+	return nil
+}
+
+// EnforceRetentionPolicies deletes the workflow executions and artifacts that the retention policies of their namespaces
+// collect, see RetentionConfig. Errors of a namespace are logged, so they don't stop the other namespaces.
+func (c *Client) EnforceRetentionPolicies() error {
+	namespaces := make([]string, 0)
+	query := sb.Select("namespace").
+		Distinct().
+		From("workflow_executions")
+	if err := c.DB.Selectx(&namespaces, query); err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		if err := c.enforceNamespaceRetention(namespace); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"Error":     err.Error(),
+			}).Error("Unable to enforce retention policies.")
+		}
+	}
+
+	return nil
+}
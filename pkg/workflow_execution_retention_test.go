@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestClient_EnforceRetentionPolicies_WithoutArtifactRepository makes sure executions are only deleted without
+// an artifact repository if the policy opts in, so their artifacts are not orphaned
+func TestClient_EnforceRetentionPolicies_WithoutArtifactRepository(t *testing.T) {
+	configMap := mockSystemConfigMap.DeepCopy()
+	delete(configMap.Data, "artifactRepository")
+	configMap.Data["retention"] = "keepLast: 1"
+	c := NewTestClient(database, configMap, mockSystemSecret)
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	createWorkflowExecutionForTest(t, c, namespace, "first")
+	second := createWorkflowExecutionForTest(t, c, namespace, "second")
+
+	_, err := database.Exec(`UPDATE workflow_executions SET phase = 'Succeeded', finished_at = NOW()`)
+	assert.Nil(t, err)
+	_, err = database.Exec(`UPDATE workflow_executions SET created_at = created_at + INTERVAL '1 minute' WHERE uid = $1`, second.UID)
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.EnforceRetentionPolicies())
+	count := 0
+	assert.Nil(t, database.Get(&count, `SELECT COUNT(*) FROM workflow_executions`))
+	assert.Equal(t, 2, count)
+
+	configMap.Data["retention"] = "keepLast: 1\ndeleteWithoutArtifactRepository: true"
+	c = NewTestClient(database, configMap, mockSystemSecret)
+
+	assert.Nil(t, c.EnforceRetentionPolicies())
+	assert.Nil(t, database.Get(&count, `SELECT COUNT(*) FROM workflow_executions`))
+	assert.Equal(t, 1, count)
+
+	kept, err := c.GetWorkflowExecution(namespace, second.UID)
+	assert.Nil(t, err)
+	assert.NotNil(t, kept)
+}
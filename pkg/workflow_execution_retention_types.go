@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"fmt"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/onepanelio/core/pkg/util/types"
+	"time"
+)
+
+// RetentionPolicy is how long finished workflow executions and their artifacts are kept. A limit of 0 means there is none.
+type RetentionPolicy struct {
+	// KeepLast is the number of finished workflow executions of each workflow template that are kept, newest first.
+	// Older ones are deleted.
+	KeepLast int `json:"keepLast"`
+	// MaxAgeDays deletes workflow executions that finished more than this many days ago
+	MaxAgeDays int `json:"maxAgeDays"`
+	// ArtifactMaxAgeDays deletes the artifacts of workflow executions that finished more than this many days ago,
+	// but keeps the workflow executions
+	ArtifactMaxAgeDays int `json:"artifactMaxAgeDays"`
+	// KeepLabels protects the workflow executions that have any of these labels, like keep=true.
+	// They are not deleted and don't count towards KeepLast.
+	KeepLabels map[string]string `json:"keepLabels"`
+	// DeleteWithoutArtifactRepository deletes workflow executions even if the artifact repository of the namespace
+	// can't be loaded, which leaves their artifacts behind. By default they are kept until it can be loaded.
+	DeleteWithoutArtifactRepository bool `json:"deleteWithoutArtifactRepository"`
+}
+
+// RetentionConfig is the retention policy of a namespace and the ones of its workflow templates.
+// It is set in the "retention" key of the onepanel config map of the namespace, for example:
+//
+//	keepLast: 100
+//	artifactMaxAgeDays: 30
+//	keepLabels:
+//	  keep: "true"
+//	workflowTemplates:
+//	  train-model:
+//	    keepLast: 50
+//
+// The policy of a workflow template replaces the one of the namespace.
+type RetentionConfig struct {
+	RetentionPolicy
+	WorkflowTemplates map[string]*RetentionPolicy `json:"workflowTemplates"`
+}
+
+// RetentionAction is what a retention policy does with a workflow execution
+type RetentionAction string
+
+const (
+	// RetentionActionDelete deletes the workflow execution, its argo workflow and its artifacts
+	RetentionActionDelete RetentionAction = "delete"
+	// RetentionActionDeleteArtifacts deletes the artifacts of the workflow execution
+	RetentionActionDeleteArtifacts RetentionAction = "deleteArtifacts"
+)
+
+// WorkflowExecutionRetentionItem is a finished workflow execution, and what its retention policy does with it
+type WorkflowExecutionRetentionItem struct {
+	ID                  uint64
+	UID                 string
+	Name                string
+	Phase               wfv1.NodePhase
+	Labels              types.JSONLabels
+	IsArchived          bool       `db:"is_archived"`
+	CreatedAt           time.Time  `db:"created_at"`
+	FinishedAt          *time.Time `db:"finished_at"`
+	ArtifactsDeletedAt  *time.Time `db:"artifacts_deleted_at"`
+	WorkflowTemplateUID string     `db:"workflow_template_uid"`
+	Action              RetentionAction
+	Reason              string
+}
+
+// IsEmpty returns true if the policy does not collect anything
+func (p *RetentionPolicy) IsEmpty() bool {
+	return p.KeepLast <= 0 && p.MaxAgeDays <= 0 && p.ArtifactMaxAgeDays <= 0
+}
+
+// keeps returns true if the labels protect the workflow execution from the policy
+func (p *RetentionPolicy) keeps(labels types.JSONLabels) bool {
+	for key, value := range p.KeepLabels {
+		if labelValue, ok := labels[key]; ok && labelValue == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Policy returns the retention policy of the workflow template
+func (c *RetentionConfig) Policy(workflowTemplateUID string) *RetentionPolicy {
+	if policy, ok := c.WorkflowTemplates[workflowTemplateUID]; ok && policy != nil {
+		return policy
+	}
+
+	return &c.RetentionPolicy
+}
+
+// HasPolicies returns true if any policy collects something
+func (c *RetentionConfig) HasPolicies() bool {
+	if !c.RetentionPolicy.IsEmpty() {
+		return true
+	}
+
+	for _, policy := range c.WorkflowTemplates {
+		if policy != nil && !policy.IsEmpty() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// planRetention sets the action of the finished workflow executions of a workflow template that policy collects,
+// and returns them. executions are expected newest first.
+func planRetention(policy *RetentionPolicy, executions []*WorkflowExecutionRetentionItem, now time.Time) []*WorkflowExecutionRetentionItem {
+	result := make([]*WorkflowExecutionRetentionItem, 0)
+	if policy.IsEmpty() {
+		return result
+	}
+
+	maxAge := time.Duration(policy.MaxAgeDays) * 24 * time.Hour
+	artifactMaxAge := time.Duration(policy.ArtifactMaxAgeDays) * 24 * time.Hour
+
+	rank := 0
+	for _, execution := range executions {
+		if execution.FinishedAt == nil || policy.keeps(execution.Labels) {
+			continue
+		}
+		rank++
+
+		age := now.Sub(*execution.FinishedAt)
+		switch {
+		case policy.KeepLast > 0 && rank > policy.KeepLast:
+			execution.Action = RetentionActionDelete
+			execution.Reason = fmt.Sprintf("Older than the last %v workflow executions.", policy.KeepLast)
+		case policy.MaxAgeDays > 0 && age > maxAge:
+			execution.Action = RetentionActionDelete
+			execution.Reason = fmt.Sprintf("Finished more than %v days ago.", policy.MaxAgeDays)
+		case policy.ArtifactMaxAgeDays > 0 && age > artifactMaxAge && execution.ArtifactsDeletedAt == nil:
+			execution.Action = RetentionActionDeleteArtifacts
+			execution.Reason = fmt.Sprintf("Artifacts are more than %v days old.", policy.ArtifactMaxAgeDays)
+		default:
+			continue
+		}
+
+		result = append(result, execution)
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util/ptr"
+	"github.com/onepanelio/core/pkg/util/types"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRetentionItems(now time.Time, finishedDaysAgo ...int) []*WorkflowExecutionRetentionItem {
+	items := make([]*WorkflowExecutionRetentionItem, 0)
+	for i, days := range finishedDaysAgo {
+		items = append(items, &WorkflowExecutionRetentionItem{
+			ID:         uint64(i + 1),
+			FinishedAt: ptr.Time(now.Add(-time.Duration(days) * 24 * time.Hour)),
+			Labels:     types.JSONLabels{},
+		})
+	}
+
+	return items
+}
+
+func retentionActions(items []*WorkflowExecutionRetentionItem) map[uint64]RetentionAction {
+	result := make(map[uint64]RetentionAction)
+	for _, item := range items {
+		result[item.ID] = item.Action
+	}
+
+	return result
+}
+
+// TestPlanRetention_KeepLast makes sure executions after the last KeepLast are deleted, except protected ones
+func TestPlanRetention_KeepLast(t *testing.T) {
+	now := time.Now()
+	items := testRetentionItems(now, 1, 2, 3, 4, 5)
+	items[1].Labels["keep"] = "true"
+
+	policy := &RetentionPolicy{KeepLast: 2, KeepLabels: map[string]string{"keep": "true"}}
+	planned := planRetention(policy, items, now)
+	assert.Equal(t, map[uint64]RetentionAction{
+		4: RetentionActionDelete,
+		5: RetentionActionDelete,
+	}, retentionActions(planned))
+}
+
+// TestPlanRetention_Age makes sure old executions are deleted, and the artifacts of less old ones
+func TestPlanRetention_Age(t *testing.T) {
+	now := time.Now()
+	items := testRetentionItems(now, 1, 10, 40, 100, 50)
+	items[4].ArtifactsDeletedAt = ptr.Time(now)
+
+	policy := &RetentionPolicy{MaxAgeDays: 90, ArtifactMaxAgeDays: 30}
+	planned := planRetention(policy, items, now)
+	assert.Equal(t, map[uint64]RetentionAction{
+		3: RetentionActionDeleteArtifacts,
+		4: RetentionActionDelete,
+	}, retentionActions(planned))
+	assert.Equal(t, "Finished more than 90 days ago.", planned[1].Reason)
+
+	assert.Empty(t, planRetention(&RetentionPolicy{}, items, now))
+}
+
+// TestRetentionConfig_Policy makes sure workflow template policies replace the one of the namespace
+func TestRetentionConfig_Policy(t *testing.T) {
+	config := &RetentionConfig{
+		RetentionPolicy: RetentionPolicy{KeepLast: 100},
+		WorkflowTemplates: map[string]*RetentionPolicy{
+			"train": {ArtifactMaxAgeDays: 7},
+		},
+	}
+
+	assert.True(t, config.HasPolicies())
+	assert.Equal(t, 100, config.Policy("other").KeepLast)
+	assert.Equal(t, 0, config.Policy("train").KeepLast)
+	assert.Equal(t, 7, config.Policy("train").ArtifactMaxAgeDays)
+	assert.False(t, (&RetentionConfig{}).HasPolicies())
+}
+
+// TestWorkflowArtifactKeyPrefix makes sure the prefix is the directory of the workflow, or empty if there is none
+func TestWorkflowArtifactKeyPrefix(t *testing.T) {
+	tests := []struct {
+		keyFormat string
+		expected  string
+	}{
+		{"artifacts/{{workflow.namespace}}/{{workflow.name}}/{{pod.name}}", "artifacts/ns/wf-abc/"},
+		{"{{workflow.name}}", "wf-abc/"},
+		{"artifacts/{{workflow.name}}-{{pod.name}}", ""},
+		{"artifacts/{{pod.name}}/{{workflow.name}}", ""},
+		{"artifacts/{{workflow.namespace}}", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, workflowArtifactKeyPrefix(test.keyFormat, "ns", "wf-abc"), test.keyFormat)
+	}
+}
+
+// TestDeleteArtifactDirectory makes sure the artifacts under the directory are deleted, and only those
+func TestDeleteArtifactDirectory(t *testing.T) {
+	store, _ := testFilesystemArtifactStore(t)
+
+	for _, key := range []string{"ns/wf/pod-1/a.txt", "ns/wf/pod-1/logs/main.log", "ns/wf/pod-2/b.txt", "ns/wf-2/pod/c.txt"} {
+		_, err := store.Put(key, strings.NewReader("data"), 4, "text/plain")
+		assert.Nil(t, err)
+	}
+
+	deleted, err := deleteArtifactDirectory(store, "ns/wf/")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, deleted)
+
+	_, err = store.Stat("ns/wf/pod-2/b.txt")
+	assert.NotNil(t, err)
+	_, err = store.Stat("ns/wf-2/pod/c.txt")
+	assert.Nil(t, err)
+}
@@ -1,8 +1,18 @@
 package v1
 
 import (
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	argoFake "github.com/argoproj/argo/pkg/client/clientset/versioned/fake"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/onepanelio/core/pkg/util/request"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+	"net/http"
 	"testing"
+	"time"
 )
 
 // TestClient_CreateWorkflowExecution tests creating a workflow execution
@@ -97,3 +107,216 @@ func TestClient_ArchiveWorkflowExecution_Exist(t *testing.T) {
 	err = c.ArchiveWorkflowExecution(namespace, weName)
 	assert.Nil(t, err)
 }
+
+// createWorkflowExecutionForTest creates a workflow execution named name from the default workflow template
+func createWorkflowExecutionForTest(t *testing.T, c *Client, namespace, name string) *WorkflowExecution {
+	wt, err := c.CreateWorkflowTemplate(namespace, &WorkflowTemplate{
+		Name:     "test",
+		Manifest: defaultWorkflowTemplate,
+	})
+	if err != nil {
+		wt, err = c.GetLatestWorkflowTemplate(namespace, "test")
+	}
+	assert.Nil(t, err)
+
+	we, err := c.CreateWorkflowExecution(namespace, &WorkflowExecution{Name: name}, wt)
+	assert.Nil(t, err)
+
+	return we
+}
+
+// setArgoWorkflowPhaseForTest sets the phase and times of the argo workflow of a workflow execution
+func setArgoWorkflowPhaseForTest(t *testing.T, c *Client, namespace, uid string, phase wfv1.NodePhase, finished bool) *wfv1.Workflow {
+	wf, err := c.ArgoprojV1alpha1().Workflows(namespace).Get(uid, metav1.GetOptions{})
+	assert.Nil(t, err)
+
+	wf.Status.Phase = phase
+	wf.Status.StartedAt = metav1.NewTime(time.Now().Add(-time.Minute))
+	if finished {
+		wf.Status.FinishedAt = metav1.Now()
+	}
+
+	wf, err = c.ArgoprojV1alpha1().Workflows(namespace).Update(wf)
+	assert.Nil(t, err)
+
+	return wf
+}
+
+// TestClient_SyncWorkflowExecutionStatus makes sure the phase and times of the argo workflow are copied to the workflow execution
+func TestClient_SyncWorkflowExecutionStatus(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	wf := setArgoWorkflowPhaseForTest(t, c, namespace, we.UID, wfv1.NodeRunning, false)
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+
+	synced, err := c.GetWorkflowExecution(namespace, we.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodeRunning, synced.Phase)
+	assert.NotNil(t, synced.StartedAt)
+	assert.Nil(t, synced.FinishedAt)
+
+	wf = setArgoWorkflowPhaseForTest(t, c, namespace, we.UID, wfv1.NodeSucceeded, true)
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+
+	synced, err = c.GetWorkflowExecution(namespace, we.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodeSucceeded, synced.Phase)
+	assert.NotNil(t, synced.FinishedAt)
+
+	// Workflows without a workflow execution, and without a phase, are ignored
+	wf.Name = "not-exist"
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(&wfv1.Workflow{}))
+}
+
+// TestClient_SyncWorkflowExecutionStatus_Terminated makes sure terminated workflow executions keep their phase
+func TestClient_SyncWorkflowExecutionStatus_Terminated(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	_, err := database.Exec(`UPDATE workflow_executions SET phase = 'Terminated' WHERE uid = $1`, we.UID)
+	assert.Nil(t, err)
+
+	wf := setArgoWorkflowPhaseForTest(t, c, namespace, we.UID, wfv1.NodeFailed, true)
+	assert.Nil(t, c.SyncWorkflowExecutionStatus(wf))
+
+	synced, err := c.GetWorkflowExecution(namespace, we.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodePhase("Terminated"), synced.Phase)
+
+	assert.Nil(t, c.FailMissingWorkflowExecution(namespace, we.UID))
+
+	synced, err = c.GetWorkflowExecution(namespace, we.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodePhase("Terminated"), synced.Phase)
+}
+
+// TestClient_RepairWorkflowExecutions makes sure unfinished workflow executions are synced, and failed if their argo workflow is gone
+func TestClient_RepairWorkflowExecutions(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	succeeded := createWorkflowExecutionForTest(t, c, namespace, "test-succeeded")
+	missing := createWorkflowExecutionForTest(t, c, namespace, "test-missing")
+
+	setArgoWorkflowPhaseForTest(t, c, namespace, succeeded.UID, wfv1.NodeSucceeded, true)
+	assert.Nil(t, c.ArgoprojV1alpha1().Workflows(namespace).Delete(missing.UID, &metav1.DeleteOptions{}))
+
+	assert.Nil(t, c.RepairWorkflowExecutions())
+
+	repaired, err := c.GetWorkflowExecution(namespace, succeeded.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodeSucceeded, repaired.Phase)
+	assert.NotNil(t, repaired.FinishedAt)
+
+	repaired, err = c.GetWorkflowExecution(namespace, missing.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, wfv1.NodeFailed, repaired.Phase)
+	assert.NotNil(t, repaired.FinishedAt)
+}
+
+// TestClient_ListWorkflowExecutions_Suspended makes sure suspended workflow executions are running in filters and statistics
+func TestClient_ListWorkflowExecutions_Suspended(t *testing.T) {
+	c := DefaultTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+
+	_, err := database.Exec(`UPDATE workflow_executions SET phase = 'Suspended' WHERE uid = $1`, we.UID)
+	assert.Nil(t, err)
+
+	running := &request.Request{Filter: WorkflowExecutionFilter{Phase: "running"}}
+	workflowExecutions, err := c.ListWorkflowExecutions(namespace, "", "", false, running)
+	assert.Nil(t, err)
+	assert.Len(t, workflowExecutions, 1)
+
+	report, err := c.GetWorkflowExecutionStatisticsForNamespace(namespace)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), report.Running)
+}
+
+// TestClient_BulkWorkflowExecutions_Resubmit makes sure resubmitted workflow executions are recorded in the database
+func TestClient_BulkWorkflowExecutions_Resubmit(t *testing.T) {
+	c := generateNameTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	we := createWorkflowExecutionForTest(t, c, namespace, "test")
+	_, err := database.Exec(`UPDATE workflow_executions SET phase = 'Succeeded', finished_at = NOW() WHERE uid = $1`, we.UID)
+	assert.Nil(t, err)
+
+	results, err := c.BulkWorkflowExecutions(namespace, &WorkflowExecutionBulkOptions{
+		Action: WorkflowExecutionBulkActionResubmit,
+		UIDs:   []string{we.UID},
+	})
+	assert.Nil(t, err)
+	if !assert.Len(t, results, 1) {
+		return
+	}
+	assert.Nil(t, results[0].Error)
+
+	resubmitted := results[0].WorkflowExecution
+	if assert.NotNil(t, resubmitted) {
+		assert.NotZero(t, resubmitted.ID)
+
+		recorded, err := c.GetWorkflowExecution(namespace, resubmitted.UID)
+		assert.Nil(t, err)
+		assert.Equal(t, wfv1.NodePending, recorded.Phase)
+	}
+}
+
+// TestClient_WatchWorkflowExecutions_Expired makes sure a watch whose resource version is too old stops with OutOfRange
+func TestClient_WatchWorkflowExecutions_Expired(t *testing.T) {
+	watcher := watch.NewFake()
+	argoFakeClient := argoFake.NewSimpleClientset()
+	argoFakeClient.PrependWatchReactor("workflows", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watcher, nil
+	})
+	c := &Client{argoprojV1alpha1: argoFakeClient.ArgoprojV1alpha1()}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	events, err := c.WatchWorkflowExecutions("onepanel", &WorkflowExecutionFilter{}, "1", stopCh)
+	assert.Nil(t, err)
+
+	go func() {
+		watcher.Add(&wfv1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "train",
+				Namespace:       "onepanel",
+				ResourceVersion: "2",
+			},
+		})
+		watcher.Error(&metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusGone,
+			Reason:  metav1.StatusReasonExpired,
+			Message: "too old resource version: 1 (5)",
+		})
+	}()
+
+	event := <-events
+	assert.Equal(t, WorkflowExecutionEventCreated, event.Type)
+	assert.Equal(t, "2", event.ResourceVersion)
+	assert.Nil(t, event.Error)
+
+	event = <-events
+	userErr, ok := event.Error.(*util.UserError)
+	if assert.True(t, ok) {
+		assert.Equal(t, codes.OutOfRange, userErr.Code)
+		assert.Equal(t, "too old resource version: 1 (5)", userErr.Message)
+	}
+
+	_, open := <-events
+	assert.False(t, open)
+}
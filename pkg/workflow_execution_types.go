@@ -27,8 +27,14 @@ type WorkflowExecution struct {
 	Labels           types.JSONLabels
 	Metrics          Metrics
 	ArgoWorkflow     *wfv1.Workflow
+	// retriedFromID is the id of the workflow execution this one retries, recorded when it is inserted
+	retriedFromID uint64
 }
 
+// WorkflowExecutionPhaseSuspended is the phase of a running workflow execution that is waiting to be resumed.
+// Argo reports these workflows as Running.
+const WorkflowExecutionPhaseSuspended wfv1.NodePhase = "Suspended"
+
 // WorkflowExecutionOptions are options you have for an executing workflow
 type WorkflowExecutionOptions struct {
 	Name           string
@@ -41,6 +47,19 @@ type WorkflowExecutionOptions struct {
 	PodGCStrategy  *PodGCStrategy
 }
 
+// RetryWorkflowExecutionOptions are the options for retrying a failed workflow execution
+type RetryWorkflowExecutionOptions struct {
+	// NodeFieldSelector limits the failed nodes that are run again, e.g. "templateName=train".
+	// Supported fields are displayName, templateName and inputs.parameters.<name>.value
+	// Failed nodes that are not selected stay failed, so the retried workflow fails once the selected nodes are done.
+	// failFast is turned off for its DAGs so the selected nodes still run; a steps template stops at the first
+	// group with a failed step.
+	NodeFieldSelector string
+	// Parameters override the values of the workflow parameters with the same name.
+	// They are validated against the parameters of the workflow template version, see ValidateParameterValues.
+	Parameters []Parameter
+}
+
 // WorkflowExecutionStatistic is a record keeping track of what happened to a workflow execution
 type WorkflowExecutionStatistic struct {
 	ID                 uint64
@@ -60,6 +79,28 @@ type WorkflowExecutionStatus struct {
 	FinishedAt *time.Time     `db:"finished_at" json:"finishedAt"`
 }
 
+// WorkflowExecutionEventType is the kind of change a WorkflowExecutionEvent describes
+type WorkflowExecutionEventType string
+
+const (
+	// WorkflowExecutionEventCreated is sent when a workflow execution is first seen
+	WorkflowExecutionEventCreated WorkflowExecutionEventType = "Created"
+	// WorkflowExecutionEventPhaseChanged is sent when the phase of a workflow execution changes
+	WorkflowExecutionEventPhaseChanged WorkflowExecutionEventType = "PhaseChanged"
+	// WorkflowExecutionEventDeleted is sent when a workflow execution is removed
+	WorkflowExecutionEventDeleted WorkflowExecutionEventType = "Deleted"
+)
+
+// WorkflowExecutionEvent represents a change to a workflow execution in a namespace.
+// ResourceVersion can be used to resume watching from this event.
+type WorkflowExecutionEvent struct {
+	Type              WorkflowExecutionEventType
+	WorkflowExecution *WorkflowExecution
+	ResourceVersion   string
+	// Error is only set on the last event of a watch that stopped because of an error, without the other fields
+	Error error
+}
+
 // GenerateUID generates a uid from the input name and sets it on the workflow execution
 func (we *WorkflowExecution) GenerateUID(name string) error {
 	result, err := uid2.GenerateUID(name, 63)
@@ -346,6 +346,28 @@ func (c *Client) getWorkflowTemplateDB(namespace, name string) (workflowTemplate
 	return
 }
 
+// selectWorkflowTemplateByUID returns the workflow template of the namespace with the uid that is not archived, or nil
+func (c *Client) selectWorkflowTemplateByUID(namespace, uid string) (*WorkflowTemplate, error) {
+	query := sb.Select(getWorkflowTemplateColumns("wt")...).
+		Columns("wt.is_system").
+		From("workflow_templates wt").
+		Where(sq.Eq{
+			"wt.namespace":   namespace,
+			"wt.uid":         uid,
+			"wt.is_archived": false,
+		})
+
+	workflowTemplate := &WorkflowTemplate{}
+	if err := c.DB.Getx(workflowTemplate, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return workflowTemplate, nil
+}
+
 // getWorkflowTemplateVersionDB will return a WorkflowTemplateVersion given the arguments.
 // version can be a number as a string, or the string "latest" to get the latest.
 func (c *Client) getWorkflowTemplateVersionDB(namespace, name, version string) (workflowTemplateVersion *WorkflowTemplateVersion, err error) {
@@ -735,6 +757,164 @@ func (c *Client) UpdateWorkflowTemplateVersion(wtv *WorkflowTemplateVersion) err
 	return updateWorkflowTemplateVersionDB(c.DB, wtv)
 }
 
+// setArgoWorkflowTemplateLatest moves the label.VersionLatest label of the argo workflow templates of workflowTemplateUID
+// to the one of version
+func (c *Client) setArgoWorkflowTemplateLatest(namespace, workflowTemplateUID string, version int64) error {
+	argoWorkflowTemplates, err := c.listArgoWorkflowTemplates(namespace, workflowTemplateUID)
+	if err != nil {
+		return err
+	}
+
+	versionAsString := strconv.FormatInt(version, 10)
+	for i := range *argoWorkflowTemplates {
+		argoWorkflowTemplate := &(*argoWorkflowTemplates)[i]
+
+		isLatest := argoWorkflowTemplate.Labels[label.Version] == versionAsString
+		if isLatest == (argoWorkflowTemplate.Labels[label.VersionLatest] == "true") {
+			continue
+		}
+
+		if isLatest {
+			if argoWorkflowTemplate.Labels == nil {
+				argoWorkflowTemplate.Labels = make(map[string]string)
+			}
+			argoWorkflowTemplate.Labels[label.VersionLatest] = "true"
+		} else {
+			delete(argoWorkflowTemplate.Labels, label.VersionLatest)
+		}
+
+		if _, err := c.ArgoprojV1alpha1().WorkflowTemplates(namespace).Update(argoWorkflowTemplate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetLatestWorkflowTemplateVersion marks an existing version of the workflow template identified by (namespace, uid)
+// as the latest one, so it is used wherever no version is given. This rolls back to, or forward to, that version.
+// If updateCronWorkflows is true, the cron workflows that use other versions of the workflow template are updated to use it too,
+// and they are returned. The new latest version is kept if argo can't be updated, and the error is returned
+// with the cron workflows that were updated. A cron workflow that can't be updated keeps its version,
+// so setting the same version again updates it.
+func (c *Client) SetLatestWorkflowTemplateVersion(namespace, uid string, version int64, updateCronWorkflows bool) (workflowTemplate *WorkflowTemplate, cronWorkflows []*CronWorkflow, err error) {
+	if version <= 0 {
+		return nil, nil, util.NewUserError(codes.InvalidArgument, "Version is required.")
+	}
+
+	query := c.workflowTemplatesVersionSelectBuilder(namespace).
+		Columns(`wt.id "workflow_template.id"`).
+		Where(sq.Eq{
+			"wt.uid":         uid,
+			"wt.is_archived": false,
+			"wtv.version":    version,
+		})
+	target := &WorkflowTemplateVersion{}
+	if err = c.DB.Getx(target, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, util.NewUserError(codes.NotFound, "Workflow template version not found.")
+		}
+		return nil, nil, err
+	}
+
+	workflowTemplate, err = c.GetWorkflowTemplate(namespace, uid, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = sb.Update("workflow_template_versions").
+		Set("is_latest", false).
+		Where(sq.Eq{
+			"workflow_template_id": target.WorkflowTemplate.ID,
+		}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = sb.Update("workflow_template_versions").
+		Set("is_latest", true).
+		Where(sq.Eq{
+			"id": target.ID,
+		}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Make sure the associated workflow template has the latest labels
+	_, err = sb.Update("workflow_templates").
+		Set("labels", target.Labels).
+		Where(sq.Eq{
+			"id": target.WorkflowTemplate.ID,
+		}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	// Argo is only updated once the new latest version is stored, so it never runs ahead of the database.
+	// Each cron workflow is updated on its own. One that fails keeps its version, and the others are still updated.
+	var updateErr error
+	cronWorkflows = make([]*CronWorkflow, 0)
+	if updateCronWorkflows {
+		otherCronWorkflows, err := c.selectCronWorkflowsOfOtherVersions(target.WorkflowTemplate.ID, target.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, cronWorkflow := range otherCronWorkflows {
+			if err := c.updateCronWorkflowWorkflowTemplateVersion(c.DB, namespace, cronWorkflow, workflowTemplate); err != nil {
+				log.WithFields(log.Fields{
+					"Namespace":    namespace,
+					"UID":          uid,
+					"Version":      version,
+					"CronWorkflow": cronWorkflow.Name,
+					"Error":        err.Error(),
+				}).Error("Could not update the workflow template version of the cron workflow")
+				if updateErr == nil {
+					updateErr = err
+				}
+				continue
+			}
+
+			cronWorkflows = append(cronWorkflows, cronWorkflow)
+		}
+	}
+
+	// Moving the label again is harmless, so setting the same version again repairs it if this fails
+	if err := c.setArgoWorkflowTemplateLatest(namespace, uid, version); err != nil {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Version":   version,
+			"Error":     err.Error(),
+		}).Error("Could not update the latest argo workflow template")
+		updateErr = err
+	}
+	if updateErr != nil {
+		return nil, cronWorkflows, updateErr
+	}
+
+	workflowTemplate.IsLatest = true
+	workflowTemplate.Labels = target.Labels
+
+	return workflowTemplate, cronWorkflows, nil
+}
+
 // GetWorkflowTemplate returns a WorkflowTemplate with data loaded from various sources
 // If version is 0, it returns the latest version data.
 //
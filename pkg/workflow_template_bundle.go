@@ -0,0 +1,359 @@
+package v1
+
+import (
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	uid2 "github.com/onepanelio/core/pkg/util/uid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"sort"
+	"time"
+)
+
+// templateBundleImport is a template of a bundle and where it is imported
+type templateBundleImport struct {
+	kind     string
+	template *TemplateBundleTemplate
+	result   *TemplateBundleImportResult
+}
+
+// selectWorkspaceTemplateUIDByWorkflowTemplateID returns the uid of the workspace template that the workflow template runs, or ""
+func (c *Client) selectWorkspaceTemplateUIDByWorkflowTemplateID(workflowTemplateID uint64) (uid string, err error) {
+	query := sb.Select("uid").
+		From("workspace_templates").
+		Where(sq.Eq{
+			"workflow_template_id": workflowTemplateID,
+			"is_archived":          false,
+		})
+
+	if err = c.DB.Getx(&uid, query); err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return
+}
+
+// exportBundleWorkflowTemplate returns the selected versions of the workflow template
+func (c *Client) exportBundleWorkflowTemplate(namespace string, workflowTemplate *WorkflowTemplate, selection *TemplateBundleSelection) (*TemplateBundleTemplate, error) {
+	versions, err := c.listWorkflowTemplateVersions(namespace, workflowTemplate.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int64]bool)
+	for _, version := range selection.Versions {
+		selected[version] = false
+	}
+
+	template := &TemplateBundleTemplate{
+		UID:    workflowTemplate.UID,
+		Name:   workflowTemplate.Name,
+		Labels: workflowTemplate.Labels,
+	}
+	for _, version := range versions {
+		if _, ok := selected[version.Version]; len(selected) != 0 && !ok {
+			continue
+		}
+		selected[version.Version] = true
+
+		template.Versions = append(template.Versions, &TemplateBundleVersion{
+			Version:  version.Version,
+			Manifest: version.Manifest,
+		})
+	}
+
+	for version, found := range selected {
+		if !found {
+			return nil, util.NewUserError(codes.NotFound, fmt.Sprintf("Version %v of workflow template '%v' not found.", version, workflowTemplate.UID))
+		}
+	}
+
+	return template, nil
+}
+
+// exportBundleWorkspaceTemplate returns all of the versions of the workspace template
+func (c *Client) exportBundleWorkspaceTemplate(namespace, uid string) (*TemplateBundleTemplate, error) {
+	versions, err := c.ListWorkspaceTemplateVersions(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, util.NewUserError(codes.NotFound, fmt.Sprintf("Workspace template '%v' not found.", uid))
+	}
+
+	latest := versions[0]
+	template := &TemplateBundleTemplate{
+		UID:         uid,
+		Name:        latest.Name,
+		Description: latest.Description,
+		Labels:      latest.Labels,
+	}
+	for _, version := range versions {
+		template.Versions = append(template.Versions, &TemplateBundleVersion{
+			Version:  version.Version,
+			Manifest: version.Manifest,
+		})
+	}
+
+	return template, nil
+}
+
+// ExportTemplateBundle returns a bundle with the selected versions of workflow templates, and all of the versions
+// of workspace templates, of the namespace. A workflow template that runs a workspace template is exported as
+// that workspace template, because importing the workspace template creates it.
+func (c *Client) ExportTemplateBundle(namespace string, workflowTemplates []*TemplateBundleSelection, workspaceTemplateUIDs []string) (*TemplateBundle, error) {
+	if len(workflowTemplates) == 0 && len(workspaceTemplateUIDs) == 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "At least one template is required.")
+	}
+
+	bundle := &TemplateBundle{
+		Namespace:  namespace,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	workspaceUIDs := make(map[string]bool)
+	for _, uid := range workspaceTemplateUIDs {
+		workspaceUIDs[uid] = true
+	}
+
+	for _, selection := range workflowTemplates {
+		workflowTemplate, err := c.selectWorkflowTemplateByUID(namespace, selection.UID)
+		if err != nil {
+			return nil, err
+		}
+		if workflowTemplate == nil {
+			return nil, util.NewUserError(codes.NotFound, fmt.Sprintf("Workflow template '%v' not found.", selection.UID))
+		}
+
+		if workflowTemplate.IsSystem {
+			workspaceTemplateUID, err := c.selectWorkspaceTemplateUIDByWorkflowTemplateID(workflowTemplate.ID)
+			if err != nil {
+				return nil, err
+			}
+			if workspaceTemplateUID != "" {
+				workspaceUIDs[workspaceTemplateUID] = true
+				continue
+			}
+		}
+
+		template, err := c.exportBundleWorkflowTemplate(namespace, workflowTemplate, selection)
+		if err != nil {
+			return nil, err
+		}
+		bundle.WorkflowTemplates = append(bundle.WorkflowTemplates, template)
+	}
+
+	uids := make([]string, 0, len(workspaceUIDs))
+	for uid := range workspaceUIDs {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	for _, uid := range uids {
+		template, err := c.exportBundleWorkspaceTemplate(namespace, uid)
+		if err != nil {
+			return nil, err
+		}
+		bundle.WorkspaceTemplates = append(bundle.WorkspaceTemplates, template)
+	}
+
+	return bundle, nil
+}
+
+// templateBundleUIDTaken returns true if a template of the kind can't be created with the uid in the namespace.
+// The workflow template of a workspace template has its uid, so both kinds are checked.
+// existing is the template new versions are added to, if any.
+func (c *Client) templateBundleUIDTaken(namespace, kind, name, uid string) (taken bool, existing *TemplateBundleTemplate, err error) {
+	workflowTemplate, err := c.selectWorkflowTemplateByUID(namespace, uid)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if kind == templateBundleWorkflows {
+		if workflowTemplate == nil {
+			return false, nil, nil
+		}
+		if workflowTemplate.IsSystem {
+			return true, nil, nil
+		}
+		return true, &TemplateBundleTemplate{UID: workflowTemplate.UID, Name: workflowTemplate.Name}, nil
+	}
+
+	workspaceTemplate, err := c.getWorkspaceTemplateByName(namespace, name)
+	if err != nil {
+		return false, nil, err
+	}
+	if workspaceTemplate != nil {
+		return true, &TemplateBundleTemplate{UID: workspaceTemplate.UID, Name: workspaceTemplate.Name}, nil
+	}
+
+	return workflowTemplate != nil, nil, nil
+}
+
+// planTemplateBundleImport decides the name, uid and collision handling of each template of the bundle,
+// so that an import that can't be done fails before anything is created
+func (c *Client) planTemplateBundleImport(namespace string, bundle *TemplateBundle, collision TemplateBundleCollision) ([]*templateBundleImport, error) {
+	imports := make([]*templateBundleImport, 0)
+	for _, template := range bundle.WorkflowTemplates {
+		imports = append(imports, &templateBundleImport{kind: templateBundleWorkflows, template: template})
+	}
+	for _, template := range bundle.WorkspaceTemplates {
+		imports = append(imports, &templateBundleImport{kind: templateBundleWorkspaces, template: template})
+	}
+
+	// uids of the templates that are created by this import
+	planned := make(map[string]bool)
+	for _, templateImport := range imports {
+		template := templateImport.template
+
+		uid, err := uid2.GenerateUID(template.Name, 30)
+		if err != nil {
+			return nil, util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Name of %v/%v must be 30 characters or less.", templateImport.kind, template.UID))
+		}
+
+		templateImport.result = &TemplateBundleImportResult{
+			Kind:      templateImport.kind,
+			SourceUID: template.UID,
+			UID:       uid,
+			Name:      template.Name,
+			Versions:  len(template.Versions),
+		}
+
+		taken, existing, err := c.templateBundleUIDTaken(namespace, templateImport.kind, template.Name, uid)
+		if err != nil {
+			return nil, err
+		}
+		if !taken && !planned[uid] {
+			planned[uid] = true
+			continue
+		}
+
+		switch collision {
+		case TemplateBundleCollisionNewVersion:
+			if existing == nil || planned[uid] {
+				return nil, util.NewUserError(codes.AlreadyExists, fmt.Sprintf("A template with the uid '%v' exists, and %v/%v can't be added to it as a new version.", uid, templateImport.kind, template.UID))
+			}
+			templateImport.result.UID = existing.UID
+			templateImport.result.Name = existing.Name
+		case TemplateBundleCollisionRename:
+			renamed := false
+			for attempt := 1; attempt <= templateBundleMaxRenames && !renamed; attempt++ {
+				name, err := templateBundleRename(template.Name, attempt, 30)
+				if err != nil {
+					return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+				}
+				uid, err = uid2.GenerateUID(name, 30)
+				if err != nil {
+					return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+				}
+
+				taken, _, err = c.templateBundleUIDTaken(namespace, templateImport.kind, name, uid)
+				if err != nil {
+					return nil, err
+				}
+				if !taken && !planned[uid] {
+					renamed = true
+					planned[uid] = true
+					templateImport.result.UID = uid
+					templateImport.result.Name = name
+				}
+			}
+			if !renamed {
+				return nil, util.NewUserError(codes.AlreadyExists, fmt.Sprintf("Unable to find a name for %v/%v that is not taken.", templateImport.kind, template.UID))
+			}
+		default:
+			return nil, util.NewUserError(codes.AlreadyExists, fmt.Sprintf("A template with the uid '%v' already exists.", uid))
+		}
+
+		templateImport.result.Collision = collision
+	}
+
+	return imports, nil
+}
+
+// importBundleTemplate creates the template, or adds its versions to the existing one, oldest version first.
+// If a version fails, the versions before it are kept, and result.Versions is set to their number.
+func (c *Client) importBundleTemplate(namespace string, templateImport *templateBundleImport) (err error) {
+	template := templateImport.template
+	result := templateImport.result
+
+	for i, version := range template.Versions {
+		create := i == 0 && result.Collision != TemplateBundleCollisionNewVersion
+
+		if templateImport.kind == templateBundleWorkflows {
+			workflowTemplate := &WorkflowTemplate{
+				UID:      result.UID,
+				Name:     result.Name,
+				Manifest: version.Manifest,
+				Labels:   template.Labels,
+			}
+			if create {
+				_, err = c.CreateWorkflowTemplate(namespace, workflowTemplate)
+			} else {
+				_, err = c.CreateWorkflowTemplateVersion(namespace, workflowTemplate)
+			}
+		} else {
+			workspaceTemplate := &WorkspaceTemplate{
+				UID:         result.UID,
+				Name:        result.Name,
+				Manifest:    version.Manifest,
+				Description: template.Description,
+				Labels:      template.Labels,
+			}
+			if create {
+				_, err = c.CreateWorkspaceTemplate(namespace, workspaceTemplate)
+			} else {
+				_, err = c.UpdateWorkspaceTemplate(namespace, workspaceTemplate)
+			}
+		}
+
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"Kind":      templateImport.kind,
+				"SourceUID": result.SourceUID,
+				"UID":       result.UID,
+				"Version":   version.Version,
+				"Error":     err.Error(),
+			}).Error("Could not import template version.")
+			result.Versions = i
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportTemplateBundle creates the templates of the bundle in the namespace, workflow templates first.
+// Templates whose uid is already taken are handled as collision says, and the import fails before anything is
+// created if one can't be.
+// If creating a template fails later on, the templates imported until then are kept. They are returned with the error,
+// which names them, so the rest can be imported again with the version or rename collision.
+func (c *Client) ImportTemplateBundle(namespace string, bundle *TemplateBundle, collision TemplateBundleCollision) ([]*TemplateBundleImportResult, error) {
+	if collision == "" {
+		collision = TemplateBundleCollisionFail
+	}
+	if err := collision.validate(); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+
+	imports, err := c.planTemplateBundleImport(namespace, bundle, collision)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*TemplateBundleImportResult, 0, len(imports))
+	for _, templateImport := range imports {
+		if err := c.importBundleTemplate(namespace, templateImport); err != nil {
+			if templateImport.result.Versions > 0 {
+				results = append(results, templateImport.result)
+			}
+			return results, templateBundleImportError(err, templateImport.kind, templateImport.result.SourceUID, results)
+		}
+		results = append(results, templateImport.result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,362 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/onepanelio/core/pkg/util"
+	uid2 "github.com/onepanelio/core/pkg/util/uid"
+	"google.golang.org/grpc/codes"
+	"io"
+	"io/ioutil"
+	"path"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A template bundle is a gzipped tarball with the same layout as the db/yaml directory of the migrations:
+//
+//	bundle.yaml
+//	workflows/<uid>/template.yaml
+//	workflows/<uid>/<version>.yaml
+//	workspaces/<uid>/template.yaml
+//	workspaces/<uid>/<version>.yaml
+//
+// template.yaml has the name, description and labels of the template, and each version file has its manifest.
+const (
+	templateBundleFormatVersion = 1
+	// templateBundleMaxSize bounds the uncompressed size of a bundle that is imported
+	templateBundleMaxSize = 64 << 20
+	// templateBundleMaxRenames is how many names are tried when an imported template is renamed
+	templateBundleMaxRenames = 100
+
+	templateBundleHeaderFile   = "bundle.yaml"
+	templateBundleTemplateFile = "template.yaml"
+	templateBundleWorkflows    = "workflows"
+	templateBundleWorkspaces   = "workspaces"
+)
+
+// TemplateBundleCollision is what an import does with a template whose uid already exists in the namespace
+type TemplateBundleCollision string
+
+const (
+	// TemplateBundleCollisionFail fails the import before anything is created
+	TemplateBundleCollisionFail TemplateBundleCollision = "fail"
+	// TemplateBundleCollisionRename imports the template with a new name, e.g. "name-2"
+	TemplateBundleCollisionRename TemplateBundleCollision = "rename"
+	// TemplateBundleCollisionNewVersion adds the versions of the bundle as new versions of the existing template
+	TemplateBundleCollisionNewVersion TemplateBundleCollision = "version"
+)
+
+// templateBundleHeader is the content of bundle.yaml
+type templateBundleHeader struct {
+	Version    int       `json:"version"`
+	Namespace  string    `json:"namespace"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// TemplateBundleVersion is a version of a template in a bundle
+type TemplateBundleVersion struct {
+	Version  int64
+	Manifest string
+}
+
+// TemplateBundleTemplate is a workflow or workspace template in a bundle, with its versions oldest first
+type TemplateBundleTemplate struct {
+	UID         string                   `json:"uid"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Labels      map[string]string        `json:"labels,omitempty"`
+	Versions    []*TemplateBundleVersion `json:"-"`
+}
+
+// TemplateBundle has workflow templates and workspace templates that can be moved between namespaces and clusters
+type TemplateBundle struct {
+	Namespace          string
+	ExportedAt         time.Time
+	WorkflowTemplates  []*TemplateBundleTemplate
+	WorkspaceTemplates []*TemplateBundleTemplate
+}
+
+// TemplateBundleSelection selects the versions of a workflow template to export. No versions selects all of them.
+type TemplateBundleSelection struct {
+	UID      string
+	Versions []int64
+}
+
+// TemplateBundleImportResult is what happened to a template of a bundle when it was imported
+type TemplateBundleImportResult struct {
+	// Kind is workflows or workspaces
+	Kind      string
+	SourceUID string
+	UID       string
+	Name      string
+	// Collision is empty if the template was created as is
+	Collision TemplateBundleCollision
+	Versions  int
+}
+
+// templateBundleImportError returns the error of an import that failed on a template.
+// Its message names the templates that were imported before, as they are kept.
+func templateBundleImportError(err error, kind, sourceUID string, imported []*TemplateBundleImportResult) error {
+	code := codes.Unknown
+	message := err.Error()
+	var userErr *util.UserError
+	if errors.As(err, &userErr) {
+		code = userErr.Code
+		message = strings.TrimSuffix(userErr.Message, ".")
+	}
+
+	message = fmt.Sprintf("Unable to import %v/%v: %v.", kind, sourceUID, message)
+	if len(imported) == 0 {
+		return util.NewUserError(code, message)
+	}
+
+	names := make([]string, 0, len(imported))
+	for _, result := range imported {
+		names = append(names, fmt.Sprintf("%v/%v (%v versions)", result.Kind, result.UID, result.Versions))
+	}
+
+	return util.NewUserError(code, fmt.Sprintf("%v Imported before the error: %v.", message, strings.Join(names, ", ")))
+}
+
+// validate returns an error if the collision handling is not known
+func (c TemplateBundleCollision) validate() error {
+	switch c {
+	case TemplateBundleCollisionFail, TemplateBundleCollisionRename, TemplateBundleCollisionNewVersion:
+		return nil
+	}
+
+	return fmt.Errorf("collision must be fail, rename or version")
+}
+
+// templateBundleRename returns the attempt-th name to try for a template whose name is taken, e.g. "name-2".
+// The name is shortened so its uid is not longer than max.
+func templateBundleRename(name string, attempt, max int) (string, error) {
+	suffix := fmt.Sprintf("-%v", attempt+1)
+	if len(name)+len(suffix) > max {
+		name = strings.TrimRight(name[:max-len(suffix)], " -")
+	}
+
+	renamed := name + suffix
+	if _, err := uid2.GenerateUID(renamed, max); err != nil {
+		return "", err
+	}
+
+	return renamed, nil
+}
+
+// sortVersions orders the versions of the template oldest first
+func (t *TemplateBundleTemplate) sortVersions() {
+	sort.Slice(t.Versions, func(i, j int) bool {
+		return t.Versions[i].Version < t.Versions[j].Version
+	})
+}
+
+// validate returns an error if the template has no name, no versions, or a uid that is not its directory
+func (t *TemplateBundleTemplate) validate(kind, uid string) error {
+	if t.Name == "" {
+		return fmt.Errorf("%v/%v has no name", kind, uid)
+	}
+	if t.UID != uid {
+		return fmt.Errorf("%v/%v has the uid '%v'", kind, uid, t.UID)
+	}
+	if len(t.Versions) == 0 {
+		return fmt.Errorf("%v/%v has no versions", kind, uid)
+	}
+
+	return nil
+}
+
+// writeTemplateBundleFile adds a file to the tarball
+func writeTemplateBundleFile(writer *tar.Writer, name string, content []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}
+	if err := writer.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := writer.Write(content)
+
+	return err
+}
+
+// Marshal returns the bundle as a gzipped tarball
+func (b *TemplateBundle) Marshal() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	header, err := yaml.Marshal(&templateBundleHeader{
+		Version:    templateBundleFormatVersion,
+		Namespace:  b.Namespace,
+		ExportedAt: b.ExportedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTemplateBundleFile(tarWriter, templateBundleHeaderFile, header, b.ExportedAt); err != nil {
+		return nil, err
+	}
+
+	kinds := map[string][]*TemplateBundleTemplate{
+		templateBundleWorkflows:  b.WorkflowTemplates,
+		templateBundleWorkspaces: b.WorkspaceTemplates,
+	}
+	for _, kind := range []string{templateBundleWorkflows, templateBundleWorkspaces} {
+		for _, template := range kinds[kind] {
+			template.sortVersions()
+
+			content, err := yaml.Marshal(template)
+			if err != nil {
+				return nil, err
+			}
+			if err := writeTemplateBundleFile(tarWriter, path.Join(kind, template.UID, templateBundleTemplateFile), content, b.ExportedAt); err != nil {
+				return nil, err
+			}
+
+			for _, version := range template.Versions {
+				name := path.Join(kind, template.UID, fmt.Sprintf("%v.yaml", version.Version))
+				if err := writeTemplateBundleFile(tarWriter, name, []byte(version.Manifest), b.ExportedAt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalTemplateBundle reads a bundle from a gzipped tarball, validating its layout
+func UnmarshalTemplateBundle(data []byte) (*TemplateBundle, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(io.LimitReader(gzipReader, templateBundleMaxSize))
+
+	var header *templateBundleHeader
+	templates := map[string]map[string]*TemplateBundleTemplate{
+		templateBundleWorkflows:  make(map[string]*TemplateBundleTemplate),
+		templateBundleWorkspaces: make(map[string]*TemplateBundleTemplate),
+	}
+	getTemplate := func(kind, uid string) *TemplateBundleTemplate {
+		template, ok := templates[kind][uid]
+		if !ok {
+			template = &TemplateBundleTemplate{}
+			templates[kind][uid] = template
+		}
+		return template
+	}
+
+	for {
+		entry, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.Typeflag == tar.TypeDir {
+			continue
+		}
+		if entry.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%v is not a regular file", entry.Name)
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(entry.Name)
+		if name == templateBundleHeaderFile {
+			header = &templateBundleHeader{}
+			if err := yaml.Unmarshal(content, header); err != nil {
+				return nil, fmt.Errorf("%v: %v", name, err)
+			}
+			continue
+		}
+
+		parts := strings.Split(name, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected file %v", entry.Name)
+		}
+		kind, uid, file := parts[0], parts[1], parts[2]
+		if _, ok := templates[kind]; !ok {
+			return nil, fmt.Errorf("unexpected file %v", entry.Name)
+		}
+
+		template := getTemplate(kind, uid)
+		if file == templateBundleTemplateFile {
+			versions := template.Versions
+			if err := yaml.Unmarshal(content, template); err != nil {
+				return nil, fmt.Errorf("%v: %v", name, err)
+			}
+			template.Versions = versions
+			continue
+		}
+
+		version, err := strconv.ParseInt(strings.TrimSuffix(file, ".yaml"), 10, 64)
+		if err != nil || !strings.HasSuffix(file, ".yaml") {
+			return nil, fmt.Errorf("unexpected file %v", entry.Name)
+		}
+		template.Versions = append(template.Versions, &TemplateBundleVersion{
+			Version:  version,
+			Manifest: string(content),
+		})
+	}
+
+	if header == nil {
+		return nil, errors.New(templateBundleHeaderFile + " is missing")
+	}
+	if header.Version != templateBundleFormatVersion {
+		return nil, fmt.Errorf("bundle version %v is not supported", header.Version)
+	}
+
+	bundle := &TemplateBundle{
+		Namespace:  header.Namespace,
+		ExportedAt: header.ExportedAt,
+	}
+	for _, kind := range []string{templateBundleWorkflows, templateBundleWorkspaces} {
+		uids := make([]string, 0, len(templates[kind]))
+		for uid := range templates[kind] {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+
+		for _, uid := range uids {
+			template := templates[kind][uid]
+			if err := template.validate(kind, uid); err != nil {
+				return nil, err
+			}
+			template.sortVersions()
+
+			if kind == templateBundleWorkflows {
+				bundle.WorkflowTemplates = append(bundle.WorkflowTemplates, template)
+			} else {
+				bundle.WorkspaceTemplates = append(bundle.WorkspaceTemplates, template)
+			}
+		}
+	}
+
+	return bundle, nil
+}
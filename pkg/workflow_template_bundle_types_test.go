@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"github.com/onepanelio/core/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"testing"
+	"time"
+)
+
+// tarballForTest returns a gzipped tarball with the files
+func tarballForTest(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		assert.Nil(t, writeTemplateBundleFile(tarWriter, name, []byte(content), time.Now()))
+	}
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+
+	return buffer.Bytes()
+}
+
+// TestTemplateBundle_Marshal makes sure a bundle is read back the same, with versions oldest first
+func TestTemplateBundle_Marshal(t *testing.T) {
+	bundle := &TemplateBundle{
+		Namespace:  "onepanel",
+		ExportedAt: time.Date(2020, 12, 15, 10, 0, 0, 0, time.UTC),
+		WorkflowTemplates: []*TemplateBundleTemplate{
+			{
+				UID:    "pytorch-training",
+				Name:   "PyTorch Training",
+				Labels: map[string]string{"framework": "pytorch"},
+				Versions: []*TemplateBundleVersion{
+					{Version: 20201221194344, Manifest: "entrypoint: main\n"},
+					{Version: 20200605090509, Manifest: "entrypoint: train\n"},
+				},
+			},
+		},
+		WorkspaceTemplates: []*TemplateBundleTemplate{
+			{
+				UID:         "jupyterlab",
+				Name:        "JupyterLab",
+				Description: "Notebooks",
+				Versions:    []*TemplateBundleVersion{{Version: 1, Manifest: "containers: []\n"}},
+			},
+		},
+	}
+
+	data, err := bundle.Marshal()
+	assert.Nil(t, err)
+
+	result, err := UnmarshalTemplateBundle(data)
+	assert.Nil(t, err)
+	assert.Equal(t, bundle, result)
+	assert.Equal(t, int64(20200605090509), result.WorkflowTemplates[0].Versions[0].Version)
+}
+
+// TestUnmarshalTemplateBundle_Invalid makes sure bundles that don't have the expected layout are rejected
+func TestUnmarshalTemplateBundle_Invalid(t *testing.T) {
+	header := "version: 1\nnamespace: onepanel\n"
+
+	_, err := UnmarshalTemplateBundle([]byte("not a tarball"))
+	assert.NotNil(t, err)
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"workflows/train/template.yaml": "uid: train\nname: Train\n",
+		"workflows/train/1.yaml":        "entrypoint: main\n",
+	}))
+	assert.EqualError(t, err, "bundle.yaml is missing")
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"bundle.yaml":                   "version: 2\n",
+		"workflows/train/template.yaml": "uid: train\nname: Train\n",
+		"workflows/train/1.yaml":        "entrypoint: main\n",
+	}))
+	assert.NotNil(t, err)
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"bundle.yaml":                   header,
+		"workflows/train/template.yaml": "uid: train\nname: Train\n",
+		"workflows/train/latest.yaml":   "entrypoint: main\n",
+	}))
+	assert.EqualError(t, err, "unexpected file workflows/train/latest.yaml")
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"bundle.yaml":            header,
+		"secrets/train/1.yaml":   "entrypoint: main\n",
+		"workflows/train/1.yaml": "entrypoint: main\n",
+	}))
+	assert.NotNil(t, err)
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"bundle.yaml":                   header,
+		"workflows/train/template.yaml": "uid: train\nname: Train\n",
+	}))
+	assert.EqualError(t, err, "workflows/train has no versions")
+
+	_, err = UnmarshalTemplateBundle(tarballForTest(t, map[string]string{
+		"bundle.yaml":                   header,
+		"workflows/train/template.yaml": "uid: export\nname: Train\n",
+		"workflows/train/1.yaml":        "entrypoint: main\n",
+	}))
+	assert.EqualError(t, err, "workflows/train has the uid 'export'")
+}
+
+// TestTemplateBundleRename makes sure renamed templates get a numbered suffix and still fit in a uid
+func TestTemplateBundleRename(t *testing.T) {
+	name, err := templateBundleRename("PyTorch Training", 1, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, "PyTorch Training-2", name)
+
+	name, err = templateBundleRename("TensorFlow Object Detection 01", 10, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, "TensorFlow Object Detection-11", name)
+}
+
+// TestTemplateBundleCollision_Validate makes sure only the known collision handling is accepted
+func TestTemplateBundleCollision_Validate(t *testing.T) {
+	assert.Nil(t, TemplateBundleCollisionRename.validate())
+	assert.Nil(t, TemplateBundleCollisionNewVersion.validate())
+	assert.NotNil(t, TemplateBundleCollision("overwrite").validate())
+}
+
+// TestTemplateBundleImportError makes sure the error keeps the code of the failure and names the imported templates
+func TestTemplateBundleImportError(t *testing.T) {
+	err := templateBundleImportError(util.NewUserError(codes.InvalidArgument, "Invalid manifest."), "workflows", "train", nil)
+	assert.Equal(t, codes.InvalidArgument, err.(*util.UserError).Code)
+	assert.EqualError(t, err, "Unable to import workflows/train: Invalid manifest.")
+
+	err = templateBundleImportError(errors.New("connection refused"), "workspaces", "jupyterlab", []*TemplateBundleImportResult{
+		{Kind: "workflows", UID: "train", Versions: 2},
+	})
+	assert.Equal(t, codes.Unknown, err.(*util.UserError).Code)
+	assert.EqualError(t, err, "Unable to import workspaces/jupyterlab: connection refused. Imported before the error: workflows/train (2 versions).")
+}
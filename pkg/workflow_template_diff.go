@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+// DiffWorkflowTemplateVersions returns what changed from one version of the workflow template identified by
+// (namespace, uid) to another. A toVersion of 0 compares with the latest version.
+func (c *Client) DiffWorkflowTemplateVersions(namespace, uid string, fromVersion, toVersion int64) (*WorkflowTemplateDiff, error) {
+	if fromVersion == 0 {
+		return nil, util.NewUserError(codes.InvalidArgument, "From version is required.")
+	}
+
+	from, err := c.GetWorkflowTemplate(namespace, uid, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := c.GetWorkflowTemplate(namespace, uid, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := diffWorkflowTemplates(from, to)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Namespace":   namespace,
+			"UID":         uid,
+			"FromVersion": fromVersion,
+			"ToVersion":   toVersion,
+			"Error":       err.Error(),
+		}).Error("Diff Workflow Template versions failed.")
+		return nil, util.NewUserError(codes.InvalidArgument, "Unable to parse the workflow template manifests.")
+	}
+
+	return diff, nil
+}
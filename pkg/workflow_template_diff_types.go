@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"fmt"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+	"sort"
+)
+
+// WorkflowTemplateDiffChange is how an item changed between two versions of a workflow template
+type WorkflowTemplateDiffChange string
+
+const (
+	WorkflowTemplateDiffAdded   WorkflowTemplateDiffChange = "added"
+	WorkflowTemplateDiffRemoved WorkflowTemplateDiffChange = "removed"
+	WorkflowTemplateDiffChanged WorkflowTemplateDiffChange = "changed"
+)
+
+// WorkflowTemplateDiffEntry is an item that is different between two versions of a workflow template.
+// From is empty for added items, and To is empty for removed ones.
+type WorkflowTemplateDiffEntry struct {
+	Name   string
+	Change WorkflowTemplateDiffChange
+	From   string
+	To     string
+}
+
+// WorkflowTemplateDiff is what changed between two versions of a workflow template
+type WorkflowTemplateDiff struct {
+	FromVersion int64
+	ToVersion   int64
+	Parameters  []*WorkflowTemplateDiffEntry
+	Templates   []*WorkflowTemplateDiffEntry
+	// Images are keyed by template, and template/container for init containers and sidecars
+	Images []*WorkflowTemplateDiffEntry
+	// Resources are keyed like Images, with the requests and limits as yaml
+	Resources []*WorkflowTemplateDiffEntry
+	// UnifiedDiff is the unified text diff of the manifests
+	UnifiedDiff string
+}
+
+// diffStringMaps returns the keys that were added, removed or changed from one map to another, sorted by name
+func diffStringMaps(from, to map[string]string) []*WorkflowTemplateDiffEntry {
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	entries := make([]*WorkflowTemplateDiffEntry, 0)
+	for _, name := range names {
+		fromValue, inFrom := from[name]
+		toValue, inTo := to[name]
+
+		entry := &WorkflowTemplateDiffEntry{Name: name, From: fromValue, To: toValue}
+		switch {
+		case !inFrom:
+			entry.Change = WorkflowTemplateDiffAdded
+		case !inTo:
+			entry.Change = WorkflowTemplateDiffRemoved
+		case fromValue != toValue:
+			entry.Change = WorkflowTemplateDiffChanged
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// workflowTemplateDiffSpec has the templates, images and resources of a manifest, each keyed by name
+type workflowTemplateDiffSpec struct {
+	templates map[string]string
+	images    map[string]string
+	resources map[string]string
+}
+
+// addContainer adds the image and resources of the container, if it has them
+func (s *workflowTemplateDiffSpec) addContainer(name string, container *corev1.Container) error {
+	if container.Image != "" {
+		s.images[name] = container.Image
+	}
+
+	if len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0 {
+		return nil
+	}
+
+	resources, err := yaml.Marshal(container.Resources)
+	if err != nil {
+		return err
+	}
+	s.resources[name] = string(resources)
+
+	return nil
+}
+
+// parseWorkflowTemplateDiffSpec reads the templates of the manifest, which is the spec of a workflow, as yaml
+func parseWorkflowTemplateDiffSpec(manifest string) (*workflowTemplateDiffSpec, error) {
+	spec := &wfv1.WorkflowSpec{}
+	if err := yaml.Unmarshal([]byte(manifest), spec); err != nil {
+		return nil, err
+	}
+
+	result := &workflowTemplateDiffSpec{
+		templates: make(map[string]string),
+		images:    make(map[string]string),
+		resources: make(map[string]string),
+	}
+	for i := range spec.Templates {
+		template := &spec.Templates[i]
+
+		templateYaml, err := yaml.Marshal(template)
+		if err != nil {
+			return nil, err
+		}
+		result.templates[template.Name] = string(templateYaml)
+
+		if template.Container != nil {
+			if err := result.addContainer(template.Name, template.Container); err != nil {
+				return nil, err
+			}
+		}
+		if template.Script != nil {
+			if err := result.addContainer(template.Name, &template.Script.Container); err != nil {
+				return nil, err
+			}
+		}
+		for j := range template.InitContainers {
+			container := &template.InitContainers[j].Container
+			if err := result.addContainer(template.Name+"/"+container.Name, container); err != nil {
+				return nil, err
+			}
+		}
+		for j := range template.Sidecars {
+			container := &template.Sidecars[j].Container
+			if err := result.addContainer(template.Name+"/"+container.Name, container); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// unifiedManifestDiff returns the unified text diff of the manifests of two versions of a workflow template
+func unifiedManifestDiff(from, to *WorkflowTemplate) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from.Manifest),
+		B:        difflib.SplitLines(to.Manifest),
+		FromFile: fmt.Sprintf("%v@%v", from.UID, from.Version),
+		ToFile:   fmt.Sprintf("%v@%v", to.UID, to.Version),
+		Context:  3,
+	})
+}
+
+// diffWorkflowTemplates returns what changed from one version of a workflow template to another
+func diffWorkflowTemplates(from, to *WorkflowTemplate) (*WorkflowTemplateDiff, error) {
+	fromParameters, err := from.GetParametersKeyString()
+	if err != nil {
+		return nil, err
+	}
+	toParameters, err := to.GetParametersKeyString()
+	if err != nil {
+		return nil, err
+	}
+
+	fromSpec, err := parseWorkflowTemplateDiffSpec(from.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	toSpec, err := parseWorkflowTemplateDiffSpec(to.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	unifiedDiff, err := unifiedManifestDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowTemplateDiff{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Parameters:  diffStringMaps(fromParameters, toParameters),
+		Templates:   diffStringMaps(fromSpec.templates, toSpec.templates),
+		Images:      diffStringMaps(fromSpec.images, toSpec.images),
+		Resources:   diffStringMaps(fromSpec.resources, toSpec.resources),
+		UnifiedDiff: unifiedDiff,
+	}, nil
+}
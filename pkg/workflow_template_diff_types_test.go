@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const diffFromManifest = `arguments:
+  parameters:
+  - name: epochs
+    value: 10
+  - name: dataset
+    value: coco
+entrypoint: main
+templates:
+- name: main
+  container:
+    image: tensorflow/tensorflow:2.3.0
+    command: [python, train.py]
+    resources:
+      limits:
+        nvidia.com/gpu: 1
+- name: export
+  script:
+    image: python:3.8
+    source: print("export")
+`
+
+const diffToManifest = `arguments:
+  parameters:
+  - name: epochs
+    value: 20
+  - name: dataset
+    value: coco
+  - name: batch-size
+    value: 32
+entrypoint: main
+templates:
+- name: main
+  container:
+    image: tensorflow/tensorflow:2.4.0
+    command: [python, train.py]
+    resources:
+      limits:
+        nvidia.com/gpu: 2
+  sidecars:
+  - name: tensorboard
+    image: tensorflow/tensorflow:2.4.0
+`
+
+// TestDiffStringMaps makes sure added, removed and changed keys are returned by name, and equal ones are skipped
+func TestDiffStringMaps(t *testing.T) {
+	entries := diffStringMaps(
+		map[string]string{"a": "1", "b": "2", "c": "3"},
+		map[string]string{"a": "1", "b": "4", "d": "5"},
+	)
+
+	assert.Equal(t, []*WorkflowTemplateDiffEntry{
+		{Name: "b", Change: WorkflowTemplateDiffChanged, From: "2", To: "4"},
+		{Name: "c", Change: WorkflowTemplateDiffRemoved, From: "3"},
+		{Name: "d", Change: WorkflowTemplateDiffAdded, To: "5"},
+	}, entries)
+
+	assert.Empty(t, diffStringMaps(nil, nil))
+}
+
+// TestDiffWorkflowTemplates makes sure parameters, templates, images and resources are compared
+func TestDiffWorkflowTemplates(t *testing.T) {
+	from := &WorkflowTemplate{UID: "train", Version: 1, Manifest: diffFromManifest}
+	to := &WorkflowTemplate{UID: "train", Version: 2, Manifest: diffToManifest}
+
+	diff, err := diffWorkflowTemplates(from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), diff.FromVersion)
+	assert.Equal(t, int64(2), diff.ToVersion)
+
+	changes := func(entries []*WorkflowTemplateDiffEntry) map[string]WorkflowTemplateDiffChange {
+		result := make(map[string]WorkflowTemplateDiffChange)
+		for _, entry := range entries {
+			result[entry.Name] = entry.Change
+		}
+		return result
+	}
+
+	assert.Equal(t, map[string]WorkflowTemplateDiffChange{
+		"epochs":     WorkflowTemplateDiffChanged,
+		"batch-size": WorkflowTemplateDiffAdded,
+	}, changes(diff.Parameters))
+	assert.Equal(t, map[string]WorkflowTemplateDiffChange{
+		"main":   WorkflowTemplateDiffChanged,
+		"export": WorkflowTemplateDiffRemoved,
+	}, changes(diff.Templates))
+	assert.Equal(t, []*WorkflowTemplateDiffEntry{
+		{Name: "export", Change: WorkflowTemplateDiffRemoved, From: "python:3.8"},
+		{Name: "main", Change: WorkflowTemplateDiffChanged, From: "tensorflow/tensorflow:2.3.0", To: "tensorflow/tensorflow:2.4.0"},
+		{Name: "main/tensorboard", Change: WorkflowTemplateDiffAdded, To: "tensorflow/tensorflow:2.4.0"},
+	}, diff.Images)
+	assert.Len(t, diff.Resources, 1)
+	assert.Equal(t, "main", diff.Resources[0].Name)
+	assert.Contains(t, diff.Resources[0].To, "nvidia.com/gpu: \"2\"")
+
+	assert.True(t, strings.HasPrefix(diff.UnifiedDiff, "--- train@1\n+++ train@2\n"))
+	assert.Contains(t, diff.UnifiedDiff, "-    value: 10\n+    value: 20\n")
+}
+
+// TestDiffWorkflowTemplates_Same makes sure a version has no changes from itself
+func TestDiffWorkflowTemplates_Same(t *testing.T) {
+	wt := &WorkflowTemplate{UID: "train", Version: 1, Manifest: diffFromManifest}
+
+	diff, err := diffWorkflowTemplates(wt, wt)
+	assert.Nil(t, err)
+	assert.Empty(t, diff.Parameters)
+	assert.Empty(t, diff.Templates)
+	assert.Empty(t, diff.Images)
+	assert.Empty(t, diff.Resources)
+	assert.Empty(t, diff.UnifiedDiff)
+}
@@ -0,0 +1,336 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/onepanelio/core/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"strings"
+	"time"
+)
+
+// CreateWorkflowTemplateSource registers a git repository, branch and path whose workflow templates are synced to
+// the namespace. The first sync happens with the next run of SyncWorkflowTemplateSources.
+func (c *Client) CreateWorkflowTemplateSource(namespace string, source *WorkflowTemplateSource) (*WorkflowTemplateSource, error) {
+	if err := source.validate(); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if err := source.GenerateUID(source.Name); err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+
+	count := 0
+	err := sb.Select("COUNT(*)").
+		From("workflow_template_sources").
+		Where(sq.Eq{"namespace": namespace, "uid": source.UID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, util.NewUserError(codes.AlreadyExists, "Workflow template source already exists.")
+	}
+
+	source.Namespace = namespace
+	err = sb.Insert("workflow_template_sources").
+		SetMap(sq.Eq{
+			"uid":           source.UID,
+			"name":          source.Name,
+			"namespace":     namespace,
+			"repository":    source.Repository,
+			"branch":        source.Branch,
+			"path":          source.Path,
+			"sync_interval": source.SyncInterval,
+		}).
+		Suffix("RETURNING id, created_at").
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&source.ID, &source.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// GetWorkflowTemplateSource returns the source identified by (namespace, uid)
+func (c *Client) GetWorkflowTemplateSource(namespace, uid string) (*WorkflowTemplateSource, error) {
+	query := sb.Select(getWorkflowTemplateSourceColumns("wts")...).
+		From("workflow_template_sources wts").
+		Where(sq.Eq{
+			"wts.namespace": namespace,
+			"wts.uid":       uid,
+		})
+
+	source := &WorkflowTemplateSource{}
+	if err := c.DB.Getx(source, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, util.NewUserError(codes.NotFound, "Workflow template source not found.")
+		}
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// ListWorkflowTemplateSources returns the sources of the namespace, ordered by name
+func (c *Client) ListWorkflowTemplateSources(namespace string) (sources []*WorkflowTemplateSource, err error) {
+	query := sb.Select(getWorkflowTemplateSourceColumns("wts")...).
+		From("workflow_template_sources wts").
+		Where(sq.Eq{"wts.namespace": namespace}).
+		OrderBy("wts.name")
+
+	sources = make([]*WorkflowTemplateSource, 0)
+	err = c.DB.Selectx(&sources, query)
+
+	return
+}
+
+// ListWorkflowTemplateSourceFiles returns the files of the source identified by (namespace, uid) that are synced
+// to workflow templates, ordered by path
+func (c *Client) ListWorkflowTemplateSourceFiles(namespace, uid string) (files []*WorkflowTemplateSourceFile, err error) {
+	query := sb.Select(getWorkflowTemplateSourceFileColumns("wtsf")...).
+		From("workflow_template_source_files wtsf").
+		Join("workflow_template_sources wts ON wts.id = wtsf.source_id").
+		Where(sq.Eq{
+			"wts.namespace": namespace,
+			"wts.uid":       uid,
+		}).
+		OrderBy("wtsf.path")
+
+	files = make([]*WorkflowTemplateSourceFile, 0)
+	err = c.DB.Selectx(&files, query)
+
+	return
+}
+
+// DeleteWorkflowTemplateSource stops syncing the source identified by (namespace, uid).
+// The workflow templates it created are kept.
+func (c *Client) DeleteWorkflowTemplateSource(namespace, uid string) error {
+	result, err := sb.Delete("workflow_template_sources").
+		Where(sq.Eq{
+			"namespace": namespace,
+			"uid":       uid,
+		}).
+		RunWith(c.DB).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return util.NewUserError(codes.NotFound, "Workflow template source not found.")
+	}
+
+	return nil
+}
+
+// syncWorkflowTemplateSourceFile creates the workflow template of a file that changed, or a new version of it,
+// labelled with the source and the commit SHA
+func (c *Client) syncWorkflowTemplateSourceFile(source *WorkflowTemplateSource, sha, filePath, content string, file *WorkflowTemplateSourceFile) error {
+	name, err := workflowTemplateSourceName(filePath)
+	if err != nil {
+		return err
+	}
+
+	workflowTemplate := &WorkflowTemplate{
+		Name:     name,
+		Manifest: content,
+		Labels: map[string]string{
+			WorkflowTemplateSourceLabel:       source.UID,
+			WorkflowTemplateSourceCommitLabel: sha,
+		},
+	}
+	if err := workflowTemplate.GenerateUID(name); err != nil {
+		return err
+	}
+
+	if file != nil {
+		if _, err := c.CreateWorkflowTemplateVersion(source.Namespace, workflowTemplate); err != nil {
+			return fmt.Errorf("%v: %v", filePath, err)
+		}
+	} else {
+		existing, err := c.selectWorkflowTemplateByUID(source.Namespace, workflowTemplate.UID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%v: workflow template '%v' already exists and is not synced from this source", filePath, workflowTemplate.UID)
+		}
+
+		if _, err := c.CreateWorkflowTemplate(source.Namespace, workflowTemplate); err != nil {
+			return fmt.Errorf("%v: %v", filePath, err)
+		}
+	}
+
+	_, err = sb.Insert("workflow_template_source_files").
+		SetMap(sq.Eq{
+			"source_id":             source.ID,
+			"path":                  filePath,
+			"content_sha256":        workflowTemplateSourceContentSHA256(content),
+			"commit_sha":            sha,
+			"workflow_template_uid": workflowTemplate.UID,
+		}).
+		Suffix(`ON CONFLICT (source_id, path) DO UPDATE SET
+			content_sha256 = EXCLUDED.content_sha256,
+			commit_sha = EXCLUDED.commit_sha,
+			workflow_template_uid = EXCLUDED.workflow_template_uid,
+			modified_at = NOW() at time zone 'utc'`).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// lockWorkflowTemplateSource takes the advisory lock of the source in a new transaction, and returns that transaction.
+// It makes sure a source is synced by one server at a time, so the versions of a change are not created twice.
+// The lock is released when the transaction ends.
+func (c *Client) lockWorkflowTemplateSource(sourceID uint64) (*sql.Tx, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", fmt.Sprintf("workflow-template-source/%v", sourceID)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// syncWorkflowTemplateSource pulls the source, and creates a workflow template version for each file whose content
+// changed since the last sync. Files that fail are recorded in LastError, and don't stop the others.
+// Workflow templates of files that are removed from the repository are kept.
+// If onlyIfUnsynced is true, nothing is done if the source was synced by another server since it was read.
+func (c *Client) syncWorkflowTemplateSource(source *WorkflowTemplateSource, onlyIfUnsynced bool) error {
+	tx, err := c.lockWorkflowTemplateSource(source.ID)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var lastSyncedAt *time.Time
+	err = sb.Select("last_synced_at").
+		From("workflow_template_sources").
+		Where(sq.Eq{"id": source.ID}).
+		RunWith(c.DB).
+		QueryRow().
+		Scan(&lastSyncedAt)
+	if err == sql.ErrNoRows {
+		return nil // deleted while waiting for the lock
+	}
+	if err != nil {
+		return err
+	}
+	if onlyIfUnsynced && !sameSyncTime(lastSyncedAt, source.LastSyncedAt) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workflowTemplateSourceGitTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	sha, files, err := gitWorkflowTemplateSourceFiles(ctx, source.Repository, source.Branch, source.Path)
+	if err != nil {
+		source.LastError = err.Error()
+	} else {
+		existingFiles, err := c.ListWorkflowTemplateSourceFiles(source.Namespace, source.UID)
+		if err != nil {
+			return err
+		}
+		filesByPath := make(map[string]*WorkflowTemplateSourceFile)
+		for _, file := range existingFiles {
+			filesByPath[file.Path] = file
+		}
+
+		errs := make([]string, 0)
+		for _, filePath := range sortedWorkflowTemplateSourcePaths(files) {
+			content := files[filePath]
+			file := filesByPath[filePath]
+			if file != nil && file.ContentSHA256 == workflowTemplateSourceContentSHA256(content) {
+				continue
+			}
+
+			if err := c.syncWorkflowTemplateSourceFile(source, sha, filePath, content, file); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+
+		source.LastCommitSHA = sha
+		source.LastError = strings.Join(errs, "; ")
+	}
+	source.LastSyncedAt = &now
+
+	if source.LastError != "" {
+		log.WithFields(log.Fields{
+			"Namespace":  source.Namespace,
+			"UID":        source.UID,
+			"Repository": source.Repository,
+			"Error":      source.LastError,
+		}).Error("Workflow template source sync failed.")
+	}
+
+	_, err = sb.Update("workflow_template_sources").
+		SetMap(sq.Eq{
+			"last_commit_sha": source.LastCommitSHA,
+			"last_synced_at":  source.LastSyncedAt,
+			"last_error":      source.LastError,
+		}).
+		Where(sq.Eq{"id": source.ID}).
+		RunWith(c.DB).
+		Exec()
+
+	return err
+}
+
+// SyncWorkflowTemplateSource pulls the source identified by (namespace, uid) right away, and returns it updated
+func (c *Client) SyncWorkflowTemplateSource(namespace, uid string) (*WorkflowTemplateSource, error) {
+	source, err := c.GetWorkflowTemplateSource(namespace, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.syncWorkflowTemplateSource(source, false); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// SyncWorkflowTemplateSources pulls the sources of every namespace whose sync interval has passed since their last sync
+func (c *Client) SyncWorkflowTemplateSources() error {
+	query := sb.Select(getWorkflowTemplateSourceColumns("wts")...).
+		From("workflow_template_sources wts").
+		Where(sq.Or{
+			sq.Eq{"wts.last_synced_at": nil},
+			sq.Expr("wts.last_synced_at + wts.sync_interval * INTERVAL '1 second' <= ?", time.Now().UTC()),
+		}).
+		OrderBy("wts.last_synced_at NULLS FIRST", "wts.id")
+
+	sources := make([]*WorkflowTemplateSource, 0)
+	if err := c.DB.Selectx(&sources, query); err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		if err := c.syncWorkflowTemplateSource(source, true); err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": source.Namespace,
+				"UID":       source.UID,
+				"Error":     err.Error(),
+			}).Error("Unable to sync workflow template source.")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+// createWorkflowTemplateSourceForTest inserts a source of the local repository. validate refuses local paths,
+// so the source is inserted as is.
+func createWorkflowTemplateSourceForTest(t *testing.T, repository *gitRepositoryForTest) *WorkflowTemplateSource {
+	_, err := database.Exec(`DELETE FROM workflow_template_sources`)
+	assert.Nil(t, err)
+
+	source := &WorkflowTemplateSource{
+		Name:         "pipelines",
+		Namespace:    "onepanel",
+		Repository:   repository.bare,
+		Branch:       repository.branch,
+		SyncInterval: 300,
+	}
+	assert.Nil(t, source.GenerateUID(source.Name))
+
+	err = database.QueryRow(`
+		INSERT INTO workflow_template_sources (uid, name, namespace, repository, branch, path, sync_interval)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		source.UID, source.Name, source.Namespace, source.Repository, source.Branch, source.Path, source.SyncInterval).
+		Scan(&source.ID)
+	assert.Nil(t, err)
+
+	return source
+}
+
+// workflowTemplateSourceCommitsForTest returns the commit label of each version of the workflow template,
+// checking that they are labelled with the source
+func workflowTemplateSourceCommitsForTest(t *testing.T, c *Client, source *WorkflowTemplateSource, uid string) []string {
+	versions, err := c.ListWorkflowTemplateVersionsModels(source.Namespace, uid)
+	assert.Nil(t, err)
+
+	commits := make([]string, 0)
+	for _, version := range versions {
+		assert.Equal(t, source.UID, version.Labels[WorkflowTemplateSourceLabel])
+		commits = append(commits, version.Labels[WorkflowTemplateSourceCommitLabel])
+	}
+
+	return commits
+}
+
+// TestClient_SyncWorkflowTemplateSource makes sure a version is created for each file that changed, labelled with
+// the source and the commit, and that a sync another server already did is not repeated
+func TestClient_SyncWorkflowTemplateSource(t *testing.T) {
+	repository := newGitRepositoryForTest(t)
+	defer os.RemoveAll(repository.dir)
+
+	c := DefaultTestClient()
+	clearDatabase(t)
+	source := createWorkflowTemplateSourceForTest(t, repository)
+
+	firstSHA := repository.commit(map[string]string{
+		"train.yaml":  defaultWorkflowTemplate,
+		"export.yaml": defaultWorkflowTemplate,
+	})
+	assert.Nil(t, c.syncWorkflowTemplateSource(source, false))
+	assert.Empty(t, source.LastError)
+	assert.Equal(t, firstSHA, source.LastCommitSHA)
+
+	secondSHA := repository.commit(map[string]string{
+		"train.yaml": defaultWorkflowTemplate + "\n# Trains for longer\n",
+	})
+	assert.Nil(t, c.syncWorkflowTemplateSource(source, false))
+	assert.Empty(t, source.LastError)
+
+	assert.ElementsMatch(t, []string{firstSHA, secondSHA}, workflowTemplateSourceCommitsForTest(t, c, source, "train"))
+	assert.ElementsMatch(t, []string{firstSHA}, workflowTemplateSourceCommitsForTest(t, c, source, "export"))
+
+	// A server that read the source before the last sync skips it
+	stale := *source
+	stale.LastSyncedAt = nil
+	repository.commit(map[string]string{
+		"export.yaml": defaultWorkflowTemplate + "\n# Exports more\n",
+	})
+	assert.Nil(t, c.syncWorkflowTemplateSource(&stale, true))
+	assert.ElementsMatch(t, []string{firstSHA}, workflowTemplateSourceCommitsForTest(t, c, source, "export"))
+
+	files, err := c.ListWorkflowTemplateSourceFiles(source.Namespace, source.UID)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+}
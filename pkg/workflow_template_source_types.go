@@ -0,0 +1,328 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/onepanelio/core/pkg/util/sql"
+	uid2 "github.com/onepanelio/core/pkg/util/uid"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultWorkflowTemplateSourceSyncInterval is how often a source is pulled if it has no interval
+	defaultWorkflowTemplateSourceSyncInterval = 5 * time.Minute
+	// minWorkflowTemplateSourceSyncInterval is the shortest interval a source can be pulled at
+	minWorkflowTemplateSourceSyncInterval = time.Minute
+	// workflowTemplateSourceGitTimeout bounds the time git has to fetch a source
+	workflowTemplateSourceGitTimeout = 2 * time.Minute
+	// workflowTemplateSourceLookupTimeout bounds the time the host of a repository has to resolve when a source is validated
+	workflowTemplateSourceLookupTimeout = 10 * time.Second
+
+	// WorkflowTemplateSourceLabel is the label with the uid of the source on the versions it creates
+	WorkflowTemplateSourceLabel = "template-source"
+	// WorkflowTemplateSourceCommitLabel is the label with the commit SHA on the versions a source creates
+	WorkflowTemplateSourceCommitLabel = "git-commit"
+)
+
+// workflowTemplateSourceRepositoryPattern matches the repositories a source can pull: http(s), ssh and git URLs,
+// and scp-like ssh addresses such as git@github.com:onepanelio/templates.git
+var workflowTemplateSourceRepositoryPattern = regexp.MustCompile(`^((https?|ssh|git)://[^\s]+|[\w.-]+@[\w.-]+:[^\s]+)$`)
+
+// workflowTemplateSourceBranchPattern matches the branch names a source can pull
+var workflowTemplateSourceBranchPattern = regexp.MustCompile(`^[\w][\w./-]*$`)
+
+// WorkflowTemplateSource is a path in a branch of a git repository whose YAML files are synced to the workflow
+// templates of a namespace. Each file is a workflow template named after the file.
+type WorkflowTemplateSource struct {
+	ID         uint64
+	UID        string
+	Name       string
+	Namespace  string
+	Repository string
+	Branch     string
+	// Path is the directory, or file, in the repository. Empty is the root of the repository.
+	Path string
+	// SyncInterval is how often the source is pulled, in seconds
+	SyncInterval  int64      `db:"sync_interval"`
+	LastCommitSHA string     `db:"last_commit_sha"`
+	LastSyncedAt  *time.Time `db:"last_synced_at"`
+	// LastError is why the last sync, or some of its files, failed
+	LastError  string     `db:"last_error"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ModifiedAt *time.Time `db:"modified_at"`
+}
+
+// WorkflowTemplateSourceFile is a file of a source and the workflow template it is synced to
+type WorkflowTemplateSourceFile struct {
+	ID                  uint64
+	SourceID            uint64 `db:"source_id"`
+	Path                string
+	ContentSHA256       string     `db:"content_sha256"`
+	CommitSHA           string     `db:"commit_sha"`
+	WorkflowTemplateUID string     `db:"workflow_template_uid"`
+	CreatedAt           time.Time  `db:"created_at"`
+	ModifiedAt          *time.Time `db:"modified_at"`
+}
+
+// GenerateUID generates a uid from the input name and sets it on the source
+func (s *WorkflowTemplateSource) GenerateUID(name string) error {
+	result, err := uid2.GenerateUID(name, 63)
+	if err != nil {
+		return err
+	}
+
+	s.UID = result
+
+	return nil
+}
+
+// validate returns an error if the source can't be pulled. It cleans the path and sets the default sync interval.
+func (s *WorkflowTemplateSource) validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	if !workflowTemplateSourceRepositoryPattern.MatchString(s.Repository) {
+		return errors.New("repository must be an http(s), ssh or git URL")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), workflowTemplateSourceLookupTimeout)
+	defer cancel()
+	if err := checkWorkflowTemplateSourceHost(ctx, s.Repository); err != nil {
+		return err
+	}
+	if !workflowTemplateSourceBranchPattern.MatchString(s.Branch) || strings.Contains(s.Branch, "..") {
+		return errors.New("branch is not a valid branch name")
+	}
+
+	if strings.Contains(s.Path, "..") {
+		return errors.New("path must be inside the repository")
+	}
+	s.Path = strings.TrimPrefix(path.Clean("/"+s.Path), "/")
+
+	if s.SyncInterval == 0 {
+		s.SyncInterval = int64(defaultWorkflowTemplateSourceSyncInterval.Seconds())
+	}
+	if s.SyncInterval < int64(minWorkflowTemplateSourceSyncInterval.Seconds()) {
+		return fmt.Errorf("sync interval must be at least %v seconds", minWorkflowTemplateSourceSyncInterval.Seconds())
+	}
+
+	return nil
+}
+
+// workflowTemplateSourceHost returns the host of a repository URL or scp-like ssh address, or an empty string if it has none
+func workflowTemplateSourceHost(repository string) string {
+	if target, err := url.Parse(repository); err == nil && target.Scheme != "" {
+		return target.Hostname()
+	}
+
+	// user@host:path
+	if at := strings.Index(repository, "@"); at >= 0 {
+		host := repository[at+1:]
+		if colon := strings.Index(host, ":"); colon >= 0 {
+			return host[:colon]
+		}
+	}
+
+	return ""
+}
+
+// checkWorkflowTemplateSourceHost returns an error if the host of the repository is internal, see isExternalHost,
+// so sources can't be used to reach the cluster and its hosts
+func checkWorkflowTemplateSourceHost(ctx context.Context, repository string) error {
+	host := workflowTemplateSourceHost(repository)
+	if host != "" && !isExternalHost(ctx, host) {
+		return fmt.Errorf("repository host '%v' can't be a loopback, link-local or private address", host)
+	}
+
+	return nil
+}
+
+// isWorkflowTemplateSourceFile returns true if the file of a source is a workflow template
+func isWorkflowTemplateSourceFile(name string) bool {
+	extension := strings.ToLower(path.Ext(name))
+
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// workflowTemplateSourceName returns the name of the workflow template of a file, which is its name without the extension
+func workflowTemplateSourceName(filePath string) (string, error) {
+	base := path.Base(filePath)
+	name := strings.TrimSuffix(base, path.Ext(base))
+
+	if _, err := uid2.GenerateUID(name, 30); err != nil {
+		return "", fmt.Errorf("%v: the name of the file must be 30 characters or less", filePath)
+	}
+
+	return name, nil
+}
+
+// workflowTemplateSourceContentSHA256 returns the hash that is compared to tell if a file changed
+func workflowTemplateSourceContentSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// runGit runs git with the arguments, returning its output, or its error output as the error
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %v: %v", args[0], message)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitWorkflowTemplateSourceFiles clones the branch of the repository and returns its commit SHA, and the
+// workflow template files under sourcePath keyed by their path in the repository
+func gitWorkflowTemplateSourceFiles(ctx context.Context, repository, branch, sourcePath string) (sha string, files map[string]string, err error) {
+	// The host is resolved again, as the addresses of a name can change after the source is validated
+	if err := checkWorkflowTemplateSourceHost(ctx, repository); err != nil {
+		return "", nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "workflow-template-source-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := runGit(ctx, dir, "clone", "--quiet", "--depth", "1", "--single-branch", "--branch", branch, "--", repository, "repository"); err != nil {
+		return "", nil, err
+	}
+
+	repositoryDir, err := filepath.EvalSymlinks(filepath.Join(dir, "repository"))
+	if err != nil {
+		return "", nil, err
+	}
+	sha, err = runGit(ctx, repositoryDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The path is resolved, so symbolic links of the repository in it can't lead outside of the repository
+	root, err := filepath.EvalSymlinks(filepath.Join(repositoryDir, filepath.FromSlash(sourcePath)))
+	if err != nil {
+		return "", nil, fmt.Errorf("path '%v' not found in branch '%v'", sourcePath, branch)
+	}
+	if relativeRoot, err := filepath.Rel(repositoryDir, root); err != nil || relativeRoot == ".." ||
+		strings.HasPrefix(relativeRoot, ".."+string(filepath.Separator)) {
+		return "", nil, fmt.Errorf("path '%v' leads outside of the repository", sourcePath)
+	}
+
+	files = make(map[string]string)
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Symbolic links under root are skipped, not followed, so files outside of the repository can't be read
+		if !info.Mode().IsRegular() || !isWorkflowTemplateSourceFile(info.Name()) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(repositoryDir, filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = string(content)
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sha, files, nil
+}
+
+// sameSyncTime returns true if both sync times are nil, or are the same time
+func sameSyncTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
+
+// sortedWorkflowTemplateSourcePaths returns the paths of the files in order, so syncs are repeatable
+func sortedWorkflowTemplateSourcePaths(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// getWorkflowTemplateSourceColumns returns all of the columns for workflowTemplateSource modified by alias, destination.
+// see formatColumnSelect
+func getWorkflowTemplateSourceColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"uid",
+		"name",
+		"namespace",
+		"repository",
+		"branch",
+		"path",
+		"sync_interval",
+		"last_commit_sha",
+		"last_synced_at",
+		"last_error",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
+
+// getWorkflowTemplateSourceFileColumns returns all of the columns for workflowTemplateSourceFile modified by alias, destination.
+// see formatColumnSelect
+func getWorkflowTemplateSourceFileColumns(aliasAndDestination ...string) []string {
+	columns := []string{
+		"id",
+		"source_id",
+		"path",
+		"content_sha256",
+		"commit_sha",
+		"workflow_template_uid",
+		"created_at",
+		"modified_at",
+	}
+	return sql.FormatColumnSelect(columns, aliasAndDestination...)
+}
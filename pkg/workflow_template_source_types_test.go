@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// gitRepositoryForTest is a local bare repository with a clone to commit to
+type gitRepositoryForTest struct {
+	t      *testing.T
+	dir    string
+	bare   string
+	clone  string
+	branch string
+}
+
+// newGitRepositoryForTest creates a bare repository in a temporary directory, or skips the test without git
+func newGitRepositoryForTest(t *testing.T) *gitRepositoryForTest {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "workflow-template-source-test-")
+	assert.Nil(t, err)
+
+	repository := &gitRepositoryForTest{
+		t:      t,
+		dir:    dir,
+		bare:   filepath.Join(dir, "templates.git"),
+		clone:  filepath.Join(dir, "clone"),
+		branch: "main",
+	}
+	repository.git(dir, "init", "--quiet", "--bare", repository.bare)
+	repository.git(dir, "init", "--quiet", repository.clone)
+
+	return repository
+}
+
+// git runs git in dir, failing the test if it fails
+func (r *gitRepositoryForTest) git(dir string, args ...string) string {
+	args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@onepanel.io", "-c", "commit.gpgsign=false"}, args...)
+	output, err := runGit(context.Background(), dir, args...)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+
+	return output
+}
+
+// commit writes the files, commits and pushes them, and returns the commit SHA
+func (r *gitRepositoryForTest) commit(files map[string]string) string {
+	for name, content := range files {
+		filePath := filepath.Join(r.clone, filepath.FromSlash(name))
+		assert.Nil(r.t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.Nil(r.t, ioutil.WriteFile(filePath, []byte(content), 0644))
+	}
+
+	r.git(r.clone, "add", "--all")
+	r.git(r.clone, "commit", "--quiet", "--message", "Update templates")
+	r.git(r.clone, "push", "--quiet", r.bare, "HEAD:refs/heads/"+r.branch)
+
+	return r.git(r.clone, "rev-parse", "HEAD")
+}
+
+// TestGitWorkflowTemplateSourceFiles makes sure the yaml files under the path of the branch are returned with the commit SHA
+func TestGitWorkflowTemplateSourceFiles(t *testing.T) {
+	repository := newGitRepositoryForTest(t)
+	defer os.RemoveAll(repository.dir)
+
+	firstSHA := repository.commit(map[string]string{
+		"README.md":                   "# Templates",
+		"pipelines/train.yaml":        "entrypoint: main\n",
+		"pipelines/nested/export.yml": "entrypoint: export\n",
+		"other/ignored.yaml":          "entrypoint: other\n",
+	})
+
+	ctx := context.Background()
+	sha, files, err := gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "pipelines")
+	assert.Nil(t, err)
+	assert.Equal(t, firstSHA, sha)
+	assert.Equal(t, map[string]string{
+		"pipelines/train.yaml":        "entrypoint: main\n",
+		"pipelines/nested/export.yml": "entrypoint: export\n",
+	}, files)
+
+	secondSHA := repository.commit(map[string]string{
+		"pipelines/train.yaml": "entrypoint: train\n",
+	})
+	assert.NotEqual(t, firstSHA, secondSHA)
+
+	sha, files, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "pipelines/train.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, secondSHA, sha)
+	assert.Equal(t, map[string]string{"pipelines/train.yaml": "entrypoint: train\n"}, files)
+
+	sha, files, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "")
+	assert.Nil(t, err)
+	assert.Len(t, files, 3)
+
+	_, _, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "missing")
+	assert.EqualError(t, err, "path 'missing' not found in branch 'main'")
+
+	_, _, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, "release", "pipelines")
+	assert.NotNil(t, err)
+}
+
+// TestWorkflowTemplateSource_Validate makes sure the repository, branch, path and interval are checked
+func TestWorkflowTemplateSource_Validate(t *testing.T) {
+	source := &WorkflowTemplateSource{
+		Name:       "pipelines",
+		Repository: "https://github.com/onepanelio/templates.git",
+		Branch:     "master",
+		Path:       "/workflows/./training/",
+	}
+	assert.Nil(t, source.validate())
+	assert.Equal(t, "workflows/training", source.Path)
+	assert.Equal(t, int64(300), source.SyncInterval)
+
+	valid := func(modify func(source *WorkflowTemplateSource)) error {
+		source := &WorkflowTemplateSource{
+			Name:       "pipelines",
+			Repository: "git@github.com:onepanelio/templates.git",
+			Branch:     "release/v0.17",
+		}
+		modify(source)
+		return source.validate()
+	}
+
+	assert.Nil(t, valid(func(source *WorkflowTemplateSource) {}))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Name = "" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "/var/lib/templates" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "file:///etc" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "--upload-pack=touch /tmp/x" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "http://169.254.169.254/templates.git" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "https://localhost:8443/templates.git" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "ssh://[::1]/templates.git" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Repository = "git@10.0.0.5:onepanelio/templates.git" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Branch = "--help" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.Path = "../secrets" }))
+	assert.NotNil(t, valid(func(source *WorkflowTemplateSource) { source.SyncInterval = 10 }))
+}
+
+// TestWorkflowTemplateSourceHost makes sure the host is found in URLs and scp-like addresses
+func TestWorkflowTemplateSourceHost(t *testing.T) {
+	assert.Equal(t, "github.com", workflowTemplateSourceHost("https://github.com/onepanelio/templates.git"))
+	assert.Equal(t, "10.0.0.5", workflowTemplateSourceHost("git://10.0.0.5:9418/templates.git"))
+	assert.Equal(t, "::1", workflowTemplateSourceHost("ssh://git@[::1]:22/templates.git"))
+	assert.Equal(t, "github.com", workflowTemplateSourceHost("git@github.com:onepanelio/templates.git"))
+	assert.Equal(t, "", workflowTemplateSourceHost("/var/lib/templates.git"))
+}
+
+// TestWorkflowTemplateSourceName makes sure workflow templates are named after their file
+func TestWorkflowTemplateSourceName(t *testing.T) {
+	name, err := workflowTemplateSourceName("pipelines/pytorch-training.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "pytorch-training", name)
+
+	_, err = workflowTemplateSourceName("pipelines/a-very-long-name-for-a-training-pipeline.yaml")
+	assert.NotNil(t, err)
+
+	assert.True(t, isWorkflowTemplateSourceFile("train.YML"))
+	assert.False(t, isWorkflowTemplateSourceFile("README.md"))
+}
+
+// TestGitWorkflowTemplateSourceFiles_Symlinks makes sure symbolic links of the repository can't be used to read files outside of it
+func TestGitWorkflowTemplateSourceFiles_Symlinks(t *testing.T) {
+	repository := newGitRepositoryForTest(t)
+	defer os.RemoveAll(repository.dir)
+
+	outside := filepath.Join(repository.dir, "outside")
+	assert.Nil(t, os.MkdirAll(outside, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(outside, "secret.yaml"), []byte("secret: true\n"), 0644))
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(repository.clone, "pipelines"), 0755))
+	assert.Nil(t, os.Symlink(outside, filepath.Join(repository.clone, "linked")))
+	assert.Nil(t, os.Symlink(filepath.Join(outside, "secret.yaml"), filepath.Join(repository.clone, "pipelines", "secret.yaml")))
+	assert.Nil(t, os.Symlink("pipelines", filepath.Join(repository.clone, "alias")))
+	repository.commit(map[string]string{
+		"pipelines/train.yaml": "entrypoint: main\n",
+	})
+
+	ctx := context.Background()
+	_, _, err := gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "linked")
+	assert.EqualError(t, err, "path 'linked' leads outside of the repository")
+
+	_, _, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "linked/secret.yaml")
+	assert.EqualError(t, err, "path 'linked/secret.yaml' leads outside of the repository")
+
+	_, files, err := gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "pipelines")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"pipelines/train.yaml": "entrypoint: main\n"}, files)
+
+	_, files, err = gitWorkflowTemplateSourceFiles(ctx, repository.bare, repository.branch, "alias")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"pipelines/train.yaml": "entrypoint: main\n"}, files)
+}
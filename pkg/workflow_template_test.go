@@ -3,9 +3,15 @@ package v1
 import (
 	"database/sql"
 	"fmt"
+	argoFake "github.com/argoproj/argo/pkg/client/clientset/versioned/fake"
 	"github.com/onepanelio/core/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	k8stesting "k8s.io/client-go/testing"
 	"testing"
 )
 
@@ -343,3 +349,95 @@ func TestClient_GetWorkflowTemplate(t *testing.T) {
 	testClientGetWorkflowTemplateSuccess(t)
 	testClientGetWorkflowTemplateNotFound(t)
 }
+
+// generateNameTestClient returns a default test client whose argo objects get a name from their generate name,
+// as the argo api would do, so more than one cron workflow can be created
+func generateNameTestClient() *Client {
+	argoFakeClient := argoFake.NewSimpleClientset()
+	argoFakeClient.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		object, err := meta.Accessor(action.(k8stesting.CreateAction).GetObject())
+		if err != nil {
+			return false, nil, err
+		}
+		if object.GetName() == "" && object.GetGenerateName() != "" {
+			object.SetName(object.GetGenerateName() + rand.String(5))
+		}
+
+		return false, nil, nil
+	})
+
+	c := DefaultTestClient()
+	c.argoprojV1alpha1 = argoFakeClient.ArgoprojV1alpha1()
+
+	return c
+}
+
+// TestClient_SetLatestWorkflowTemplateVersion makes sure the cron workflows of every other version are updated,
+// and that setting the same version again updates the ones that failed before
+func TestClient_SetLatestWorkflowTemplateVersion(t *testing.T) {
+	c := generateNameTestClient()
+	clearDatabase(t)
+
+	namespace := "onepanel"
+	original, err := c.CreateWorkflowTemplate(namespace, &WorkflowTemplate{
+		Name:     "test",
+		Manifest: defaultWorkflowTemplate,
+	})
+	assert.Nil(t, err)
+
+	newCronWorkflow := func() *CronWorkflow {
+		cronWorkflow, err := c.CreateCronWorkflow(namespace, &CronWorkflow{
+			Manifest: `{"schedule": "0 * * * *"}`,
+			WorkflowExecution: &WorkflowExecution{
+				WorkflowTemplate: &WorkflowTemplate{
+					UID:     original.UID,
+					Version: original.Version,
+				},
+			},
+		})
+		assert.Nil(t, err)
+
+		return cronWorkflow
+	}
+	first := newCronWorkflow()
+	second := newCronWorkflow()
+
+	latest, err := c.CreateWorkflowTemplateVersion(namespace, &WorkflowTemplate{
+		UID:      original.UID,
+		Name:     original.Name,
+		Manifest: defaultWorkflowTemplate,
+	})
+	assert.Nil(t, err)
+
+	// The second cron workflow can't be updated while it is missing from argo
+	cronWorkflows := c.ArgoprojV1alpha1().CronWorkflows(namespace)
+	argoSecond, err := cronWorkflows.Get(second.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Nil(t, cronWorkflows.Delete(second.Name, &metav1.DeleteOptions{}))
+
+	_, updated, err := c.SetLatestWorkflowTemplateVersion(namespace, original.UID, latest.Version, true)
+	assert.NotNil(t, err)
+	if assert.Len(t, updated, 1) {
+		assert.Equal(t, first.Name, updated[0].Name)
+	}
+
+	argoSecond.ResourceVersion = ""
+	_, err = cronWorkflows.Create(argoSecond)
+	assert.Nil(t, err)
+
+	workflowTemplate, updated, err := c.SetLatestWorkflowTemplateVersion(namespace, original.UID, latest.Version, true)
+	assert.Nil(t, err)
+	assert.True(t, workflowTemplate.IsLatest)
+	if assert.Len(t, updated, 1) {
+		assert.Equal(t, second.Name, updated[0].Name)
+	}
+
+	versions := make([]int64, 0)
+	err = database.Select(&versions, `
+		SELECT wtv.version
+		FROM cron_workflows cw
+		JOIN workflow_template_versions wtv ON wtv.id = cw.workflow_template_version_id
+		ORDER BY cw.id`)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{latest.Version, latest.Version}, versions)
+}
@@ -519,6 +519,14 @@ func (c *Client) CreateWorkspace(namespace string, workspace *Workspace) (*Works
 	}
 	workspace.WorkspaceTemplate = workspaceTemplate
 
+	declaredParameters, err := ParseParametersFromManifest([]byte(workspaceTemplate.Manifest))
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, err.Error())
+	}
+	if err := ValidateParameterValues(declaredParameters, workspace.Parameters); err != nil {
+		return nil, err
+	}
+
 	workspace, err = c.createWorkspace(namespace, parameters, workspace)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	api "github.com/onepanelio/core/api/gen"
+	v1 "github.com/onepanelio/core/pkg"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// artifactDownloadPath matches /apis/v1beta1/{namespace}/workflow_executions/{uid}/download/{key}
+	artifactDownloadPath = regexp.MustCompile(`^/apis/v1beta1/([^/]+)/workflow_executions/([^/]+)/download/(.+)$`)
+	// artifactUploadPath matches /apis/v1beta1/{namespace}/artifacts/upload
+	artifactUploadPath = regexp.MustCompile(`^/apis/v1beta1/([^/]+)/artifacts/upload$`)
+)
+
+// parseRange parses a single byte range from a Range header into an offset and length, as used by DownloadArtifact.
+// ok is false if there is no range or it is not supported, in which case the whole artifact should be served.
+func parseRange(header string) (offset, length int64, ok bool) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return 0, 0, false
+	}
+
+	spec := strings.TrimSpace(header[len(prefix):])
+	// Multiple ranges are not supported
+	if strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	start, end := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if start == "" {
+		// bytes=-n is the last n bytes
+		suffix, err := strconv.ParseInt(end, 10, 64)
+		if err != nil || suffix <= 0 {
+			return 0, 0, false
+		}
+		return -suffix, 0, true
+	}
+
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+	if end == "" {
+		return offset, 0, true
+	}
+
+	last, err := strconv.ParseInt(end, 10, 64)
+	if err != nil || last < offset {
+		return 0, 0, false
+	}
+
+	return offset, last - offset + 1, true
+}
+
+// outgoingContext returns a context for calls to the RPC server with the credentials of the http request
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		md.Set("authorization", authorization)
+	}
+	if cookie := r.Header.Get("Cookie"); cookie != "" {
+		md.Set("grpcgateway-cookie", cookie)
+	}
+
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// writeArtifactError writes the status of a grpc error as an http error
+func writeArtifactError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+}
+
+// serveArtifact streams the artifact from DownloadArtifact into the http response.
+func serveArtifact(client api.WorkflowServiceClient, w http.ResponseWriter, r *http.Request, namespace, uid, key string) {
+	ctx, cancel := context.WithCancel(outgoingContext(r))
+	defer cancel()
+
+	offset, length, hasRange := parseRange(r.Header.Get("Range"))
+	stream, err := client.DownloadArtifact(ctx, &api.DownloadArtifactRequest{
+		Namespace: namespace,
+		Uid:       uid,
+		Key:       key,
+		Offset:    offset,
+		Length:    length,
+	})
+	if err != nil {
+		writeArtifactError(w, err)
+		return
+	}
+
+	// The first chunk has the information about the artifact, errors also show up here.
+	chunk, err := stream.Recv()
+	if err != nil {
+		st, _ := status.FromError(err)
+		if hasRange && st.Code() == codes.OutOfRange {
+			w.Header().Set("Content-Range", "bytes */*")
+			http.Error(w, st.Message(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		writeArtifactError(w, err)
+		return
+	}
+
+	contentType := chunk.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": v1.FilePathToName(key)}))
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Content-Length", strconv.FormatInt(chunk.Length, 10))
+
+	statusCode := http.StatusOK
+	if hasRange && chunk.Size > 0 {
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", chunk.Offset, chunk.Offset+chunk.Length-1, chunk.Size))
+		statusCode = http.StatusPartialContent
+	}
+	w.WriteHeader(statusCode)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for {
+		if _, err := w.Write(chunk.Data); err != nil {
+			// The client went away, there is nobody to report the error to.
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Namespace": namespace,
+				"UID":       uid,
+				"Key":       key,
+				"Error":     err.Error(),
+			}).Error("Artifact download stopped.")
+			return
+		}
+	}
+}
+
+// ArtifactDownloadHandler serves artifacts at /apis/v1beta1/{namespace}/workflow_executions/{uid}/download/{key}
+// as plain http responses, with support for Range requests. The artifacts are streamed from DownloadArtifact,
+// which checks the authorization of the request. All other requests are passed to next.
+func ArtifactDownloadHandler(client api.WorkflowServiceClient, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := artifactDownloadPath.FindStringSubmatch(r.URL.Path)
+		if matches == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		serveArtifact(client, w, r, matches[1], matches[2], matches[3])
+	})
+}
+
+// uploadArtifact streams the file of a multipart form into UploadArtifact.
+// The form may have "path" and "size" fields, which have to come before the "file" field.
+// The size of the file is the "size" field, or the Content-Length of the file part. Uploads of an unknown
+// size are buffered in parts by the artifact store, so clients should send it when they can.
+func uploadArtifact(client api.WorkflowServiceClient, w http.ResponseWriter, r *http.Request, namespace string) {
+	multipartReader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart form.", http.StatusBadRequest)
+		return
+	}
+
+	path := ""
+	size := int64(-1)
+	for {
+		part, err := multipartReader.NextPart()
+		if err == io.EOF {
+			http.Error(w, "Missing file.", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid multipart form.", http.StatusBadRequest)
+			return
+		}
+
+		switch part.FormName() {
+		case "path":
+			value, err := ioutil.ReadAll(io.LimitReader(part, 4096))
+			if err != nil {
+				http.Error(w, "Invalid path.", http.StatusBadRequest)
+				return
+			}
+			path = string(value)
+		case "size":
+			value, err := ioutil.ReadAll(io.LimitReader(part, 32))
+			if err != nil {
+				http.Error(w, "Invalid size.", http.StatusBadRequest)
+				return
+			}
+			size, err = strconv.ParseInt(strings.TrimSpace(string(value)), 10, 64)
+			if err != nil || size < 0 {
+				http.Error(w, "Invalid size.", http.StatusBadRequest)
+				return
+			}
+		case "file":
+			if size < 0 {
+				if contentLength, err := strconv.ParseInt(part.Header.Get("Content-Length"), 10, 64); err == nil && contentLength >= 0 {
+					size = contentLength
+				}
+			}
+
+			resp, err := streamUpload(client, r, namespace, path, size, part)
+			if err != nil {
+				writeArtifactError(w, err)
+				return
+			}
+
+			body, err := protojson.Marshal(resp)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+			return
+		}
+	}
+}
+
+// streamUpload sends the content of the part to UploadArtifact in chunks. size is -1 if it is not known.
+func streamUpload(client api.WorkflowServiceClient, r *http.Request, namespace, path string, size int64, part *multipart.Part) (*api.UploadArtifactResponse, error) {
+	stream, err := client.UploadArtifact(outgoingContext(r))
+	if err != nil {
+		return nil, err
+	}
+
+	req := &api.UploadArtifactRequest{
+		Namespace:   namespace,
+		Path:        path,
+		Name:        part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Size:        size,
+	}
+
+	buffer := make([]byte, artifactChunkSize)
+	for {
+		n, readErr := io.ReadFull(part, buffer)
+		if n > 0 || req.Namespace != "" {
+			req.Data = buffer[:n]
+			if err := stream.Send(req); err != nil {
+				// The actual error is returned by CloseAndRecv
+				break
+			}
+			req = &api.UploadArtifactRequest{}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// ArtifactUploadHandler accepts multipart form uploads at /apis/v1beta1/{namespace}/artifacts/upload
+// and streams them into the artifact repository with UploadArtifact, which checks the authorization of the request.
+// All other requests are passed to next.
+func ArtifactUploadHandler(client api.WorkflowServiceClient, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := artifactUploadPath.FindStringSubmatch(r.URL.Path)
+		if matches == nil || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		uploadArtifact(client, w, r, matches[1])
+	})
+}
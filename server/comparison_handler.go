@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/csv"
+	api "github.com/onepanelio/core/api/gen"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// comparisonDownloadPath matches /apis/v1beta1/{namespace}/workflow_executions/compare/download
+var comparisonDownloadPath = regexp.MustCompile(`^/apis/v1beta1/([^/]+)/workflow_executions/compare/download$`)
+
+// comparisonCSVRecords returns the comparison as CSV records, with a header and a record for each workflow execution.
+// Parameters, metrics and labels are columns prefixed with "parameter:", "metric:" and "label:". Missing values are empty.
+func comparisonCSVRecords(comparison *api.CompareWorkflowExecutionsResponse) [][]string {
+	header := []string{"uid", "name", "phase", "workflowTemplateUid", "workflowTemplateVersion", "createdAt", "startedAt", "finishedAt", "durationSeconds"}
+	for _, parameter := range comparison.Parameters {
+		header = append(header, "parameter:"+parameter.Name)
+	}
+	for _, metric := range comparison.Metrics {
+		header = append(header, "metric:"+metric.Name)
+	}
+	for _, label := range comparison.Labels {
+		header = append(header, "label:"+label.Name)
+	}
+
+	records := [][]string{header}
+	for i, we := range comparison.WorkflowExecutions {
+		record := []string{
+			we.Uid,
+			we.Name,
+			we.Phase,
+			we.WorkflowTemplateUid,
+			strconv.FormatInt(we.WorkflowTemplateVersion, 10),
+			we.CreatedAt,
+			we.StartedAt,
+			we.FinishedAt,
+			strconv.FormatFloat(we.DurationSeconds, 'f', -1, 64),
+		}
+		for _, parameter := range comparison.Parameters {
+			record = append(record, parameter.Values[i].Value)
+		}
+		for _, metric := range comparison.Metrics {
+			value := ""
+			if metric.Values[i].Present {
+				value = strconv.FormatFloat(metric.Values[i].Value, 'g', -1, 64)
+			}
+			record = append(record, value)
+		}
+		for _, label := range comparison.Labels {
+			record = append(record, label.Values[i].Value)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// downloadComparison writes the comparison from CompareWorkflowExecutions into the http response as a CSV file.
+// The workflow executions are given by the uids query parameter, which can be repeated.
+func downloadComparison(client api.WorkflowServiceClient, w http.ResponseWriter, r *http.Request, namespace string) {
+	comparison, err := client.CompareWorkflowExecutions(outgoingContext(r), &api.CompareWorkflowExecutionsRequest{
+		Namespace: namespace,
+		Uids:      r.URL.Query()["uids"],
+	})
+	if err != nil {
+		writeArtifactError(w, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/csv; charset=utf-8")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "comparison.csv"}))
+	w.WriteHeader(http.StatusOK)
+
+	// The client went away if writing fails, there is nobody to report the error to.
+	csv.NewWriter(w).WriteAll(comparisonCSVRecords(comparison))
+}
+
+// ComparisonDownloadHandler serves the comparison of workflow executions as a CSV file
+// at /apis/v1beta1/{namespace}/workflow_executions/compare/download. The comparison is from CompareWorkflowExecutions,
+// which checks the authorization of the request. All other requests are passed to next.
+func ComparisonDownloadHandler(client api.WorkflowServiceClient, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := comparisonDownloadPath.FindStringSubmatch(r.URL.Path)
+		if matches == nil || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		downloadComparison(client, w, r, matches[1])
+	})
+}
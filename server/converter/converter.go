@@ -3,6 +3,7 @@ package converter
 import (
 	api "github.com/onepanelio/core/api/gen"
 	v1 "github.com/onepanelio/core/pkg"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"sort"
 	"time"
 )
@@ -73,6 +74,57 @@ func APIMetricsToCore(metrics []*api.Metric) v1.Metrics {
 	return result
 }
 
+// MetricSeriesToAPI converts []*v1.MetricSeries to the API version
+func MetricSeriesToAPI(series []*v1.MetricSeries) []*api.MetricSeries {
+	result := make([]*api.MetricSeries, 0)
+
+	for _, item := range series {
+		points := make([]*api.MetricPoint, 0)
+		for _, point := range item.Points {
+			points = append(points, &api.MetricPoint{
+				Step:      point.Step,
+				Value:     point.Value,
+				Min:       point.Min,
+				Max:       point.Max,
+				Timestamp: point.Timestamp.Format(time.RFC3339Nano),
+			})
+		}
+
+		result = append(result, &api.MetricSeries{
+			Name:   item.Name,
+			Points: points,
+		})
+	}
+
+	return result
+}
+
+// APIMetricPointsToCore converts []*api.MetricPoint to []*v1.MetricPoint.
+// Timestamps are RFC3339, empty timestamps are left as the zero time.
+func APIMetricPointsToCore(points []*api.MetricPoint) ([]*v1.MetricPoint, error) {
+	result := make([]*v1.MetricPoint, 0)
+
+	for _, point := range points {
+		item := &v1.MetricPoint{
+			Name:  point.Name,
+			Step:  point.Step,
+			Value: point.Value,
+		}
+
+		if point.Timestamp != "" {
+			timestamp, err := time.Parse(time.RFC3339Nano, point.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+			item.Timestamp = timestamp
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
 // LabelsToKeyValues converts []*v1.Label to []*api.Label
 func LabelsToKeyValues(labels []*v1.Label) []*api.KeyValue {
 	keyValues := make([]*api.KeyValue, 0)
@@ -158,6 +210,18 @@ func ParameterToAPI(param v1.Parameter) *api.Parameter {
 	if param.Options != nil {
 		apiParam.Options = ParameterOptionsToAPI(param.Options)
 	}
+	if param.Min != nil {
+		apiParam.Min = wrapperspb.Double(*param.Min)
+	}
+	if param.Max != nil {
+		apiParam.Max = wrapperspb.Double(*param.Max)
+	}
+	if param.Step != nil {
+		apiParam.Step = wrapperspb.Double(*param.Step)
+	}
+	if param.Pattern != nil {
+		apiParam.Pattern = *param.Pattern
+	}
 
 	return apiParam
 }
@@ -193,6 +257,18 @@ func APIParameterToInternal(param *api.Parameter) *v1.Parameter {
 	if param.Options != nil {
 		result.Options = APIParameterOptionsToInternal(param.Options)
 	}
+	if param.Min != nil {
+		result.Min = &param.Min.Value
+	}
+	if param.Max != nil {
+		result.Max = &param.Max.Value
+	}
+	if param.Step != nil {
+		result.Step = &param.Step.Value
+	}
+	if param.Pattern != "" {
+		result.Pattern = &param.Pattern
+	}
 
 	return result
 }
@@ -219,6 +295,7 @@ func WorkflowExecutionStatisticsReportToAPI(report *v1.WorkflowExecutionStatisti
 		Completed:    report.Completed,
 		Failed:       report.Failed,
 		Terminated:   report.Terminated,
+		Queued:       report.Queued,
 		LastExecuted: TimestampToAPIString(report.LastExecuted),
 	}
 
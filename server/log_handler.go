@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	api "github.com/onepanelio/core/api/gen"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// logDownloadPath matches /apis/v1beta1/{namespace}/workflow_executions/{uid}/logs/download
+var logDownloadPath = regexp.MustCompile(`^/apis/v1beta1/([^/]+)/workflow_executions/([^/]+)/logs/download$`)
+
+// formatLogEntry formats an entry as a line of a downloaded log file: "{timestamp} [{pod}/{container}] {content}"
+func formatLogEntry(entry *api.LogEntry) string {
+	line := "[" + entry.PodName + "/" + entry.ContainerName + "] " + entry.Content + "\n"
+	if entry.Timestamp != "" {
+		line = entry.Timestamp + " " + line
+	}
+
+	return line
+}
+
+// downloadLogs writes the merged logs from GetMergedWorkflowExecutionLogs into the http response as a text file.
+// The query parameters are the options of GetMergedWorkflowExecutionLogsRequest.
+func downloadLogs(client api.WorkflowServiceClient, w http.ResponseWriter, r *http.Request, namespace, uid string) {
+	ctx, cancel := context.WithCancel(outgoingContext(r))
+	defer cancel()
+
+	query := r.URL.Query()
+	req := &api.GetMergedWorkflowExecutionLogsRequest{
+		Namespace: namespace,
+		Uid:       uid,
+		Filter:    query.Get("filter"),
+		Since:     query.Get("since"),
+		Until:     query.Get("until"),
+	}
+	if regex := query.Get("regex"); regex != "" {
+		value, err := strconv.ParseBool(regex)
+		if err != nil {
+			http.Error(w, "Invalid regex.", http.StatusBadRequest)
+			return
+		}
+		req.Regex = value
+	}
+	if tail := query.Get("tail"); tail != "" {
+		value, err := strconv.ParseInt(tail, 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid tail.", http.StatusBadRequest)
+			return
+		}
+		req.Tail = int32(value)
+	}
+
+	stream, err := client.GetMergedWorkflowExecutionLogs(ctx, req)
+	if err != nil {
+		writeArtifactError(w, err)
+		return
+	}
+
+	// Errors, like an invalid filter, are returned with the first response
+	resp, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		writeArtifactError(w, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": uid + ".log"}))
+	w.WriteHeader(http.StatusOK)
+
+	writer := bufio.NewWriter(w)
+	defer writer.Flush()
+	for err == nil {
+		for _, entry := range resp.LogEntries {
+			if _, err := writer.WriteString(formatLogEntry(entry)); err != nil {
+				// The client went away, there is nobody to report the error to.
+				return
+			}
+		}
+
+		resp, err = stream.Recv()
+	}
+
+	if err != io.EOF {
+		log.WithFields(log.Fields{
+			"Namespace": namespace,
+			"UID":       uid,
+			"Error":     err.Error(),
+		}).Error("Log download stopped.")
+	}
+}
+
+// LogDownloadHandler serves the merged logs of all the pods and containers of a workflow execution as a text file
+// at /apis/v1beta1/{namespace}/workflow_executions/{uid}/logs/download. The logs are streamed from GetMergedWorkflowExecutionLogs,
+// which checks the authorization of the request. All other requests are passed to next.
+func LogDownloadHandler(client api.WorkflowServiceClient, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := logDownloadPath.FindStringSubmatch(r.URL.Path)
+		if matches == nil || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		downloadLogs(client, w, r, matches[1], matches[2])
+	})
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	wfv1 "github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
+	"github.com/golang/protobuf/ptypes/empty"
+	api "github.com/onepanelio/core/api/gen"
+	v1 "github.com/onepanelio/core/pkg"
+	"github.com/onepanelio/core/pkg/util/request/pagination"
+	"github.com/onepanelio/core/server/auth"
+	"github.com/onepanelio/core/server/converter"
+)
+
+// NotificationServer is an implementation of the grpc NotificationServer
+type NotificationServer struct {
+	api.UnimplementedNotificationServiceServer
+}
+
+// NewNotificationServer creates a new NotificationServer
+func NewNotificationServer() *NotificationServer {
+	return &NotificationServer{}
+}
+
+func apiNotificationSubscription(subscription *v1.NotificationSubscription) *api.NotificationSubscription {
+	filter := &api.NotificationFilter{
+		WorkflowTemplateUid: subscription.Filter.WorkflowTemplateUID,
+		Labels:              converter.MappingToKeyValue(subscription.Filter.Labels),
+	}
+	for _, phase := range subscription.Filter.Phases {
+		filter.Phases = append(filter.Phases, string(phase))
+	}
+
+	return &api.NotificationSubscription{
+		Uid:       subscription.UID,
+		Name:      subscription.Name,
+		Url:       subscription.URL,
+		Filter:    filter,
+		CreatedAt: converter.TimestampToAPIString(&subscription.CreatedAt),
+	}
+}
+
+func apiNotificationDelivery(delivery *v1.NotificationDelivery) *api.NotificationDelivery {
+	result := &api.NotificationDelivery{
+		EventId:              delivery.EventID,
+		EventType:            string(delivery.EventType),
+		WorkflowExecutionUid: delivery.WorkflowExecutionUID,
+		Url:                  delivery.URL,
+		Payload:              string(delivery.Payload),
+		Status:               string(delivery.Status),
+		Attempts:             int32(delivery.Attempts),
+		Error:                delivery.Error,
+		NextAttemptAt:        converter.TimestampToAPIString(delivery.NextAttemptAt),
+		DeliveredAt:          converter.TimestampToAPIString(delivery.DeliveredAt),
+		CreatedAt:            converter.TimestampToAPIString(&delivery.CreatedAt),
+	}
+	if delivery.ResponseCode != nil {
+		result.ResponseCode = int32(*delivery.ResponseCode)
+	}
+
+	return result
+}
+
+func (s *NotificationServer) CreateNotificationSubscription(ctx context.Context, req *api.CreateNotificationSubscriptionRequest) (*api.NotificationSubscription, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	subscription := &v1.NotificationSubscription{}
+	if req.Subscription != nil {
+		subscription.Name = req.Subscription.Name
+		subscription.URL = req.Subscription.Url
+		if req.Subscription.Filter != nil {
+			subscription.Filter.WorkflowTemplateUID = req.Subscription.Filter.WorkflowTemplateUid
+			subscription.Filter.Labels = converter.APIKeyValueToLabel(req.Subscription.Filter.Labels)
+			for _, phase := range req.Subscription.Filter.Phases {
+				subscription.Filter.Phases = append(subscription.Filter.Phases, wfv1.NodePhase(phase))
+			}
+		}
+	}
+
+	subscription, err = client.CreateNotificationSubscription(req.Namespace, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiNotificationSubscription(subscription), nil
+}
+
+func (s *NotificationServer) GetNotificationSubscription(ctx context.Context, req *api.GetNotificationSubscriptionRequest) (*api.NotificationSubscription, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	subscription, err := client.GetNotificationSubscription(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiNotificationSubscription(subscription), nil
+}
+
+func (s *NotificationServer) ListNotificationSubscriptions(ctx context.Context, req *api.ListNotificationSubscriptionsRequest) (*api.ListNotificationSubscriptionsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	subscriptions, err := client.ListNotificationSubscriptions(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	apiSubscriptions := make([]*api.NotificationSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		apiSubscriptions = append(apiSubscriptions, apiNotificationSubscription(subscription))
+	}
+
+	return &api.ListNotificationSubscriptionsResponse{
+		Count:         int32(len(apiSubscriptions)),
+		Subscriptions: apiSubscriptions,
+	}, nil
+}
+
+func (s *NotificationServer) DeleteNotificationSubscription(ctx context.Context, req *api.DeleteNotificationSubscriptionRequest) (*empty.Empty, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "delete", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	if err := client.DeleteNotificationSubscription(req.Namespace, req.Uid); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *NotificationServer) ListNotificationDeliveries(ctx context.Context, req *api.ListNotificationDeliveriesRequest) (*api.ListNotificationDeliveriesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	paginator := pagination.NewRequest(req.Page, req.PageSize)
+	deliveries, err := client.ListNotificationDeliveries(req.Namespace, req.Uid, &paginator)
+	if err != nil {
+		return nil, err
+	}
+
+	apiDeliveries := make([]*api.NotificationDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		apiDeliveries = append(apiDeliveries, apiNotificationDelivery(delivery))
+	}
+
+	count, err := client.CountNotificationDeliveries(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ListNotificationDeliveriesResponse{
+		Count:      int32(len(apiDeliveries)),
+		Deliveries: apiDeliveries,
+		Page:       int32(paginator.Page),
+		Pages:      paginator.CalculatePages(count),
+		TotalCount: int32(count),
+	}, nil
+}
+
+func (s *NotificationServer) SendTestNotification(ctx context.Context, req *api.SendTestNotificationRequest) (*api.NotificationDelivery, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	delivery, err := client.SendTestNotification(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiNotificationDelivery(delivery), nil
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	api "github.com/onepanelio/core/api/gen"
+	v1 "github.com/onepanelio/core/pkg"
+	"github.com/onepanelio/core/pkg/util/ptr"
+	"github.com/onepanelio/core/pkg/util/request/pagination"
+	"github.com/onepanelio/core/server/auth"
+	"github.com/onepanelio/core/server/converter"
+	"time"
+)
+
+// SweepServer is an implementation of the grpc SweepServer
+type SweepServer struct {
+	api.UnimplementedSweepServiceServer
+}
+
+// NewSweepServer creates a new SweepServer
+func NewSweepServer() *SweepServer {
+	return &SweepServer{}
+}
+
+func apiSweepRun(run *v1.SweepRun) *api.SweepRun {
+	if run == nil {
+		return nil
+	}
+
+	result := &api.SweepRun{
+		Trial:      int32(run.Trial),
+		Rung:       int32(run.Rung),
+		Phase:      string(run.Phase),
+		Parameters: converter.ParametersToAPI(run.Parameters),
+	}
+
+	if run.ObjectiveValue != nil {
+		result.HasObjectiveValue = true
+		result.ObjectiveValue = *run.ObjectiveValue
+	}
+
+	if run.WorkflowExecution != nil {
+		result.WorkflowExecutionUid = run.WorkflowExecution.UID
+		result.WorkflowExecutionPhase = string(run.WorkflowExecution.Phase)
+		result.Metrics = converter.MetricsToAPI(run.WorkflowExecution.Metrics)
+		result.StartedAt = converter.TimestampToAPIString(run.WorkflowExecution.StartedAt)
+		result.FinishedAt = converter.TimestampToAPIString(run.WorkflowExecution.FinishedAt)
+	}
+
+	return result
+}
+
+func apiSweep(sweep *v1.Sweep) *api.Sweep {
+	result := &api.Sweep{
+		Uid:             sweep.UID,
+		Name:            sweep.Name,
+		Strategy:        string(sweep.Strategy),
+		ObjectiveMetric: sweep.ObjectiveMetric,
+		ObjectiveGoal:   string(sweep.ObjectiveGoal),
+		MaxConcurrency:  int32(sweep.MaxConcurrency),
+		Phase:           string(sweep.Phase),
+		Message:         sweep.Message,
+		Labels:          converter.MappingToKeyValue(sweep.Labels),
+		CreatedAt:       sweep.CreatedAt.UTC().Format(time.RFC3339),
+		StartedAt:       converter.TimestampToAPIString(sweep.StartedAt),
+		FinishedAt:      converter.TimestampToAPIString(sweep.FinishedAt),
+		Spec: &api.SweepSpec{
+			FixedParameters:   converter.ParametersToAPI(sweep.Spec.FixedParameters),
+			MaxRuns:           int32(sweep.Spec.MaxRuns),
+			Seed:              sweep.Spec.Seed,
+			ResourceParameter: sweep.Spec.ResourceParameter,
+			MinResource:       sweep.Spec.MinResource,
+			MaxResource:       sweep.Spec.MaxResource,
+			ReductionFactor:   int32(sweep.Spec.ReductionFactor),
+		},
+	}
+
+	for _, parameter := range sweep.Spec.Parameters {
+		apiParameter := &api.SweepParameter{
+			Name:    parameter.Name,
+			Values:  parameter.Values,
+			Scale:   parameter.Scale,
+			Integer: parameter.Integer,
+		}
+		if parameter.Min != nil {
+			apiParameter.Min = *parameter.Min
+		}
+		if parameter.Max != nil {
+			apiParameter.Max = *parameter.Max
+		}
+		if parameter.Step != nil {
+			apiParameter.Step = *parameter.Step
+		}
+		result.Spec.Parameters = append(result.Spec.Parameters, apiParameter)
+	}
+
+	if sweep.WorkflowTemplate != nil {
+		result.WorkflowTemplate = &api.WorkflowTemplate{
+			Uid:     sweep.WorkflowTemplate.UID,
+			Name:    sweep.WorkflowTemplate.Name,
+			Version: sweep.WorkflowTemplate.Version,
+		}
+	}
+
+	for _, run := range sweep.Runs {
+		result.Runs = append(result.Runs, apiSweepRun(run))
+	}
+	result.BestRun = apiSweepRun(sweep.BestRun())
+
+	return result
+}
+
+// apiSweepSpecToInternal converts the spec of a sweep. Ranges are used for parameters without values,
+// and a step of 0 means there is none.
+func apiSweepSpecToInternal(spec *api.SweepSpec) v1.SweepSpec {
+	if spec == nil {
+		return v1.SweepSpec{}
+	}
+
+	result := v1.SweepSpec{
+		MaxRuns:           int(spec.MaxRuns),
+		Seed:              spec.Seed,
+		ResourceParameter: spec.ResourceParameter,
+		MinResource:       spec.MinResource,
+		MaxResource:       spec.MaxResource,
+		ReductionFactor:   int(spec.ReductionFactor),
+	}
+
+	for _, parameter := range spec.FixedParameters {
+		result.FixedParameters = append(result.FixedParameters, v1.Parameter{
+			Name:  parameter.Name,
+			Value: ptr.String(parameter.Value),
+		})
+	}
+
+	for _, parameter := range spec.Parameters {
+		sweepParameter := &v1.SweepParameter{
+			Name:    parameter.Name,
+			Values:  parameter.Values,
+			Scale:   parameter.Scale,
+			Integer: parameter.Integer,
+		}
+		if len(parameter.Values) == 0 {
+			sweepParameter.Min = ptr.Float64(parameter.Min)
+			sweepParameter.Max = ptr.Float64(parameter.Max)
+		}
+		if parameter.Step != 0 {
+			sweepParameter.Step = ptr.Float64(parameter.Step)
+		}
+		result.Parameters = append(result.Parameters, sweepParameter)
+	}
+
+	return result
+}
+
+func (s *SweepServer) CreateSweep(ctx context.Context, req *api.CreateSweepRequest) (*api.Sweep, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	sweep := &v1.Sweep{
+		Name:             req.Sweep.Name,
+		Strategy:         v1.SweepStrategy(req.Sweep.Strategy),
+		Spec:             apiSweepSpecToInternal(req.Sweep.Spec),
+		ObjectiveMetric:  req.Sweep.ObjectiveMetric,
+		ObjectiveGoal:    v1.SweepObjectiveGoal(req.Sweep.ObjectiveGoal),
+		MaxConcurrency:   int(req.Sweep.MaxConcurrency),
+		Labels:           converter.APIKeyValueToLabel(req.Sweep.Labels),
+		WorkflowTemplate: &v1.WorkflowTemplate{},
+	}
+	if req.Sweep.WorkflowTemplate != nil {
+		sweep.WorkflowTemplate.UID = req.Sweep.WorkflowTemplate.Uid
+		sweep.WorkflowTemplate.Version = req.Sweep.WorkflowTemplate.Version
+	}
+
+	sweep, err = client.CreateSweep(req.Namespace, sweep)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiSweep(sweep), nil
+}
+
+func (s *SweepServer) GetSweep(ctx context.Context, req *api.GetSweepRequest) (*api.Sweep, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	sweep, err := client.GetSweep(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiSweep(sweep), nil
+}
+
+func (s *SweepServer) ListSweeps(ctx context.Context, req *api.ListSweepsRequest) (*api.ListSweepsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	paginator := pagination.NewRequest(req.Page, req.PageSize)
+	sweeps, err := client.ListSweeps(req.Namespace, &paginator)
+	if err != nil {
+		return nil, err
+	}
+
+	apiSweeps := make([]*api.Sweep, 0, len(sweeps))
+	for _, sweep := range sweeps {
+		apiSweeps = append(apiSweeps, apiSweep(sweep))
+	}
+
+	count, err := client.CountSweeps(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ListSweepsResponse{
+		Count:      int32(len(apiSweeps)),
+		Sweeps:     apiSweeps,
+		Page:       int32(paginator.Page),
+		Pages:      paginator.CalculatePages(count),
+		TotalCount: int32(count),
+	}, nil
+}
+
+func (s *SweepServer) TerminateSweep(ctx context.Context, req *api.TerminateSweepRequest) (*api.Sweep, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	sweep, err := client.TerminateSweep(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiSweep(sweep), nil
+}
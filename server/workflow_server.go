@@ -11,9 +11,10 @@ import (
 	"github.com/onepanelio/core/server/converter"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sort"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
@@ -25,6 +26,9 @@ import (
 	requestSort "github.com/onepanelio/core/pkg/util/request/sort"
 )
 
+// artifactChunkSize is the size of the chunks artifacts are streamed in
+const artifactChunkSize = 512 * 1024
+
 // WorkflowServer is an implementation of the grpc WorkflowServer
 type WorkflowServer struct {
 	api.UnimplementedWorkflowServiceServer
@@ -230,6 +234,51 @@ func (s *WorkflowServer) WatchWorkflowExecution(req *api.WatchWorkflowExecutionR
 	return nil
 }
 
+// WatchWorkflowExecutions streams creation, phase change and deletion events for the workflow executions
+// in a namespace that match the label filter.
+func (s *WorkflowServer) WatchWorkflowExecutions(req *api.WatchWorkflowExecutionsRequest, stream api.WorkflowService_WatchWorkflowExecutionsServer) error {
+	client := getClient(stream.Context())
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "watch", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return err
+	}
+
+	labelFilter, err := v1.LabelsFromString(req.Labels)
+	if err != nil {
+		return err
+	}
+
+	filter := &v1.WorkflowExecutionFilter{
+		Labels: labelFilter,
+	}
+	watcher, err := client.WatchWorkflowExecutions(req.Namespace, filter, req.ResourceVersion, stream.Context().Done())
+	if err != nil {
+		return err
+	}
+
+	webRouter, err := client.GetWebRouter()
+	if err != nil {
+		return err
+	}
+
+	for event := range watcher {
+		if event.Error != nil {
+			return event.Error
+		}
+
+		if err := stream.Send(&api.WorkflowExecutionEvent{
+			Type:              string(event.Type),
+			WorkflowExecution: apiWorkflowExecution(event.WorkflowExecution, webRouter),
+			ResourceVersion:   event.ResourceVersion,
+		}); err != nil {
+			log.Printf("Stream Send failed: %v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *WorkflowServer) GetWorkflowExecutionLogs(req *api.GetWorkflowExecutionLogsRequest, stream api.WorkflowService_GetWorkflowExecutionLogsServer) error {
 	client := getClient(stream.Context())
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
@@ -270,6 +319,75 @@ func (s *WorkflowServer) GetWorkflowExecutionLogs(req *api.GetWorkflowExecutionL
 	return nil
 }
 
+// parseLogTime parses an optional RFC3339 timestamp of a log time window
+func parseLogTime(value, name string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid "+name+", expected a RFC3339 timestamp.")
+	}
+
+	return &result, nil
+}
+
+// GetMergedWorkflowExecutionLogs streams the logs of all the pods and containers of a workflow execution, ordered by timestamp
+func (s *WorkflowServer) GetMergedWorkflowExecutionLogs(req *api.GetMergedWorkflowExecutionLogsRequest, stream api.WorkflowService_GetMergedWorkflowExecutionLogsServer) error {
+	client := getClient(stream.Context())
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return err
+	}
+
+	if req.Tail < 0 {
+		return util.NewUserError(codes.InvalidArgument, "Tail can not be negative.")
+	}
+	since, err := parseLogTime(req.Since, "since")
+	if err != nil {
+		return err
+	}
+	until, err := parseLogTime(req.Until, "until")
+	if err != nil {
+		return err
+	}
+
+	watcher, err := client.GetMergedWorkflowExecutionLogs(req.Namespace, req.Uid, &v1.WorkflowExecutionLogsOptions{
+		Filter:    req.Filter,
+		Regex:     req.Regex,
+		Since:     since,
+		Until:     until,
+		TailLines: int(req.Tail),
+	}, stream.Context().Done())
+	if err != nil {
+		return err
+	}
+
+	for logEntries := range watcher {
+		apiLogEntries := make([]*api.LogEntry, len(logEntries))
+		for i, item := range logEntries {
+			apiLogEntries[i] = &api.LogEntry{
+				Content:       item.Content,
+				PodName:       item.PodName,
+				ContainerName: item.ContainerName,
+			}
+
+			if item.Timestamp.After(time.Time{}) {
+				apiLogEntries[i].Timestamp = item.Timestamp.Format(time.RFC3339Nano)
+			}
+		}
+
+		if err := stream.Send(&api.LogStreamResponse{
+			LogEntries: apiLogEntries,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *WorkflowServer) GetWorkflowExecutionMetrics(ctx context.Context, req *api.GetWorkflowExecutionMetricsRequest) (*api.GetWorkflowExecutionMetricsResponse, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
@@ -278,6 +396,14 @@ func (s *WorkflowServer) GetWorkflowExecutionMetrics(ctx context.Context, req *a
 	}
 
 	metrics, err := client.GetWorkflowExecutionMetrics(req.Namespace, req.Uid, req.PodName)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	// The metric series are what learning curves are charted from
+	series, err := client.ListWorkflowExecutionMetricSeries(req.Namespace, req.Uid, &v1.ListMetricSeriesOptions{
+		MaxPoints: int(req.MaxPoints),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -290,11 +416,75 @@ func (s *WorkflowServer) GetWorkflowExecutionMetrics(ctx context.Context, req *a
 			Format: m.Format,
 		})
 	}
-	if len(apiMetrics) == 0 {
+	if len(apiMetrics) == 0 && len(series) == 0 {
 		return nil, util.NewUserError(codes.NotFound, "Metrics were not found.")
 	}
 
-	return &api.GetWorkflowExecutionMetricsResponse{Metrics: apiMetrics}, nil
+	return &api.GetWorkflowExecutionMetricsResponse{
+		Metrics: apiMetrics,
+		Series:  converter.MetricSeriesToAPI(series),
+	}, nil
+}
+
+// apiWorkflowExecutionNode converts a package workflow execution node to the api version
+func apiWorkflowExecutionNode(node *v1.WorkflowExecutionNode) *api.WorkflowExecutionNode {
+	result := &api.WorkflowExecutionNode{
+		Id:           node.NodeID,
+		Name:         node.Name,
+		DisplayName:  node.DisplayName,
+		Type:         node.Type,
+		TemplateName: node.TemplateName,
+		Phase:        string(node.Phase),
+		Message:      node.Message,
+		BoundaryId:   node.BoundaryID,
+		PodName:      node.PodName,
+		Inputs:       string(node.Inputs),
+		Outputs:      string(node.Outputs),
+	}
+
+	if node.ExitCode != nil {
+		result.ExitCode = strconv.Itoa(*node.ExitCode)
+	}
+	if node.StartedAt != nil && !node.StartedAt.IsZero() {
+		result.StartedAt = node.StartedAt.Format(time.RFC3339)
+	}
+	if node.FinishedAt != nil && !node.FinishedAt.IsZero() {
+		result.FinishedAt = node.FinishedAt.Format(time.RFC3339)
+	}
+
+	return result
+}
+
+// ListWorkflowExecutionNodes returns the nodes of a workflow execution and the edges of its graph
+func (s *WorkflowServer) ListWorkflowExecutionNodes(ctx context.Context, req *api.ListWorkflowExecutionNodesRequest) (*api.ListWorkflowExecutionNodesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	nodes, edges, err := client.ListWorkflowExecutionNodes(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	apiNodes := make([]*api.WorkflowExecutionNode, 0)
+	for _, node := range nodes {
+		apiNodes = append(apiNodes, apiWorkflowExecutionNode(node))
+	}
+
+	apiEdges := make([]*api.WorkflowExecutionNodeEdge, 0)
+	for _, edge := range edges {
+		apiEdges = append(apiEdges, &api.WorkflowExecutionNodeEdge{
+			From: edge.From,
+			To:   edge.To,
+		})
+	}
+
+	return &api.ListWorkflowExecutionNodesResponse{
+		Nodes: apiNodes,
+		Edges: apiEdges,
+	}, nil
 }
 
 // ListWorkflowExecutions returns a list of workflow executions that are specified by the criteria in the ListWorkflowExecutionsRequest
@@ -317,8 +507,9 @@ func (s *WorkflowServer) ListWorkflowExecutions(ctx context.Context, req *api.Li
 	resourceRequest := &request.Request{
 		Pagination: pagination.New(req.Page, req.PageSize),
 		Filter: v1.WorkflowExecutionFilter{
-			Labels: labelFilter,
-			Phase:  req.Phase,
+			Labels:     labelFilter,
+			Phase:      req.Phase,
+			Expression: req.Filter,
 		},
 		Sort: reqSort,
 	}
@@ -360,6 +551,38 @@ func (s *WorkflowServer) ListWorkflowExecutions(ctx context.Context, req *api.Li
 	}, nil
 }
 
+// RetryWorkflowExecution retries a failed workflow execution as a new workflow execution
+func (s *WorkflowServer) RetryWorkflowExecution(ctx context.Context, req *api.RetryWorkflowExecutionRequest) (*api.WorkflowExecution, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	opts := &v1.RetryWorkflowExecutionOptions{}
+	if req.Body != nil {
+		opts.NodeFieldSelector = req.Body.NodeFieldSelector
+		for _, param := range req.Body.Parameters {
+			opts.Parameters = append(opts.Parameters, v1.Parameter{
+				Name:  param.Name,
+				Value: ptr.String(param.Value),
+			})
+		}
+	}
+
+	wf, err := client.RetryWorkflowExecution(req.Namespace, req.Uid, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	webRouter, err := client.GetWebRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	return apiWorkflowExecution(wf, webRouter), nil
+}
+
 func (s *WorkflowServer) ResubmitWorkflowExecution(ctx context.Context, req *api.ResubmitWorkflowExecutionRequest) (*api.WorkflowExecution, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", req.Uid)
@@ -381,6 +604,70 @@ func (s *WorkflowServer) ResubmitWorkflowExecution(ctx context.Context, req *api
 	return apiWorkflowExecution(wf, webRouter), nil
 }
 
+// SuspendWorkflowExecution suspends a running workflow execution
+func (s *WorkflowServer) SuspendWorkflowExecution(ctx context.Context, req *api.SuspendWorkflowExecutionRequest) (*empty.Empty, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	if err := client.SuspendWorkflowExecution(req.Namespace, req.Uid); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ResumeWorkflowExecution resumes a suspended workflow execution
+func (s *WorkflowServer) ResumeWorkflowExecution(ctx context.Context, req *api.ResumeWorkflowExecutionRequest) (*api.WorkflowExecution, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	nodeFieldSelector := ""
+	if req.Body != nil {
+		nodeFieldSelector = req.Body.NodeFieldSelector
+	}
+
+	wf, err := client.ResumeWorkflowExecution(req.Namespace, req.Uid, nodeFieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	webRouter, err := client.GetWebRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	return apiWorkflowExecution(wf, webRouter), nil
+}
+
+// ListWorkflowExecutionSuspendNodes returns the suspend nodes of a workflow execution that are waiting to be resumed
+func (s *WorkflowServer) ListWorkflowExecutionSuspendNodes(ctx context.Context, req *api.ListWorkflowExecutionSuspendNodesRequest) (*api.ListWorkflowExecutionSuspendNodesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	nodes, err := client.ListWorkflowExecutionSuspendNodes(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	apiNodes := make([]*api.WorkflowExecutionNode, 0)
+	for _, node := range nodes {
+		apiNodes = append(apiNodes, apiWorkflowExecutionNode(node))
+	}
+
+	return &api.ListWorkflowExecutionSuspendNodesResponse{
+		Nodes: apiNodes,
+	}, nil
+}
+
 func (s *WorkflowServer) TerminateWorkflowExecution(ctx context.Context, req *api.TerminateWorkflowExecutionRequest) (*empty.Empty, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", "")
@@ -396,6 +683,100 @@ func (s *WorkflowServer) TerminateWorkflowExecution(ctx context.Context, req *ap
 	return &empty.Empty{}, nil
 }
 
+func apiQueuedWorkflowExecutions(queue []*v1.QueuedWorkflowExecution) *api.ListQueuedWorkflowExecutionsResponse {
+	result := &api.ListQueuedWorkflowExecutionsResponse{}
+	for _, queued := range queue {
+		result.QueuedWorkflowExecutions = append(result.QueuedWorkflowExecutions, &api.QueuedWorkflowExecution{
+			Position:          int32(queued.Position),
+			QueuedAt:          queued.CreatedAt.UTC().Format(time.RFC3339),
+			WorkflowExecution: apiWorkflowExecution(queued.WorkflowExecution, nil),
+		})
+	}
+
+	return result
+}
+
+func (s *WorkflowServer) ListQueuedWorkflowExecutions(ctx context.Context, req *api.ListQueuedWorkflowExecutionsRequest) (*api.ListQueuedWorkflowExecutionsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	queue, err := client.ListQueuedWorkflowExecutions(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiQueuedWorkflowExecutions(queue), nil
+}
+
+func (s *WorkflowServer) MoveQueuedWorkflowExecution(ctx context.Context, req *api.MoveQueuedWorkflowExecutionRequest) (*api.ListQueuedWorkflowExecutionsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	queue, err := client.MoveQueuedWorkflowExecution(req.Namespace, req.Uid, int(req.Position))
+	if err != nil {
+		return nil, err
+	}
+
+	return apiQueuedWorkflowExecutions(queue), nil
+}
+
+func (s *WorkflowServer) CancelQueuedWorkflowExecution(ctx context.Context, req *api.CancelQueuedWorkflowExecutionRequest) (*empty.Empty, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	if err := client.CancelQueuedWorkflowExecution(req.Namespace, req.Uid); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *WorkflowServer) GetWorkflowExecutionRetentionReport(ctx context.Context, req *api.GetWorkflowExecutionRetentionReportRequest) (*api.GetWorkflowExecutionRetentionReportResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	items, err := client.GetWorkflowExecutionRetentionReport(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.GetWorkflowExecutionRetentionReportResponse{}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &api.WorkflowExecutionRetentionItem{
+			Uid:                 item.UID,
+			Name:                item.Name,
+			Phase:               string(item.Phase),
+			WorkflowTemplateUid: item.WorkflowTemplateUID,
+			CreatedAt:           converter.TimestampToAPIString(&item.CreatedAt),
+			FinishedAt:          converter.TimestampToAPIString(item.FinishedAt),
+			IsArchived:          item.IsArchived,
+			Action:              string(item.Action),
+			Reason:              item.Reason,
+		})
+
+		switch item.Action {
+		case v1.RetentionActionDelete:
+			resp.DeleteCount++
+		case v1.RetentionActionDeleteArtifacts:
+			resp.DeleteArtifactsCount++
+		}
+	}
+
+	return resp, nil
+}
+
 func (s *WorkflowServer) GetArtifact(ctx context.Context, req *api.GetArtifactRequest) (*api.ArtifactResponse, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
@@ -413,6 +794,153 @@ func (s *WorkflowServer) GetArtifact(ctx context.Context, req *api.GetArtifactRe
 	}, nil
 }
 
+// GetArtifactURL returns a pre-signed URL for an artifact
+func (s *WorkflowServer) GetArtifactURL(ctx context.Context, req *api.GetArtifactURLRequest) (*api.GetArtifactURLResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	artifactURL, expiresAt, err := client.GetArtifactURL(req.Namespace, req.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetArtifactURLResponse{
+		Url:       artifactURL,
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// DownloadArtifact streams an artifact in chunks, so large artifacts don't have to be loaded into memory
+func (s *WorkflowServer) DownloadArtifact(req *api.DownloadArtifactRequest, stream api.WorkflowService_DownloadArtifactServer) error {
+	client := getClient(stream.Context())
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return err
+	}
+
+	file, err := client.StatArtifact(req.Namespace, req.Key)
+	if err != nil {
+		return err
+	}
+
+	offset := req.Offset
+	if offset < 0 {
+		offset = file.Size + offset
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > 0 && offset >= file.Size {
+		return util.NewUserError(codes.OutOfRange, "Offset is past the end of the artifact.")
+	}
+
+	length := req.Length
+	if length <= 0 || offset+length > file.Size {
+		length = file.Size - offset
+	}
+
+	chunk := &api.ArtifactChunk{
+		Size:        file.Size,
+		ContentType: file.ContentType,
+		Offset:      offset,
+		Length:      length,
+	}
+	if length == 0 {
+		return stream.Send(chunk)
+	}
+
+	reader, err := client.GetArtifactRange(req.Namespace, req.Key, offset, length)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	buffer := make([]byte, artifactChunkSize)
+	for {
+		n, readErr := io.ReadFull(reader, buffer)
+		if n > 0 {
+			chunk.Data = buffer[:n]
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			chunk = &api.ArtifactChunk{}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if readErr != nil {
+			log.WithFields(log.Fields{
+				"Namespace": req.Namespace,
+				"UID":       req.Uid,
+				"Key":       req.Key,
+				"Error":     readErr.Error(),
+			}).Error("Unable to read artifact.")
+			return util.NewUserError(codes.Unknown, "Unable to read artifact.")
+		}
+	}
+}
+
+// UploadArtifact writes the streamed file into the artifact repository of the namespace.
+// The returned key can be used as the key of an input artifact.
+func (s *WorkflowServer) UploadArtifact(stream api.WorkflowService_UploadArtifactServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	client := getClient(stream.Context())
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return err
+	}
+
+	key, err := client.GetArtifactUploadKey(req.Path, req.Name)
+	if err != nil {
+		return err
+	}
+
+	size := req.Size
+	if size <= 0 {
+		size = -1
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		data := req.Data
+		for {
+			if _, err := writer.Write(data); err != nil {
+				return
+			}
+
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				writer.Close()
+				return
+			}
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			data = chunk.Data
+		}
+	}()
+
+	written, err := client.PutArtifact(req.Namespace, key, reader, size, req.ContentType)
+	reader.Close()
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&api.UploadArtifactResponse{
+		Key:  key,
+		Size: written,
+	})
+}
+
 func (s *WorkflowServer) ListFiles(ctx context.Context, req *api.ListFilesRequest) (*api.ListFilesResponse, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
@@ -420,13 +948,23 @@ func (s *WorkflowServer) ListFiles(ctx context.Context, req *api.ListFilesReques
 		return nil, err
 	}
 
-	files, err := client.ListFiles(req.Namespace, req.Path)
+	reqSort, err := requestSort.New(req.Order)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid order.")
+	}
+
+	result, err := client.ListFiles(req.Namespace, req.Path, &v1.ListFilesOptions{
+		PageSize:          int(req.PageSize),
+		ContinuationToken: req.ContinuationToken,
+		Sort:              reqSort,
+		FolderSizes:       req.FolderSizes,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	apiFiles := make([]*api.File, len(files))
-	for i, file := range files {
+	apiFiles := make([]*api.File, len(result.Files))
+	for i, file := range result.Files {
 		apiFiles[i] = &api.File{
 			Path:         file.Path,
 			Name:         file.Name,
@@ -438,22 +976,12 @@ func (s *WorkflowServer) ListFiles(ctx context.Context, req *api.ListFilesReques
 		}
 	}
 
-	sort.Slice(apiFiles, func(i, j int) bool {
-		fileI := apiFiles[i]
-		fileJ := apiFiles[j]
-
-		if fileI.Directory && !fileJ.Directory {
-			return true
-		}
-
-		return strings.Compare(fileI.Path, fileJ.Path) < 0
-	})
-
 	parentPath := v1.FilePathToParentPath(req.Path)
 
 	return &api.ListFilesResponse{
-		Files:      apiFiles,
-		ParentPath: parentPath,
+		Files:                 apiFiles,
+		ParentPath:            parentPath,
+		NextContinuationToken: result.NextContinuationToken,
 	}, nil
 }
 
@@ -524,3 +1052,286 @@ func (s *WorkflowServer) UpdateWorkflowExecutionMetrics(ctx context.Context, req
 
 	return resp, nil
 }
+
+// AddWorkflowExecutionMetricPoints adds a batch of metric points to the workflow execution identified by (namespace, uid)
+func (s *WorkflowServer) AddWorkflowExecutionMetricPoints(ctx context.Context, req *api.AddWorkflowExecutionMetricPointsRequest) (*api.WorkflowExecutionsMetricsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	points, err := converter.APIMetricPointsToCore(req.Points)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, "Invalid timestamp, expected a RFC3339 timestamp.")
+	}
+
+	metrics, err := client.AddWorkflowExecutionMetricPoints(req.Namespace, req.Uid, points)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.WorkflowExecutionsMetricsResponse{
+		Metrics: converter.MetricsToAPI(metrics),
+	}, nil
+}
+
+// ListWorkflowExecutionMetricSeries returns the downsampled series of metric points of the workflow execution identified by (namespace, uid)
+func (s *WorkflowServer) ListWorkflowExecutionMetricSeries(ctx context.Context, req *api.ListWorkflowExecutionMetricSeriesRequest) (*api.ListWorkflowExecutionMetricSeriesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	series, err := client.ListWorkflowExecutionMetricSeries(req.Namespace, req.Uid, &v1.ListMetricSeriesOptions{
+		Names:     req.Names,
+		MaxPoints: int(req.MaxPoints),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ListWorkflowExecutionMetricSeriesResponse{
+		Series: converter.MetricSeriesToAPI(series),
+	}, nil
+}
+
+// apiWorkflowExecutionComparisonRows converts parameter or label comparison rows to the api version
+func apiWorkflowExecutionComparisonRows(rows []*v1.WorkflowExecutionComparisonRow) []*api.ComparedParameter {
+	result := make([]*api.ComparedParameter, 0)
+	for _, row := range rows {
+		values := make([]*api.ComparedValue, 0)
+		for _, value := range row.Values {
+			if value == nil {
+				values = append(values, &api.ComparedValue{})
+				continue
+			}
+			values = append(values, &api.ComparedValue{Present: true, Value: *value})
+		}
+
+		result = append(result, &api.ComparedParameter{
+			Name:    row.Name,
+			Values:  values,
+			Differs: row.Differs,
+		})
+	}
+
+	return result
+}
+
+// CompareWorkflowExecutions returns a side-by-side comparison of the parameters, metrics, durations, template versions and labels
+// of workflow executions
+func (s *WorkflowServer) CompareWorkflowExecutions(ctx context.Context, req *api.CompareWorkflowExecutionsRequest) (*api.CompareWorkflowExecutionsResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	comparison, err := client.CompareWorkflowExecutions(req.Namespace, req.Uids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.CompareWorkflowExecutionsResponse{
+		WorkflowExecutions: make([]*api.ComparedWorkflowExecution, 0),
+		Parameters:         apiWorkflowExecutionComparisonRows(comparison.Parameters),
+		Metrics:            make([]*api.ComparedMetric, 0),
+		Labels:             apiWorkflowExecutionComparisonRows(comparison.Labels),
+	}
+
+	for i, we := range comparison.WorkflowExecutions {
+		item := &api.ComparedWorkflowExecution{
+			Uid:       we.UID,
+			Name:      we.Name,
+			Phase:     string(we.Phase),
+			CreatedAt: we.CreatedAt.Format(time.RFC3339),
+		}
+		if we.WorkflowTemplate != nil {
+			item.WorkflowTemplateUid = we.WorkflowTemplate.UID
+			item.WorkflowTemplateVersion = we.WorkflowTemplate.Version
+		}
+		if we.StartedAt != nil && !we.StartedAt.IsZero() {
+			item.StartedAt = we.StartedAt.Format(time.RFC3339)
+		}
+		if we.FinishedAt != nil && !we.FinishedAt.IsZero() {
+			item.FinishedAt = we.FinishedAt.Format(time.RFC3339)
+		}
+		if duration := comparison.Durations[i]; duration != nil {
+			item.DurationSeconds = duration.Seconds()
+		}
+
+		resp.WorkflowExecutions = append(resp.WorkflowExecutions, item)
+	}
+
+	for _, row := range comparison.Metrics {
+		values := make([]*api.ComparedMetricValue, 0)
+		for _, value := range row.Values {
+			if value == nil {
+				values = append(values, &api.ComparedMetricValue{})
+				continue
+			}
+			values = append(values, &api.ComparedMetricValue{Present: true, Value: *value})
+		}
+
+		resp.Metrics = append(resp.Metrics, &api.ComparedMetric{
+			Name:    row.Name,
+			Values:  values,
+			Differs: row.Differs,
+		})
+	}
+
+	return resp, nil
+}
+
+// workflowExecutionBulkVerbs are the verbs a user needs on workflows for each bulk action
+var workflowExecutionBulkVerbs = map[v1.WorkflowExecutionBulkAction]string{
+	v1.WorkflowExecutionBulkActionTerminate: "update",
+	v1.WorkflowExecutionBulkActionArchive:   "delete",
+	v1.WorkflowExecutionBulkActionResubmit:  "create",
+	v1.WorkflowExecutionBulkActionLabel:     "update",
+}
+
+// BulkWorkflowExecutions applies an action to many workflow executions, and returns the result for each of them
+func (s *WorkflowServer) BulkWorkflowExecutions(ctx context.Context, req *api.BulkWorkflowExecutionsRequest) (*api.BulkWorkflowExecutionsResponse, error) {
+	action := v1.WorkflowExecutionBulkAction(req.Action)
+	verb, ok := workflowExecutionBulkVerbs[action]
+	if !ok {
+		return nil, util.NewUserError(codes.InvalidArgument, "Unknown action '"+req.Action+"'.")
+	}
+
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, verb, "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	labelFilter, err := v1.LabelsFromString(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := client.BulkWorkflowExecutions(req.Namespace, &v1.WorkflowExecutionBulkOptions{
+		Action:              action,
+		UIDs:                req.Uids,
+		WorkflowTemplateUID: req.WorkflowTemplateUid,
+		Filter: v1.WorkflowExecutionFilter{
+			Labels:     labelFilter,
+			Phase:      req.Phase,
+			Expression: req.Filter,
+		},
+		AddLabels:    converter.APIKeyValueToLabel(req.AddLabels),
+		DeleteLabels: req.DeleteLabels,
+		DryRun:       req.DryRun,
+		Parallelism:  int(req.Parallelism),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	webRouter, err := client.GetWebRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.BulkWorkflowExecutionsResponse{}
+	for _, result := range results {
+		apiResult := &api.BulkWorkflowExecutionResult{
+			Uid:     result.UID,
+			Name:    result.Name,
+			Success: result.Error == nil,
+		}
+		if result.Error != nil {
+			errStatus := status.Convert(result.Error)
+			apiResult.Code = errStatus.Code().String()
+			apiResult.Message = errStatus.Message()
+			resp.FailedCount++
+		} else {
+			resp.SucceededCount++
+		}
+		if result.WorkflowExecution != nil {
+			result.WorkflowExecution.Namespace = req.Namespace
+			apiResult.WorkflowExecution = apiWorkflowExecution(result.WorkflowExecution, webRouter)
+		}
+		if result.Labels != nil {
+			apiResult.Labels = converter.MappingToKeyValue(result.Labels)
+		}
+
+		resp.Results = append(resp.Results, apiResult)
+	}
+
+	return resp, nil
+}
+
+func apiWorkflowExecutionArtifact(artifact *v1.WorkflowExecutionArtifact) *api.WorkflowExecutionArtifact {
+	result := &api.WorkflowExecutionArtifact{
+		NodeId:    artifact.NodeID,
+		Direction: string(artifact.Direction),
+		Name:      artifact.Name,
+		Bucket:    artifact.Bucket,
+		Key:       artifact.Key,
+		CreatedAt: converter.TimestampToAPIString(&artifact.CreatedAt),
+	}
+	if artifact.WorkflowExecution != nil {
+		result.WorkflowExecution = apiWorkflowExecution(artifact.WorkflowExecution, nil)
+	}
+
+	return result
+}
+
+// GetWorkflowExecutionLineage returns the workflow executions connected to a workflow execution by the artifacts they read and wrote
+func (s *WorkflowServer) GetWorkflowExecutionLineage(ctx context.Context, req *api.GetWorkflowExecutionLineageRequest) (*api.GetWorkflowExecutionLineageResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflows", req.Uid)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	lineage, err := client.GetWorkflowExecutionLineage(req.Namespace, req.Uid, v1.LineageDirection(req.Direction), int(req.Depth))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.GetWorkflowExecutionLineageResponse{}
+	for _, artifact := range lineage.Artifacts {
+		resp.Artifacts = append(resp.Artifacts, apiWorkflowExecutionArtifact(artifact))
+	}
+	for _, node := range lineage.Nodes {
+		resp.Nodes = append(resp.Nodes, &api.WorkflowExecutionLineageNode{
+			WorkflowExecution: apiWorkflowExecution(node.WorkflowExecution, nil),
+			Depth:             int32(node.Depth),
+		})
+	}
+	for _, edge := range lineage.Edges {
+		resp.Edges = append(resp.Edges, &api.WorkflowExecutionLineageEdge{
+			From:   edge.From,
+			To:     edge.To,
+			Bucket: edge.Bucket,
+			Key:    edge.Key,
+		})
+	}
+
+	return resp, nil
+}
+
+// ListArtifactLineage returns the workflow executions that wrote or read an artifact
+func (s *WorkflowServer) ListArtifactLineage(ctx context.Context, req *api.ListArtifactLineageRequest) (*api.ListArtifactLineageResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflows", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	artifacts, err := client.ListArtifactLineage(req.Namespace, req.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListArtifactLineageResponse{}
+	for _, artifact := range artifacts {
+		resp.Artifacts = append(resp.Artifacts, apiWorkflowExecutionArtifact(artifact))
+	}
+
+	return resp, nil
+}
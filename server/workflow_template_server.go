@@ -3,12 +3,15 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	api "github.com/onepanelio/core/api/gen"
 	v1 "github.com/onepanelio/core/pkg"
+	"github.com/onepanelio/core/pkg/util"
 	"github.com/onepanelio/core/pkg/util/request"
 	"github.com/onepanelio/core/pkg/util/request/pagination"
 	"github.com/onepanelio/core/server/auth"
 	"github.com/onepanelio/core/server/converter"
+	"google.golang.org/grpc/codes"
 )
 
 // WorkflowTemplateServer is an implementation of the grpc WorkflowTemplateServer
@@ -204,6 +207,142 @@ func (s *WorkflowTemplateServer) ListWorkflowTemplateVersions(ctx context.Contex
 	}, nil
 }
 
+// apiWorkflowTemplateDiffEntries converts []*v1.WorkflowTemplateDiffEntry to []*api.WorkflowTemplateDiffEntry
+func apiWorkflowTemplateDiffEntries(entries []*v1.WorkflowTemplateDiffEntry) []*api.WorkflowTemplateDiffEntry {
+	result := make([]*api.WorkflowTemplateDiffEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &api.WorkflowTemplateDiffEntry{
+			Name:   entry.Name,
+			Change: string(entry.Change),
+			From:   entry.From,
+			To:     entry.To,
+		})
+	}
+
+	return result
+}
+
+// DiffWorkflowTemplateVersions returns what changed from one version of a workflow template to another
+func (s *WorkflowTemplateServer) DiffWorkflowTemplateVersions(ctx context.Context, req *api.DiffWorkflowTemplateVersionsRequest) (*api.WorkflowTemplateDiff, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	diff, err := client.DiffWorkflowTemplateVersions(req.Namespace, req.Uid, req.FromVersion, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.WorkflowTemplateDiff{
+		FromVersion: diff.FromVersion,
+		ToVersion:   diff.ToVersion,
+		Parameters:  apiWorkflowTemplateDiffEntries(diff.Parameters),
+		Templates:   apiWorkflowTemplateDiffEntries(diff.Templates),
+		Images:      apiWorkflowTemplateDiffEntries(diff.Images),
+		Resources:   apiWorkflowTemplateDiffEntries(diff.Resources),
+		UnifiedDiff: diff.UnifiedDiff,
+	}, nil
+}
+
+// SetLatestWorkflowTemplateVersion marks an existing version of a workflow template as the latest one
+func (s *WorkflowTemplateServer) SetLatestWorkflowTemplateVersion(ctx context.Context, req *api.SetLatestWorkflowTemplateVersionRequest) (*api.SetLatestWorkflowTemplateVersionResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+	if req.UpdateCronWorkflows {
+		allowed, err = auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "cronworkflows", "")
+		if err != nil || !allowed {
+			return nil, err
+		}
+	}
+
+	workflowTemplate, cronWorkflows, err := client.SetLatestWorkflowTemplateVersion(req.Namespace, req.Uid, req.Version, req.UpdateCronWorkflows)
+	if err != nil {
+		return nil, err
+	}
+
+	cronWorkflowNames := make([]string, 0, len(cronWorkflows))
+	for _, cronWorkflow := range cronWorkflows {
+		cronWorkflowNames = append(cronWorkflowNames, cronWorkflow.Name)
+	}
+
+	return &api.SetLatestWorkflowTemplateVersionResponse{
+		WorkflowTemplate: apiWorkflowTemplate(workflowTemplate),
+		CronWorkflows:    cronWorkflowNames,
+	}, nil
+}
+
+// ExportTemplateBundle returns a bundle with the selected workflow templates and workspace templates
+func (s *WorkflowTemplateServer) ExportTemplateBundle(ctx context.Context, req *api.ExportTemplateBundleRequest) (*api.ExportTemplateBundleResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	selections := make([]*v1.TemplateBundleSelection, 0, len(req.WorkflowTemplates))
+	for _, selection := range req.WorkflowTemplates {
+		selections = append(selections, &v1.TemplateBundleSelection{
+			UID:      selection.Uid,
+			Versions: selection.Versions,
+		})
+	}
+
+	bundle, err := client.ExportTemplateBundle(req.Namespace, selections, req.WorkspaceTemplateUids)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := bundle.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ExportTemplateBundleResponse{
+		Bundle:   data,
+		Filename: fmt.Sprintf("%v-templates-%v.tar.gz", req.Namespace, bundle.ExportedAt.Format("20060102150405")),
+	}, nil
+}
+
+// ImportTemplateBundle creates the templates of a bundle
+func (s *WorkflowTemplateServer) ImportTemplateBundle(ctx context.Context, req *api.ImportTemplateBundleRequest) (*api.ImportTemplateBundleResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	bundle, err := v1.UnmarshalTemplateBundle(req.Bundle)
+	if err != nil {
+		return nil, util.NewUserError(codes.InvalidArgument, fmt.Sprintf("Invalid bundle: %v", err))
+	}
+
+	results, err := client.ImportTemplateBundle(req.Namespace, bundle, v1.TemplateBundleCollision(req.Collision))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*api.ImportedTemplate, 0, len(results))
+	for _, result := range results {
+		templates = append(templates, &api.ImportedTemplate{
+			Kind:      result.Kind,
+			SourceUid: result.SourceUID,
+			Uid:       result.UID,
+			Name:      result.Name,
+			Collision: string(result.Collision),
+			Versions:  int32(result.Versions),
+		})
+	}
+
+	return &api.ImportTemplateBundleResponse{
+		Templates: templates,
+	}, nil
+}
+
 func (s *WorkflowTemplateServer) ListWorkflowTemplates(ctx context.Context, req *api.ListWorkflowTemplatesRequest) (*api.ListWorkflowTemplatesResponse, error) {
 	client := getClient(ctx)
 	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflowtemplates", "")
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes/empty"
+	api "github.com/onepanelio/core/api/gen"
+	v1 "github.com/onepanelio/core/pkg"
+	"github.com/onepanelio/core/server/auth"
+	"github.com/onepanelio/core/server/converter"
+)
+
+// WorkflowTemplateSourceServer is an implementation of the grpc WorkflowTemplateSourceServer
+type WorkflowTemplateSourceServer struct {
+	api.UnimplementedWorkflowTemplateSourceServiceServer
+}
+
+// NewWorkflowTemplateSourceServer creates a new WorkflowTemplateSourceServer
+func NewWorkflowTemplateSourceServer() *WorkflowTemplateSourceServer {
+	return &WorkflowTemplateSourceServer{}
+}
+
+func apiWorkflowTemplateSource(source *v1.WorkflowTemplateSource) *api.WorkflowTemplateSource {
+	return &api.WorkflowTemplateSource{
+		Uid:           source.UID,
+		Name:          source.Name,
+		Repository:    source.Repository,
+		Branch:        source.Branch,
+		Path:          source.Path,
+		SyncInterval:  source.SyncInterval,
+		LastCommitSha: source.LastCommitSHA,
+		LastSyncedAt:  converter.TimestampToAPIString(source.LastSyncedAt),
+		LastError:     source.LastError,
+		CreatedAt:     converter.TimestampToAPIString(&source.CreatedAt),
+	}
+}
+
+func apiWorkflowTemplateSourceFile(file *v1.WorkflowTemplateSourceFile) *api.WorkflowTemplateSourceFile {
+	return &api.WorkflowTemplateSourceFile{
+		Path:                file.Path,
+		ContentSha256:       file.ContentSHA256,
+		CommitSha:           file.CommitSHA,
+		WorkflowTemplateUid: file.WorkflowTemplateUID,
+		CreatedAt:           converter.TimestampToAPIString(&file.CreatedAt),
+		ModifiedAt:          converter.TimestampToAPIString(file.ModifiedAt),
+	}
+}
+
+func (s *WorkflowTemplateSourceServer) CreateWorkflowTemplateSource(ctx context.Context, req *api.CreateWorkflowTemplateSourceRequest) (*api.WorkflowTemplateSource, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "create", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	source := &v1.WorkflowTemplateSource{}
+	if req.Source != nil {
+		source.Name = req.Source.Name
+		source.Repository = req.Source.Repository
+		source.Branch = req.Source.Branch
+		source.Path = req.Source.Path
+		source.SyncInterval = req.Source.SyncInterval
+	}
+
+	source, err = client.CreateWorkflowTemplateSource(req.Namespace, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiWorkflowTemplateSource(source), nil
+}
+
+func (s *WorkflowTemplateSourceServer) GetWorkflowTemplateSource(ctx context.Context, req *api.GetWorkflowTemplateSourceRequest) (*api.WorkflowTemplateSource, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "get", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	source, err := client.GetWorkflowTemplateSource(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiWorkflowTemplateSource(source), nil
+}
+
+func (s *WorkflowTemplateSourceServer) ListWorkflowTemplateSources(ctx context.Context, req *api.ListWorkflowTemplateSourcesRequest) (*api.ListWorkflowTemplateSourcesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	sources, err := client.ListWorkflowTemplateSources(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	apiSources := make([]*api.WorkflowTemplateSource, 0, len(sources))
+	for _, source := range sources {
+		apiSources = append(apiSources, apiWorkflowTemplateSource(source))
+	}
+
+	return &api.ListWorkflowTemplateSourcesResponse{
+		Count:   int32(len(apiSources)),
+		Sources: apiSources,
+	}, nil
+}
+
+func (s *WorkflowTemplateSourceServer) DeleteWorkflowTemplateSource(ctx context.Context, req *api.DeleteWorkflowTemplateSourceRequest) (*empty.Empty, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "delete", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	if err := client.DeleteWorkflowTemplateSource(req.Namespace, req.Uid); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *WorkflowTemplateSourceServer) ListWorkflowTemplateSourceFiles(ctx context.Context, req *api.ListWorkflowTemplateSourceFilesRequest) (*api.ListWorkflowTemplateSourceFilesResponse, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "list", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	if _, err := client.GetWorkflowTemplateSource(req.Namespace, req.Uid); err != nil {
+		return nil, err
+	}
+
+	files, err := client.ListWorkflowTemplateSourceFiles(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	apiFiles := make([]*api.WorkflowTemplateSourceFile, 0, len(files))
+	for _, file := range files {
+		apiFiles = append(apiFiles, apiWorkflowTemplateSourceFile(file))
+	}
+
+	return &api.ListWorkflowTemplateSourceFilesResponse{
+		Count: int32(len(apiFiles)),
+		Files: apiFiles,
+	}, nil
+}
+
+func (s *WorkflowTemplateSourceServer) SyncWorkflowTemplateSource(ctx context.Context, req *api.SyncWorkflowTemplateSourceRequest) (*api.WorkflowTemplateSource, error) {
+	client := getClient(ctx)
+	allowed, err := auth.IsAuthorized(client, req.Namespace, "update", "argoproj.io", "workflowtemplates", "")
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	source, err := client.SyncWorkflowTemplateSource(req.Namespace, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiWorkflowTemplateSource(source), nil
+}